@@ -0,0 +1,283 @@
+package main
+
+import (
+	"crypto/rand"
+	"library-management/internal/domain"
+	"library-management/internal/infrastructure/database"
+	"library-management/internal/infrastructure/metadata"
+	"library-management/internal/infrastructure/notification"
+	"library-management/internal/usecases"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loadDatabaseConfig lê as configurações da conexão com o banco das
+// variáveis de ambiente. Variáveis não definidas usam os valores de
+// database.DefaultConfig.
+func loadDatabaseConfig() database.Config {
+	cfg := database.DefaultConfig(envString("DB_PATH", "library.db"))
+	cfg.ForeignKeys = envBool("DB_FOREIGN_KEYS", cfg.ForeignKeys)
+	cfg.JournalMode = envString("DB_JOURNAL_MODE", cfg.JournalMode)
+	cfg.Synchronous = envString("DB_SYNCHRONOUS", cfg.Synchronous)
+	cfg.BusyTimeout = envDuration("DB_BUSY_TIMEOUT", cfg.BusyTimeout)
+	cfg.MaxOpenConns = envInt("DB_MAX_OPEN_CONNS", cfg.MaxOpenConns)
+	cfg.MaxIdleConns = envInt("DB_MAX_IDLE_CONNS", cfg.MaxIdleConns)
+	cfg.ConnMaxLifetime = envDuration("DB_CONN_MAX_LIFETIME", cfg.ConnMaxLifetime)
+	return cfg
+}
+
+// loadCirculationPolicy lê das variáveis de ambiente a política padrão, usada
+// quando nenhuma política cadastrada se aplica ao usuário e ao material.
+// Variáveis não definidas usam os valores de domain.DefaultCirculationPolicy.
+func loadCirculationPolicy() domain.CirculationPolicy {
+	policy := domain.DefaultCirculationPolicy()
+	policy.LoanPeriodDays = envInt("LOAN_PERIOD_DAYS", policy.LoanPeriodDays)
+	policy.MaxLoans = envInt("LOAN_MAX_LOANS", policy.MaxLoans)
+	policy.MaxRenewals = envInt("LOAN_MAX_RENEWALS", policy.MaxRenewals)
+	policy.RenewalGraceDays = envInt("LOAN_RENEWAL_GRACE_DAYS", policy.RenewalGraceDays)
+	policy.HoldPickupDays = envInt("HOLD_PICKUP_DAYS", policy.HoldPickupDays)
+	policy.FineDailyRate = envMoney("FINE_DAILY_RATE", policy.FineDailyRate)
+	policy.FineMaxPerItem = envMoney("FINE_MAX_PER_ITEM", policy.FineMaxPerItem)
+	policy.MaxDebt = envMoney("MAX_DEBT", policy.MaxDebt)
+	return policy
+}
+
+// envInt lê uma variável de ambiente inteira, retornando def se ausente ou inválida
+func envInt(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Valor inválido para %s: %q, usando %d", key, value, def)
+		return def
+	}
+	return n
+}
+
+// envMoney lê uma variável de ambiente com valor monetário (ex.: "1.50"),
+// retornando def se ausente ou inválida
+func envMoney(key string, def domain.Money) domain.Money {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	m, err := domain.ParseMoney(value)
+	if err != nil {
+		log.Printf("Valor inválido para %s: %q, usando %s", key, value, def)
+		return def
+	}
+	return m
+}
+
+// authConfig reúne as configurações de autenticação
+type authConfig struct {
+	jwtSecret     []byte
+	accessTTL     time.Duration
+	refreshTTL    time.Duration
+	bcryptCost    int
+	adminName     string
+	adminEmail    string
+	adminPassword string
+}
+
+// loadAuthConfig lê as configurações de autenticação das variáveis de ambiente.
+// Sem JWT_SECRET, é gerado um segredo aleatório e as sessões não sobrevivem
+// a um reinício do servidor.
+func loadAuthConfig() authConfig {
+	cfg := authConfig{
+		jwtSecret:     []byte(os.Getenv("JWT_SECRET")),
+		accessTTL:     envDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		refreshTTL:    envDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		bcryptCost:    envInt("BCRYPT_COST", 12),
+		adminName:     envString("ADMIN_NAME", "Administrador"),
+		adminEmail:    os.Getenv("ADMIN_EMAIL"),
+		adminPassword: os.Getenv("ADMIN_PASSWORD"),
+	}
+
+	if len(cfg.jwtSecret) == 0 {
+		log.Println("JWT_SECRET não definido, usando um segredo aleatório; os logins não sobrevivem a um reinício")
+		cfg.jwtSecret = make([]byte, 32)
+		if _, err := rand.Read(cfg.jwtSecret); err != nil {
+			log.Fatal("Erro ao gerar segredo JWT:", err)
+		}
+	}
+
+	return cfg
+}
+
+// envString lê uma variável de ambiente, retornando def se ausente
+func envString(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// envDuration lê uma variável de ambiente com uma duração (ex.: "15m", "720h"),
+// retornando def se ausente ou inválida
+func envDuration(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Valor inválido para %s: %q, usando %s", key, value, def)
+		return def
+	}
+	return d
+}
+
+// loadMetadataProvider monta as fontes de dados bibliográficos a partir das
+// variáveis de ambiente. METADATA_PROVIDERS lista as fontes, em ordem de
+// consulta: "openlibrary", "googlebooks" e "file" (arquivo JSON indicado em
+// METADATA_FILE). As respostas ficam guardadas por METADATA_CACHE_TTL.
+func loadMetadataProvider(cache domain.MetadataCacheRepository) domain.MetadataProvider {
+	client := metadata.NewHTTPClient(envDuration("METADATA_TIMEOUT", metadata.DefaultTimeout))
+
+	var providers []domain.MetadataProvider
+	for _, name := range strings.Split(envString("METADATA_PROVIDERS", "openlibrary,googlebooks"), ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "openlibrary":
+			providers = append(providers, metadata.NewOpenLibraryProvider(client, os.Getenv("OPEN_LIBRARY_URL")))
+		case "googlebooks":
+			providers = append(providers, metadata.NewGoogleBooksProvider(client, os.Getenv("GOOGLE_BOOKS_URL"), os.Getenv("GOOGLE_BOOKS_API_KEY")))
+		case "file":
+			provider, err := metadata.NewFileProvider(os.Getenv("METADATA_FILE"))
+			if err != nil {
+				log.Fatal("Erro ao carregar METADATA_FILE:", err)
+			}
+			providers = append(providers, provider)
+		case "":
+		default:
+			log.Printf("Fonte de dados bibliográficos desconhecida em METADATA_PROVIDERS: %q", name)
+		}
+	}
+
+	return metadata.NewCachingProvider(metadata.NewChainProvider(providers...), cache,
+		envDuration("METADATA_CACHE_TTL", 30*24*time.Hour))
+}
+
+// envBool lê uma variável de ambiente booleana, retornando def se ausente ou inválida
+func envBool(key string, def bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Valor inválido para %s: %q, usando %t", key, value, def)
+		return def
+	}
+	return b
+}
+
+// envSchedule lê uma variável de ambiente com uma expressão de agendamento
+// (ex.: "0 2 * * *"), usando def se ausente ou inválida
+func envSchedule(key, def string) *domain.CronSchedule {
+	value := envString(key, def)
+	schedule, err := domain.ParseSchedule(value)
+	if err != nil {
+		log.Printf("Valor inválido para %s: %q, usando %q", key, value, def)
+		schedule, _ = domain.ParseSchedule(def)
+	}
+	return schedule
+}
+
+// schedulerConfig reúne as configurações das tarefas agendadas
+type schedulerConfig struct {
+	enabled               bool
+	lockTimeout           time.Duration
+	overdueSchedule       *domain.CronSchedule
+	holdsSchedule         *domain.CronSchedule
+	notificationsSchedule *domain.CronSchedule
+	purgeSchedule         *domain.CronSchedule
+	// deletedRetentionDays é por quantos dias os livros e usuários removidos
+	// podem ser restaurados antes do expurgo
+	deletedRetentionDays int
+}
+
+// loadSchedulerConfig lê as configurações do agendador das variáveis de
+// ambiente. Com SCHEDULER_ENABLED=false as tarefas só rodam sob demanda.
+func loadSchedulerConfig() schedulerConfig {
+	return schedulerConfig{
+		enabled:               envBool("SCHEDULER_ENABLED", true),
+		lockTimeout:           envDuration("JOB_LOCK_TIMEOUT", time.Hour),
+		overdueSchedule:       envSchedule("JOB_OVERDUE_SCHEDULE", "0 2 * * *"),
+		holdsSchedule:         envSchedule("JOB_HOLDS_SCHEDULE", "@hourly"),
+		notificationsSchedule: envSchedule("JOB_NOTIFICATIONS_SCHEDULE", "*/5 * * * *"),
+		purgeSchedule:         envSchedule("JOB_PURGE_SCHEDULE", "0 3 * * *"),
+		deletedRetentionDays:  envInt("DELETED_RETENTION_DAYS", 90),
+	}
+}
+
+// loadNotificationConfig lê as configurações dos avisos aos leitores das
+// variáveis de ambiente. NOTIFICATION_LANGUAGE é o idioma das mensagens
+// ("pt-BR" ou "en").
+func loadNotificationConfig() usecases.NotificationConfig {
+	language := envString("NOTIFICATION_LANGUAGE", notification.DefaultLanguage)
+	if !notification.SupportsLanguage(language) {
+		log.Printf("Valor inválido para NOTIFICATION_LANGUAGE: %q, usando %s", language, notification.DefaultLanguage)
+		language = notification.DefaultLanguage
+	}
+	return usecases.NotificationConfig{
+		Language:    language,
+		DueSoonDays: envInt("NOTIFICATION_DUE_SOON_DAYS", 3),
+		MaxAttempts: envInt("NOTIFICATION_MAX_ATTEMPTS", 5),
+		RetryDelay:  envDuration("NOTIFICATION_RETRY_DELAY", 5*time.Minute),
+		DigestHour:  envInt("NOTIFICATION_DIGEST_HOUR", 8),
+	}
+}
+
+// loadNotifiers monta o envio de avisos de cada canal a partir das variáveis
+// de ambiente. Sem SMTP_HOST, os emails são apenas registrados no log, e sem
+// SMS_GATEWAY_URL, o mesmo vale para os SMS. Os webhooks, informados pelos
+// leitores, só são enviados a endereços públicos; o gateway de SMS,
+// configurado pelo administrador, pode estar na rede interna.
+func loadNotifiers() map[domain.NotificationChannel]domain.Notifier {
+	timeout := envDuration("NOTIFICATION_HTTP_TIMEOUT", notification.DefaultHTTPTimeout)
+	client := notification.NewHTTPClient(timeout)
+	notifiers := map[domain.NotificationChannel]domain.Notifier{
+		domain.ChannelEmail:   loadEmailNotifier(),
+		domain.ChannelWebhook: notification.NewWebhookNotifier(notification.NewPublicHTTPClient(timeout), os.Getenv("WEBHOOK_SECRET")),
+	}
+
+	if url := os.Getenv("SMS_GATEWAY_URL"); url != "" {
+		notifiers[domain.ChannelSMS] = notification.NewSMSGateway(client, url, os.Getenv("SMS_GATEWAY_TOKEN"))
+	} else {
+		log.Println("SMS_GATEWAY_URL não definido, os avisos por SMS serão apenas registrados no log")
+		notifiers[domain.ChannelSMS] = notification.NewLogNotifier(domain.ChannelSMS)
+	}
+	return notifiers
+}
+
+// loadEmailNotifier monta o envio de email por SMTP
+func loadEmailNotifier() domain.Notifier {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		log.Println("SMTP_HOST não definido, os avisos por email serão apenas registrados no log")
+		return notification.NewLogNotifier(domain.ChannelEmail)
+	}
+
+	notifier, err := notification.NewSMTPNotifier(notification.SMTPConfig{
+		Host:     host,
+		Port:     envInt("SMTP_PORT", 25),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     envString("SMTP_FROM", "Biblioteca <biblioteca@localhost>"),
+		Timeout:  envDuration("SMTP_TIMEOUT", notification.DefaultSMTPTimeout),
+	})
+	if err != nil {
+		log.Fatal("Erro ao configurar o envio de email:", err)
+	}
+	return notifier
+}
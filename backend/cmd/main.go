@@ -1,25 +1,45 @@
 package main
 
 import (
+	"context"
+	"library-management/internal/infrastructure/auth"
 	"library-management/internal/infrastructure/database"
+	"library-management/internal/infrastructure/notification"
+	"library-management/internal/infrastructure/transfer"
 	"library-management/internal/interfaces/http/handlers"
+	"library-management/internal/interfaces/http/middleware"
 	"library-management/internal/interfaces/http/routes"
 	"library-management/internal/usecases"
 	"log"
 	"os"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
 )
 
 func main() {
-	// Obter caminho do banco de dados da variável de ambiente ou usar padrão
-	dbPath := os.Getenv("DB_PATH")
-	if dbPath == "" {
-		dbPath = "library.db"
+	// Obter caminho e configurações do banco de dados das variáveis de ambiente
+	dbCfg := loadDatabaseConfig()
+
+	// Subcomando de migrações: ./main migrate up | down [n] | status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(dbCfg, os.Args[2:]); err != nil {
+			log.Fatal("Erro ao executar migrações:", err)
+		}
+		return
+	}
+
+	// Subcomandos de importação e exportação em lote:
+	// ./main import books|users <arquivo> | export books|users [arquivo]
+	if len(os.Args) > 1 && (os.Args[1] == "import" || os.Args[1] == "export") {
+		if err := runTransfer(dbCfg, os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal("Erro na transferência de dados:", err)
+		}
+		return
 	}
 
 	// Inicializar banco de dados
-	db, err := database.InitDB(dbPath)
+	db, err := database.InitDB(dbCfg)
 	if err != nil {
 		log.Fatal("Erro ao inicializar banco de dados:", err)
 	}
@@ -27,34 +47,89 @@ func main() {
 
 	// Inicializar repositórios
 	bookRepo := database.NewBookRepository(db)
+	itemRepo := database.NewItemRepository(db)
 	userRepo := database.NewUserRepository(db)
 	loanRepo := database.NewLoanRepository(db)
+	reservationRepo := database.NewReservationRepository(db)
+	accountRepo := database.NewAccountRepository(db)
+	policyRepo := database.NewPolicyRepository(db)
+	blockRepo := database.NewBlockRepository(db)
+	sessionRepo := database.NewSessionRepository(db)
+	metadataCacheRepo := database.NewMetadataCacheRepository(db)
+	reportRepo := database.NewReportRepository(db)
+	jobRepo := database.NewJobRepository(db)
+	notificationRepo := database.NewNotificationRepository(db)
+	notificationPreferenceRepo := database.NewNotificationPreferenceRepository(db)
+	auditRepo := database.NewAuditRepository(db)
+	uow := database.NewUnitOfWork(db)
 
 	// Inicializar serviços
-	bookService := usecases.NewBookService(bookRepo, loanRepo)
-	userService := usecases.NewUserService(userRepo, loanRepo)
-	loanService := usecases.NewLoanService(loanRepo, bookRepo, userRepo)
+	policy := loadCirculationPolicy()
+	bookService := usecases.NewBookService(bookRepo, loanRepo, uow, loadMetadataProvider(metadataCacheRepo))
+	itemService := usecases.NewItemService(itemRepo, bookRepo, uow, policy)
+	userService := usecases.NewUserService(userRepo, uow)
+	loanService := usecases.NewLoanService(loanRepo, bookRepo, itemRepo, userRepo, uow, policy)
+	reservationService := usecases.NewReservationService(reservationRepo, bookRepo, userRepo, uow, policy)
+	fineService := usecases.NewFineService(accountRepo, userRepo, uow, policy)
+	policyService := usecases.NewPolicyService(policyRepo, uow)
+	standingService := usecases.NewStandingService(blockRepo, userRepo, loanRepo, accountRepo, policyRepo, uow, policy)
+	transferService := usecases.NewTransferService(bookRepo, userRepo, uow, transfer.CatalogFormats(), transfer.Formats())
+	reportService := usecases.NewReportService(reportRepo, transfer.Formats())
+	notificationService := usecases.NewNotificationService(loanRepo, reservationRepo, userRepo, bookRepo, notificationRepo,
+		notificationPreferenceRepo, uow, notification.NewTemplateRenderer(), loadNotifiers(), loadNotificationConfig())
+	auditService := usecases.NewAuditService(auditRepo)
+
+	// Tarefas em segundo plano
+	schedulerCfg := loadSchedulerConfig()
+	schedulerService := usecases.NewSchedulerService(jobRepo, schedulerCfg.lockTimeout,
+		usecases.NewOverdueJob(schedulerCfg.overdueSchedule, loanService, fineService, notificationService),
+		usecases.NewHoldExpiryJob(schedulerCfg.holdsSchedule, reservationService),
+		usecases.NewNotificationJob(schedulerCfg.notificationsSchedule, notificationService),
+		usecases.NewPurgeJob(schedulerCfg.purgeSchedule, schedulerCfg.deletedRetentionDays, bookService, userService))
+	if schedulerCfg.enabled {
+		go schedulerService.Start(context.Background())
+	}
+
+	authCfg := loadAuthConfig()
+	authService := usecases.NewAuthService(userRepo, sessionRepo, uow,
+		auth.NewBcryptHasher(authCfg.bcryptCost), auth.NewJWTIssuer(authCfg.jwtSecret, authCfg.accessTTL), authCfg.refreshTTL)
+
+	// Criar o primeiro acesso, se configurado
+	if authCfg.adminEmail != "" {
+		if _, err := authService.EnsureAdmin(context.Background(), authCfg.adminName, authCfg.adminEmail, authCfg.adminPassword); err != nil {
+			log.Fatal("Erro ao criar usuário administrador:", err)
+		}
+	}
 
 	// Inicializar handlers
 	bookHandler := handlers.NewBookHandler(bookService)
+	itemHandler := handlers.NewItemHandler(itemService)
 	userHandler := handlers.NewUserHandler(userService)
 	loanHandler := handlers.NewLoanHandler(loanService)
+	reservationHandler := handlers.NewReservationHandler(reservationService)
+	fineHandler := handlers.NewFineHandler(fineService)
+	policyHandler := handlers.NewPolicyHandler(policyService)
+	standingHandler := handlers.NewStandingHandler(standingService)
+	authHandler := handlers.NewAuthHandler(authService, userService)
+	transferHandler := handlers.NewTransferHandler(transferService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	jobHandler := handlers.NewJobHandler(schedulerService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	auditHandler := handlers.NewAuditHandler(auditService)
 
 	// Inicializar Fiber app
+	// StreamRequestBody permite importar arquivos grandes sem carregá-los
+	// inteiros na memória
 	app := fiber.New(fiber.Config{
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			code := fiber.StatusInternalServerError
-			if e, ok := err.(*fiber.Error); ok {
-				code = e.Code
-			}
-			return c.Status(code).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		},
+		ErrorHandler:      middleware.ErrorHandler,
+		StreamRequestBody: true,
 	})
+	// Um panic em um handler vira uma resposta 500 em vez de derrubar o servidor
+	app.Use(recover.New())
 
 	// Configurar rotas
-	routes.SetupRoutes(app, bookHandler, userHandler, loanHandler)
+	allowedOrigins := envString("CORS_ALLOWED_ORIGINS", "http://localhost:3000")
+	routes.SetupRoutes(app, allowedOrigins, middleware.RequireAuth(authService), authHandler, bookHandler, itemHandler, userHandler, loanHandler, reservationHandler, fineHandler, policyHandler, standingHandler, transferHandler, reportHandler, jobHandler, notificationHandler, auditHandler)
 
 	// Iniciar servidor
 	log.Println("Servidor iniciado na porta 8080")
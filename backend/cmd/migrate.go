@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"library-management/internal/infrastructure/database"
+	"strconv"
+)
+
+// runMigrate executa o subcomando "migrate" (up, down [n] ou status)
+func runMigrate(dbCfg database.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: migrate up | down [n] | status")
+	}
+
+	db, err := database.Open(dbCfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migração(ões) aplicada(s)\n", count)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("número de passos inválido: %s", args[1])
+			}
+		}
+		count, err := migrator.Down(steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migração(ões) revertida(s)\n", count)
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pendente"
+			if status.Applied {
+				state = "aplicada em " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if status.Missing {
+				state += " (desconhecida por este binário)"
+			} else if !status.ChecksumOK {
+				state += " (checksum divergente)"
+			}
+			fmt.Printf("%04d %-30s %s\n", status.Version, status.Name, state)
+		}
+
+	default:
+		return fmt.Errorf("subcomando desconhecido: %s", args[0])
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"library-management/internal/domain"
+	"library-management/internal/infrastructure/database"
+	"library-management/internal/infrastructure/transfer"
+	"library-management/internal/usecases"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// mappingFlag acumula as opções -map campo:coluna, que podem ser repetidas
+type mappingFlag map[string]string
+
+func (m mappingFlag) String() string {
+	pairs := make([]string, 0, len(m))
+	for field, column := range m {
+		pairs = append(pairs, field+":"+column)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (m mappingFlag) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		field, column, ok := strings.Cut(pair, ":")
+		field, column = strings.TrimSpace(field), strings.TrimSpace(column)
+		if !ok || field == "" || column == "" {
+			return fmt.Errorf("mapeamento inválido %q; use campo:coluna", pair)
+		}
+		m[field] = column
+	}
+	return nil
+}
+
+// runTransfer executa os subcomandos de importação e exportação em lote:
+//
+//	import books|users <arquivo> [-format csv|json|marc|marcxml] [-dry-run] [-map campo:coluna]
+//	export books|users [arquivo] [-format csv|json|marc|marcxml]
+//
+// Sem -format, o formato vem da extensão do arquivo; os formatos MARC só se
+// aplicam a livros. A exportação sem arquivo é gravada na saída padrão.
+func runTransfer(dbCfg database.Config, command string, args []string) error {
+	usage := fmt.Errorf("uso: import books|users <arquivo> [-format csv|json|marc|marcxml] [-dry-run] [-map campo:coluna] | export books|users [arquivo] [-format csv|json|marc|marcxml]")
+	if len(args) == 0 || (args[0] != "books" && args[0] != "users") {
+		return usage
+	}
+	kind := args[0]
+
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	format := flags.String("format", "", "formato do arquivo (csv, json, marc ou marcxml)")
+	dryRun := flags.Bool("dry-run", false, "valida o arquivo sem gravar nada")
+	mapping := mappingFlag{}
+	flags.Var(mapping, "map", "coluna do arquivo de um campo, no formato campo:coluna")
+
+	// As opções podem vir antes ou depois do arquivo
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	path := flags.Arg(0)
+	if flags.NArg() > 0 {
+		if err := flags.Parse(flags.Args()[1:]); err != nil {
+			return err
+		}
+		if flags.NArg() > 0 {
+			return usage
+		}
+	}
+	if *format == "" {
+		*format = formatFromExtension(path)
+	}
+
+	db, err := database.InitDB(dbCfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	service := usecases.NewTransferService(database.NewBookRepository(db), database.NewUserRepository(db),
+		database.NewUnitOfWork(db), transfer.CatalogFormats(), transfer.Formats())
+
+	if command == "export" {
+		var out io.Writer = os.Stdout
+		if path != "" {
+			file, err := os.Create(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			out = file
+		}
+		if kind == "books" {
+			return service.ExportBooks(out, *format)
+		}
+		return service.ExportUsers(out, *format)
+	}
+
+	if path == "" {
+		return usage
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	options := domain.ImportOptions{Format: *format, Mapping: mapping, DryRun: *dryRun}
+	var report *domain.ImportReport
+	if kind == "books" {
+		report, err = service.ImportBooks(context.Background(), file, options)
+	} else {
+		report, err = service.ImportUsers(context.Background(), file, options)
+	}
+	if err != nil {
+		return err
+	}
+
+	printImportReport(report)
+	return nil
+}
+
+// formatFromExtension deduz o formato pela extensão do arquivo (csv por padrão)
+func formatFromExtension(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".mrc", ".marc":
+		return "marc"
+	case ".xml":
+		return "marcxml"
+	}
+	return "csv"
+}
+
+// printImportReport mostra o resumo da importação e os registros recusados
+func printImportReport(report *domain.ImportReport) {
+	if report.DryRun {
+		fmt.Println("Simulação: nenhuma alteração foi gravada")
+	}
+	fmt.Printf("%d registro(s): %d criado(s), %d atualizado(s), %d com erro\n",
+		report.Total, report.Created, report.Updated, report.Failed)
+	if len(report.Unmapped) > 0 {
+		names := make([]string, 0, len(report.Unmapped))
+		for name, count := range report.Unmapped {
+			names = append(names, fmt.Sprintf("%s (%d)", name, count))
+		}
+		sort.Strings(names)
+		fmt.Printf("Campos ignorados: %s\n", strings.Join(names, ", "))
+	}
+	for _, rowErr := range report.Errors {
+		if rowErr.Field != "" {
+			fmt.Printf("  registro %d (%s): %s\n", rowErr.Row, rowErr.Field, rowErr.Message)
+		} else {
+			fmt.Printf("  registro %d: %s\n", rowErr.Row, rowErr.Message)
+		}
+	}
+}
@@ -4,8 +4,10 @@ go 1.21
 
 require (
 	github.com/gofiber/fiber/v2 v2.52.0
+	github.com/golang-jwt/jwt/v5 v5.2.0
 	github.com/google/uuid v1.5.0
 	github.com/mattn/go-sqlite3 v1.14.18
+	golang.org/x/crypto v0.17.0
 )
 
 require (
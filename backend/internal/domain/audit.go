@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction é o tipo de alteração registrada no log de auditoria
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+	// AuditRestore é a restauração de um registro removido
+	AuditRestore AuditAction = "restore"
+	// AuditPurge é a exclusão definitiva de um registro removido
+	AuditPurge AuditAction = "purge"
+)
+
+// Entidades registradas no log de auditoria
+const (
+	AuditEntityBook                    = "book"
+	AuditEntityItem                    = "item"
+	AuditEntityUser                    = "user"
+	AuditEntityLoan                    = "loan"
+	AuditEntityReservation             = "reservation"
+	AuditEntityAccountEntry            = "account_entry"
+	AuditEntityPolicy                  = "policy"
+	AuditEntityBlock                   = "block"
+	AuditEntityCredential              = "credential"
+	AuditEntityNotificationPreferences = "notification_preferences"
+)
+
+// AuditChange é o valor de um campo antes e depois da alteração; Before é
+// nil na criação e After é nil na remoção
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEntry é um registro do log de auditoria: quem alterou o quê, quando
+// e em qual requisição. Os registros nunca são alterados nem removidos.
+type AuditEntry struct {
+	ID         uuid.UUID `json:"id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	// ActorID é o usuário autenticado que fez a alteração; nil quando ela foi
+	// feita pelo sistema (tarefas agendadas, linha de comando)
+	ActorID   *uuid.UUID  `json:"actor_id,omitempty"`
+	ActorName string      `json:"actor_name"`
+	Action    AuditAction `json:"action"`
+	Entity    string      `json:"entity"`
+	EntityID  string      `json:"entity_id"`
+	// Changes traz, por campo, os valores que mudaram
+	Changes   map[string]AuditChange `json:"changes"`
+	RequestID string                 `json:"request_id,omitempty"`
+}
+
+// AuditQuery filtra e pagina a consulta ao log de auditoria. O intervalo é
+// fechado no início e aberto no fim; nil significa sem limite.
+type AuditQuery struct {
+	Entity   string
+	EntityID string
+	ActorID  string
+	From     *time.Time
+	To       *time.Time
+	Page     Page
+}
+
+// SystemActor é o nome registrado nas alterações feitas sem usuário autenticado
+const SystemActor = "system"
+
+type requestIDKey struct{}
+
+// WithRequestID retorna um contexto que carrega o identificador da requisição
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext retorna o identificador da requisição do contexto, ou "" se não houver
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
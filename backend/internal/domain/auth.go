@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Credential guarda o hash da senha de um usuário
+type Credential struct {
+	UserID       uuid.UUID `json:"user_id"`
+	PasswordHash string    `json:"-"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Session representa um login ativo. O refresh token emitido no login é
+// guardado apenas como hash e trocado a cada renovação.
+type Session struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	TokenHash  string     `json:"-"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsActive indica se a sessão ainda pode ser usada em now
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Principal identifica o usuário autenticado em uma requisição
+type Principal struct {
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID uuid.UUID `json:"session_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      Role      `json:"role"`
+}
+
+// Can indica se o usuário autenticado tem a permissão
+func (p *Principal) Can(permission Permission) bool {
+	return p.Role.Can(permission)
+}
+
+// IsSelf indica se userID é o próprio usuário autenticado
+func (p *Principal) IsSelf(userID string) bool {
+	return p.UserID.String() == userID
+}
+
+// AuthTokens é o par de tokens entregue no login e na renovação da sessão
+type AuthTokens struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	User             *User     `json:"user"`
+}
+
+// PasswordHasher gera e confere hashes de senha
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Compare retorna erro se a senha não corresponder ao hash
+	Compare(hash, password string) error
+}
+
+// TokenIssuer emite e valida os tokens de acesso
+type TokenIssuer interface {
+	Issue(principal *Principal) (token string, expiresAt time.Time, err error)
+	// Parse valida a assinatura e a validade do token e retorna o principal
+	Parse(token string) (*Principal, error)
+}
+
+type principalKey struct{}
+
+// WithPrincipal retorna um contexto que carrega o usuário autenticado
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext retorna o usuário autenticado do contexto, ou nil se não houver
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
@@ -0,0 +1,192 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSchedule indica uma expressão de agendamento malformada
+var ErrInvalidSchedule = NewFieldError("schedule", "invalid_schedule", "expressão de agendamento inválida")
+
+// cronMacros são as abreviações aceitas no lugar dos cinco campos
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Nomes aceitos nos campos de mês e de dia da semana
+var (
+	monthNames   = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// cronField é o conjunto de valores aceitos em um campo, um bit por valor
+type cronField uint64
+
+// has indica se o valor pertence ao campo
+func (f cronField) has(value int) bool {
+	return f&(1<<uint(value)) != 0
+}
+
+// CronSchedule é um agendamento no formato do cron: minuto, hora, dia do mês,
+// mês e dia da semana (0 ou 7 = domingo), com listas (1,15), intervalos
+// (1-5), passos (*/10) e nomes (jan, mon), ou uma das abreviações @hourly,
+// @daily, @weekly, @monthly e @yearly. Os horários são os do fuso local.
+type CronSchedule struct {
+	spec                          string
+	minute, hour, dom, month, dow cronField
+	// Como no cron, se o dia do mês e o da semana forem restritos, basta
+	// que um deles coincida
+	domAny, dowAny bool
+}
+
+// ParseSchedule interpreta uma expressão de agendamento
+func ParseSchedule(spec string) (*CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	expr := spec
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, invalidSchedule(spec, fmt.Errorf("são esperados 5 campos, encontrados %d", len(fields)))
+	}
+
+	s := &CronSchedule{spec: spec, domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	var err error
+	if s.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, invalidSchedule(spec, err)
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, invalidSchedule(spec, err)
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, invalidSchedule(spec, err)
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, invalidSchedule(spec, err)
+	}
+	if s.dow, err = parseCronField(fields[4], 0, 7, weekdayNames); err != nil {
+		return nil, invalidSchedule(spec, err)
+	}
+	// O domingo pode ser escrito como 0 ou 7
+	if s.dow.has(7) {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// invalidSchedule cria o erro de agendamento inválido com o motivo
+func invalidSchedule(spec string, err error) error {
+	return ErrInvalidSchedule.WithParams(map[string]interface{}{"schedule": spec}).
+		WithCause(fmt.Errorf("agendamento %q: %w", spec, err))
+}
+
+// parseCronField interpreta um campo: uma lista separada por vírgulas de
+// "*", valores ou intervalos, cada um com passo opcional. names, se houver,
+// são os nomes dos valores a partir de min.
+func parseCronField(field string, min, max int, names []string) (cronField, error) {
+	var set cronField
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("passo inválido em %q", part)
+			}
+			step = n
+		}
+
+		low, high := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = cronValue(from, min, max, names); err != nil {
+				return 0, err
+			}
+			high = low
+			if isRange {
+				if high, err = cronValue(to, min, max, names); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// "5/10" equivale a "5-max/10"
+				high = max
+			}
+			if low > high {
+				return 0, fmt.Errorf("intervalo invertido em %q", part)
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// cronValue interpreta um valor numérico ou um nome
+func cronValue(value string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(value, name) {
+			return min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("valor %q fora do intervalo %d-%d", value, min, max)
+	}
+	return n, nil
+}
+
+// Next retorna o primeiro horário do agendamento depois de t, ou o instante
+// zero se não houver nenhum nos próximos cinco anos (ex.: 30 de fevereiro)
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.Year() + 5
+
+	for t.Year() <= limit {
+		if !s.month.has(int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.hour.has(t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !s.minute.has(t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches confere o dia do mês e o dia da semana
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	dom, dow := s.dom.has(t.Day()), s.dow.has(int(t.Weekday()))
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// String retorna a expressão como foi escrita
+func (s *CronSchedule) String() string {
+	return s.spec
+}
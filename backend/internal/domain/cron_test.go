@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		spec  string
+		valid bool
+	}{
+		{"0 2 * * *", true},
+		{"*/30 8-18 * * mon-fri", true},
+		{"0,15,30,45 * * * *", true},
+		{"5/10 * * * *", true},
+		{"0 0 1 jan,JUL *", true},
+		{"0 0 * * 7", true},
+		{" @daily ", true},
+		{"@HOURLY", true},
+		{"", false},
+		{"0 2 * *", false},
+		{"0 2 * * * *", false},
+		{"60 * * * *", false},
+		{"* 24 * * *", false},
+		{"* * 0 * *", false},
+		{"* * * 13 *", false},
+		{"* * * * 8", false},
+		{"*/0 * * * *", false},
+		{"*/x * * * *", false},
+		{"30-10 * * * *", false},
+		{"* * * foo *", false},
+		{"@every 5m", false},
+	}
+
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.spec)
+		if tt.valid {
+			if err != nil {
+				t.Errorf("ParseSchedule(%q): erro inesperado %v", tt.spec, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("ParseSchedule(%q) = %v, %v, esperado %v", tt.spec, schedule, err, ErrInvalidSchedule)
+		}
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		spec string
+		from string
+		want string
+	}{
+		{"0 2 * * *", "2026-03-10 01:59", "2026-03-10 02:00"},
+		{"0 2 * * *", "2026-03-10 02:00", "2026-03-11 02:00"},
+		{"*/15 * * * *", "2026-03-10 10:07", "2026-03-10 10:15"},
+		{"*/30 8-18 * * mon-fri", "2026-03-13 18:30", "2026-03-16 08:00"},
+		{"@monthly", "2026-12-15 00:00", "2027-01-01 00:00"},
+		{"0 0 29 feb *", "2026-03-01 00:00", "2028-02-29 00:00"},
+		{"0 0 * * 7", "2026-03-10 00:00", "2026-03-15 00:00"},
+		// Dia do mês e da semana restritos: basta um coincidir
+		{"0 0 1 * mon", "2026-03-10 00:00", "2026-03-16 00:00"},
+		{"0 0 1 * mon", "2026-03-30 00:00", "2026-04-01 00:00"},
+		{"0 0 30 feb *", "2026-01-01 00:00", ""},
+	}
+
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q): erro inesperado %v", tt.spec, err)
+		}
+		var want time.Time
+		if tt.want != "" {
+			want = at(tt.want)
+		}
+		if got := schedule.Next(at(tt.from)); !got.Equal(want) {
+			t.Errorf("%q.Next(%s) = %v, esperado %v", tt.spec, tt.from, got, want)
+		}
+	}
+}
@@ -6,42 +6,93 @@ import (
 	"github.com/google/uuid"
 )
 
-// Book representa um livro na biblioteca
+// Book representa um título (registro bibliográfico) na biblioteca.
+// A disponibilidade é calculada a partir dos exemplares (Item) do livro.
 type Book struct {
-	ID            uuid.UUID `json:"id"`
-	Title         string    `json:"title"`
-	Author        string    `json:"author"`
-	YearPublished int       `json:"year_published"`
-	ISBN          string    `json:"isbn,omitempty"`
-	IsAvailable   bool      `json:"is_available"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID              uuid.UUID `json:"id"`
+	Title           string    `json:"title"`
+	Author          string    `json:"author"`
+	YearPublished   int       `json:"year_published"`
+	ISBN            string    `json:"isbn,omitempty"`         // ISBN-13 normalizado, só com dígitos
+	ISBNDisplay     string    `json:"isbn_display,omitempty"` // ISBN como foi informado, para exibição
+	Publisher       string    `json:"publisher,omitempty"`
+	CoverURL        string    `json:"cover_url,omitempty"`
+	Subjects        []string  `json:"subjects,omitempty"`
+	MaterialType    string    `json:"material_type"`
+	IsAvailable     bool      `json:"is_available"`
+	TotalCopies     int       `json:"total_copies"`
+	AvailableCopies int       `json:"available_copies"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	// DeletedAt é quando o livro foi removido; nil se não foi
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// ItemStatus representa a situação de um exemplar
+type ItemStatus string
+
+const (
+	ItemStatusAvailable ItemStatus = "available"
+	ItemStatusOnLoan    ItemStatus = "on_loan"
+	ItemStatusOnHold    ItemStatus = "on_hold" // separado para retirada por uma reserva
+	ItemStatusLost      ItemStatus = "lost"
+	ItemStatusDamaged   ItemStatus = "damaged"
+	ItemStatusWithdrawn ItemStatus = "withdrawn"
+)
+
+// IsValid indica se o status é um dos valores conhecidos
+func (s ItemStatus) IsValid() bool {
+	switch s {
+	case ItemStatusAvailable, ItemStatusOnLoan, ItemStatusOnHold, ItemStatusLost, ItemStatusDamaged, ItemStatusWithdrawn:
+		return true
+	}
+	return false
+}
+
+// Item representa um exemplar físico de um livro
+type Item struct {
+	ID            uuid.UUID  `json:"id"`
+	BookID        uuid.UUID  `json:"book_id"`
+	Book          *Book      `json:"book,omitempty"`
+	Barcode       string     `json:"barcode"`
+	ShelfLocation string     `json:"shelf_location,omitempty"`
+	Status        ItemStatus `json:"status"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 // User representa um usuário do sistema
 type User struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Phone     string    `json:"phone,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uuid.UUID      `json:"id"`
+	Name      string         `json:"name"`
+	Email     string         `json:"email"`
+	Phone     string         `json:"phone,omitempty"`
+	Category  PatronCategory `json:"category"`
+	Role      Role           `json:"role"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	// DeletedAt é quando o usuário foi removido; nil se não foi
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
-// Loan representa um empréstimo
+// Loan representa um empréstimo de um exemplar.
+// BookID é mantido junto com ItemID para consultas e relatórios por título.
 type Loan struct {
-	ID         uuid.UUID  `json:"id"`
-	BookID     uuid.UUID  `json:"book_id"`
-	UserID     uuid.UUID  `json:"user_id"`
-	Book       *Book      `json:"book,omitempty"`
-	User       *User      `json:"user,omitempty"`
-	LoanDate   time.Time  `json:"loan_date"`
-	DueDate    time.Time  `json:"due_date"`
-	ReturnDate *time.Time `json:"return_date,omitempty"`
-	IsReturned bool       `json:"is_returned"`
-	IsOverdue  bool       `json:"is_overdue"`
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at"`
+	ID           uuid.UUID  `json:"id"`
+	ItemID       uuid.UUID  `json:"item_id"`
+	BookID       uuid.UUID  `json:"book_id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	Item         *Item      `json:"item,omitempty"`
+	Book         *Book      `json:"book,omitempty"`
+	User         *User      `json:"user,omitempty"`
+	LoanDate     time.Time  `json:"loan_date"`
+	DueDate      time.Time  `json:"due_date"`
+	ReturnDate   *time.Time `json:"return_date,omitempty"`
+	IsReturned   bool       `json:"is_returned"`
+	IsOverdue    bool       `json:"is_overdue"`
+	RenewalCount int        `json:"renewal_count"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
 }
 
 // LoanStatus representa o status de um empréstimo
@@ -53,6 +104,11 @@ const (
 	LoanStatusReturned LoanStatus = "returned"
 )
 
+// IsValid indica se o status é conhecido
+func (s LoanStatus) IsValid() bool {
+	return s == LoanStatusActive || s == LoanStatusOverdue || s == LoanStatusReturned
+}
+
 // GetStatus retorna o status atual do empréstimo
 func (l *Loan) GetStatus() LoanStatus {
 	if l.IsReturned {
@@ -63,3 +119,67 @@ func (l *Loan) GetStatus() LoanStatus {
 	}
 	return LoanStatusActive
 }
+
+// ReservationStatus representa o status de uma reserva
+type ReservationStatus string
+
+const (
+	ReservationStatusWaiting   ReservationStatus = "waiting"
+	ReservationStatusReady     ReservationStatus = "ready"
+	ReservationStatusFulfilled ReservationStatus = "fulfilled"
+	ReservationStatusCancelled ReservationStatus = "cancelled"
+	ReservationStatusExpired   ReservationStatus = "expired"
+)
+
+// Reservation representa uma reserva (hold) de um livro na fila de espera.
+// Quando um exemplar é liberado, a primeira reserva da fila passa para
+// "ready" com o exemplar separado até ExpiresAt.
+type Reservation struct {
+	ID        uuid.UUID         `json:"id"`
+	BookID    uuid.UUID         `json:"book_id"`
+	UserID    uuid.UUID         `json:"user_id"`
+	ItemID    *uuid.UUID        `json:"item_id,omitempty"`
+	Book      *Book             `json:"book,omitempty"`
+	User      *User             `json:"user,omitempty"`
+	Status    ReservationStatus `json:"status"`
+	Position  int               `json:"position,omitempty"`
+	ReadyAt   *time.Time        `json:"ready_at,omitempty"`
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// IsActive indica se a reserva ainda está na fila (aguardando ou pronta para retirada)
+func (r *Reservation) IsActive() bool {
+	return r.Status == ReservationStatusWaiting || r.Status == ReservationStatusReady
+}
+
+// AccountEntryType representa o tipo de um lançamento na conta do usuário
+type AccountEntryType string
+
+const (
+	AccountEntryCharge     AccountEntryType = "charge"
+	AccountEntryPayment    AccountEntryType = "payment"
+	AccountEntryWaiver     AccountEntryType = "waiver"
+	AccountEntryAdjustment AccountEntryType = "adjustment"
+)
+
+// AccountEntry representa um lançamento no extrato financeiro do usuário.
+// Valores positivos aumentam a dívida (multas) e negativos a reduzem
+// (pagamentos e abonos). Lançamentos nunca são alterados ou removidos.
+type AccountEntry struct {
+	ID          uuid.UUID        `json:"id"`
+	UserID      uuid.UUID        `json:"user_id"`
+	LoanID      *uuid.UUID       `json:"loan_id,omitempty"`
+	Type        AccountEntryType `json:"type"`
+	Amount      Money            `json:"amount"`
+	Description string           `json:"description,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// Account representa o saldo devedor de um usuário e seus lançamentos
+type Account struct {
+	UserID  uuid.UUID       `json:"user_id"`
+	Balance Money           `json:"balance"`
+	Entries []*AccountEntry `json:"entries"`
+}
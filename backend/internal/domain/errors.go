@@ -0,0 +1,175 @@
+package domain
+
+import "errors"
+
+// ErrorKind classifica os erros do domínio; a camada HTTP escolhe o status da
+// resposta a partir dele
+type ErrorKind string
+
+const (
+	// KindNotFound indica que o registro procurado não existe
+	KindNotFound ErrorKind = "not_found"
+	// KindConflict indica que a operação conflita com o estado atual (registro
+	// duplicado, empréstimo já devolvido, livro emprestado etc.)
+	KindConflict ErrorKind = "conflict"
+	// KindValidation indica dados de entrada inválidos
+	KindValidation ErrorKind = "validation"
+	// KindForbidden indica que o usuário não tem permissão para a operação
+	KindForbidden ErrorKind = "forbidden"
+	// KindPolicyViolation indica que a operação é recusada por uma regra da
+	// biblioteca (limite de empréstimos, de renovações etc.)
+	KindPolicyViolation ErrorKind = "policy_violation"
+	// KindUnauthenticated indica credenciais ou sessão inválidas
+	KindUnauthenticated ErrorKind = "unauthenticated"
+	// KindUnavailable indica que um serviço externo necessário à operação
+	// falhou ou não respondeu
+	KindUnavailable ErrorKind = "unavailable"
+)
+
+// FieldError descreve o problema de um campo em um erro de validação
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error é um erro do domínio. Code identifica o erro de forma estável para os
+// clientes (ex.: "book_not_found") e é a chave da mensagem nos catálogos de
+// tradução; Message é o texto em português, usado quando não há tradução.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	// Params são os valores variáveis da mensagem (ex.: {"max": 5}), usados
+	// para montar a mensagem traduzida
+	Params map[string]interface{}
+	// Fields detalha os campos inválidos em erros de validação
+	Fields []FieldError
+	// Err é a causa original, quando houver
+	Err error
+}
+
+// Error retorna a mensagem para o usuário
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap retorna a causa original
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is considera iguais os erros do domínio com o mesmo código, de modo que
+// errors.Is(err, ErrBookNotFound) funciona mesmo com a causa anexada
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithCause retorna uma cópia do erro com a causa original anexada
+func (e *Error) WithCause(err error) *Error {
+	copy := *e
+	copy.Err = err
+	return &copy
+}
+
+// WithParams retorna uma cópia do erro com os valores variáveis da mensagem
+func (e *Error) WithParams(params map[string]interface{}) *Error {
+	copy := *e
+	copy.Params = params
+	return &copy
+}
+
+// NewNotFound cria um erro de registro não encontrado
+func NewNotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// NewConflict cria um erro de conflito com o estado atual
+func NewConflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// NewValidation cria um erro de validação com os detalhes dos campos
+func NewValidation(code, message string, fields ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message, Fields: fields}
+}
+
+// NewFieldError cria um erro de validação de um único campo
+func NewFieldError(field, code, message string) *Error {
+	return NewValidation(code, message, FieldError{Field: field, Code: code, Message: message})
+}
+
+// NewForbidden cria um erro de permissão
+func NewForbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+// NewPolicyViolation cria um erro de regra da biblioteca
+func NewPolicyViolation(code, message string) *Error {
+	return &Error{Kind: KindPolicyViolation, Code: code, Message: message}
+}
+
+// NewUnauthenticated cria um erro de autenticação
+func NewUnauthenticated(code, message string) *Error {
+	return &Error{Kind: KindUnauthenticated, Code: code, Message: message}
+}
+
+// NewUnavailable cria um erro de serviço externo indisponível
+func NewUnavailable(code, message string) *Error {
+	return &Error{Kind: KindUnavailable, Code: code, Message: message}
+}
+
+// KindOf retorna a classificação de err, ou "" se não for um erro do domínio
+func KindOf(err error) ErrorKind {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Kind
+	}
+	return ""
+}
+
+// Erros de registro não encontrado, retornados pelos repositórios
+var (
+	ErrBookNotFound    = NewNotFound("book_not_found", "livro não encontrado")
+	ErrItemNotFound    = NewNotFound("item_not_found", "exemplar não encontrado")
+	ErrUserNotFound    = NewNotFound("user_not_found", "usuário não encontrado")
+	ErrLoanNotFound    = NewNotFound("loan_not_found", "empréstimo não encontrado")
+	ErrHoldNotFound    = NewNotFound("hold_not_found", "reserva não encontrada")
+	ErrPolicyNotFound  = NewNotFound("policy_not_found", "política não encontrada")
+	ErrBlockNotFound   = NewNotFound("block_not_found", "bloqueio não encontrado")
+	ErrSessionNotFound = NewNotFound("session_not_found", "sessão não encontrada")
+	// ErrNotFound é usado quando o repositório não tem um erro mais específico
+	ErrNotFound = NewNotFound("not_found", "registro não encontrado")
+)
+
+// Erros de unicidade, retornados pelos serviços e pelos repositórios quando o
+// banco recusa um registro duplicado
+var (
+	ErrEmailTaken   = NewConflict("email_taken", "email já está em uso")
+	ErrBarcodeTaken = NewConflict("barcode_taken", "código de barras já está em uso")
+	ErrISBNTaken    = NewConflict("isbn_taken", "já existe um livro com este ISBN")
+	ErrPolicyExists = NewConflict("policy_exists", "já existe uma política para esta categoria e tipo de material")
+	ErrItemOnLoan   = NewConflict("item_on_loan", "exemplar já está emprestado")
+	ErrHoldExists   = NewConflict("hold_exists", "usuário já possui reserva para este livro")
+	// ErrDuplicate é usado quando o banco recusa um registro duplicado sem um erro mais específico
+	ErrDuplicate = NewConflict("duplicate", "registro já existe")
+	// ErrReferenced é usado quando o banco recusa a operação por violar uma chave estrangeira
+	ErrReferenced = NewConflict("referenced", "registro está em uso ou referencia um registro inexistente")
+)
+
+// Erros da restauração de registros removidos
+var (
+	ErrBookNotDeleted = NewConflict("book_not_deleted", "livro não está removido")
+	ErrUserNotDeleted = NewConflict("user_not_deleted", "usuário não está removido")
+	// ErrRestoreISBNTaken e ErrRestoreEmailTaken indicam que, desde a remoção,
+	// outro registro ativo passou a usar o ISBN ou o email
+	ErrRestoreISBNTaken  = NewConflict("restore_isbn_taken", "outro livro já usa o ISBN deste livro")
+	ErrRestoreEmailTaken = NewConflict("restore_email_taken", "outro usuário já usa o email deste usuário")
+)
+
+// ErrLoanReturned é retornado ao devolver ou renovar um empréstimo já devolvido
+var ErrLoanReturned = NewConflict("loan_returned", "empréstimo já foi devolvido")
+
+// ErrAccessDenied é retornado quando o papel do usuário não tem a permissão necessária
+var ErrAccessDenied = NewForbidden("forbidden", "Acesso negado")
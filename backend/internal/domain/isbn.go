@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"strings"
+)
+
+// ErrInvalidISBN indica um ISBN com formato ou dígito verificador inválido
+var ErrInvalidISBN = NewFieldError("isbn", "invalid_isbn", "ISBN inválido")
+
+// NormalizeISBN valida um ISBN-10 ou ISBN-13, com ou sem hífens e espaços, e
+// o converte em ISBN-13 só com dígitos (ex.: "85-359-0277-5" vira
+// "9788535902778"). O dígito verificador é conferido nos dois formatos.
+func NormalizeISBN(s string) (string, error) {
+	digits := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(s)))
+
+	switch len(digits) {
+	case 10:
+		if !isDigits(digits[:9]) || !strings.ContainsRune("0123456789X", rune(digits[9])) {
+			return "", ErrInvalidISBN
+		}
+		sum := 0
+		for i := 0; i < 10; i++ {
+			value := 10
+			if digits[i] != 'X' {
+				value = int(digits[i] - '0')
+			}
+			sum += value * (10 - i)
+		}
+		if sum%11 != 0 {
+			return "", ErrInvalidISBN
+		}
+		isbn := "978" + digits[:9]
+		return isbn + string(isbn13CheckDigit(isbn)), nil
+	case 13:
+		if !isDigits(digits) || !strings.HasPrefix(digits, "978") && !strings.HasPrefix(digits, "979") {
+			return "", ErrInvalidISBN
+		}
+		if isbn13CheckDigit(digits[:12]) != digits[12] {
+			return "", ErrInvalidISBN
+		}
+		return digits, nil
+	}
+	return "", ErrInvalidISBN
+}
+
+// isbn13CheckDigit calcula o dígito verificador dos 12 primeiros dígitos de
+// um ISBN-13 (pesos alternados 1 e 3, módulo 10)
+func isbn13CheckDigit(first12 string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(first12[i]-'0') * weight
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// isDigits informa se s contém apenas dígitos decimais
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
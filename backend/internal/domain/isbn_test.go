@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   error
+	}{
+		{"85-359-0277-5", "9788535902778", nil},
+		{"8535902775", "9788535902778", nil},
+		{"0-306-40615-2", "9780306406157", nil},
+		{"080442957X", "9780804429573", nil},
+		{"080442957x", "9780804429573", nil},
+		{"978-0-306-40615-7", "9780306406157", nil},
+		{" 978 0 306 40615 7 ", "9780306406157", nil},
+		{"9791090636071", "9791090636071", nil},
+		{"", "", ErrInvalidISBN},
+		{"85-359-0277-4", "", ErrInvalidISBN},
+		{"978-0-306-40615-8", "", ErrInvalidISBN},
+		{"9770306406156", "", ErrInvalidISBN},
+		{"X804429570", "", ErrInvalidISBN},
+		{"97803064061X7", "", ErrInvalidISBN},
+		{"12345", "", ErrInvalidISBN},
+		{"978-0-306-40615-77", "", ErrInvalidISBN},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeISBN(tt.input)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("NormalizeISBN(%q): erro = %v, esperado %v", tt.input, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NormalizeISBN(%q): erro inesperado %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeISBN(%q) = %q, esperado %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobFunc executa uma tarefa em segundo plano no instante now e retorna um
+// resumo do que foi feito, guardado no histórico
+type JobFunc func(ctx context.Context, now time.Time) (string, error)
+
+// Job é uma tarefa executada pelo agendador nos horários de Schedule ou sob demanda
+type Job struct {
+	Name        string
+	Description string
+	Schedule    *CronSchedule
+	Run         JobFunc
+}
+
+// JobTrigger indica o que iniciou uma execução
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "schedule"
+	JobTriggerManual   JobTrigger = "manual"
+)
+
+// JobStatus é a situação de uma execução
+type JobStatus string
+
+const (
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// JobRun é uma execução de uma tarefa, guardada no histórico
+type JobRun struct {
+	ID      uuid.UUID  `json:"id"`
+	Job     string     `json:"job"`
+	Trigger JobTrigger `json:"trigger"`
+	// ScheduledFor é o horário do agendamento atendido; nil nas execuções manuais
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"`
+	Status       JobStatus  `json:"status"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	Result       string     `json:"result,omitempty"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// JobInfo descreve uma tarefa cadastrada no agendador
+type JobInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Schedule    string `json:"schedule"`
+	// NextRun é o próximo horário agendado; nil se o agendador estiver desligado
+	NextRun *time.Time `json:"next_run,omitempty"`
+	LastRun *JobRun    `json:"last_run,omitempty"`
+}
+
+// Erros do agendador
+var (
+	ErrJobNotFound = NewNotFound("job_not_found", "tarefa não encontrada")
+	ErrJobRunning  = NewConflict("job_running", "a tarefa já está em execução")
+)
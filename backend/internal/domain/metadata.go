@@ -0,0 +1,27 @@
+package domain
+
+// BookMetadata são os dados bibliográficos de um livro obtidos de uma fonte
+// externa (Open Library, Google Books etc.) para preencher o cadastro
+type BookMetadata struct {
+	ISBN          string   `json:"isbn"`
+	Title         string   `json:"title"`
+	Authors       []string `json:"authors"`
+	YearPublished int      `json:"year_published,omitempty"`
+	Publisher     string   `json:"publisher,omitempty"`
+	CoverURL      string   `json:"cover_url,omitempty"`
+	// Source identifica a fonte que forneceu os dados (ex.: "openlibrary")
+	Source string `json:"source"`
+}
+
+// MetadataProvider busca os dados bibliográficos de um livro pelo ISBN-13
+// normalizado. Retorna ErrMetadataNotFound se a fonte não conhece o ISBN e
+// ErrMetadataUnavailable (com a causa anexada) se a fonte falhar.
+type MetadataProvider interface {
+	LookupISBN(isbn string) (*BookMetadata, error)
+}
+
+// Erros das fontes de dados bibliográficos
+var (
+	ErrMetadataNotFound    = NewNotFound("metadata_not_found", "nenhuma fonte de dados bibliográficos conhece este ISBN")
+	ErrMetadataUnavailable = NewUnavailable("metadata_unavailable", "fonte de dados bibliográficos indisponível")
+)
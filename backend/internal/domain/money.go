@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money representa um valor monetário em centavos (unidade mínima da moeda).
+// Todos os cálculos são feitos com inteiros para evitar erros de arredondamento.
+type Money int64
+
+// ErrInvalidMoney indica um valor monetário em formato inválido
+var ErrInvalidMoney = NewValidation("invalid_amount", "valor monetário inválido")
+
+// ErrMoneyTooLarge indica um valor que não cabe em Money
+var ErrMoneyTooLarge = NewValidation("amount_too_large", "valor monetário muito grande")
+
+// maxMoneyUnits é a maior parte inteira aceita por ParseMoney; acima dela o
+// valor em centavos estouraria o int64
+const maxMoneyUnits = (math.MaxInt64 - 99) / 100
+
+// ParseMoney converte um valor decimal como "12", "12.5", "12.50" ou "12,50" em Money.
+// Valores com mais de duas casas decimais são rejeitados em vez de arredondados.
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	s = strings.Replace(s, ",", ".", 1)
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" || len(frac) > 2 {
+		return 0, ErrInvalidMoney
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+
+	if strings.ContainsAny(whole, "+-") {
+		return 0, ErrInvalidMoney
+	}
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange || err == nil && units > maxMoneyUnits {
+		return 0, ErrMoneyTooLarge
+	}
+	if err != nil {
+		return 0, ErrInvalidMoney
+	}
+	cents, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil || strings.ContainsAny(frac, "+-") {
+		return 0, ErrInvalidMoney
+	}
+
+	value := Money(units*100 + cents)
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// String formata o valor com duas casas decimais, ex.: "12.50"
+func (m Money) String() string {
+	sign := ""
+	value := int64(m)
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, value/100, value%100)
+}
+
+// MarshalJSON serializa o valor como string decimal, ex.: "12.50"
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(m.String())), nil
+}
+
+// UnmarshalJSON aceita tanto strings ("12.50") quanto números (12.5),
+// interpretando o texto original sem passar por ponto flutuante
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	value, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = value
+	return nil
+}
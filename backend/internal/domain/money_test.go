@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Money
+		err   error
+	}{
+		{"12", 1200, nil},
+		{"12.5", 1250, nil},
+		{"12.50", 1250, nil},
+		{"12,50", 1250, nil},
+		{" 0.05 ", 5, nil},
+		{".5", 50, nil},
+		{"-3.20", -320, nil},
+		{"+3", 300, nil},
+		{"92233720368547757", 9223372036854775700, nil},
+		{"", 0, ErrInvalidMoney},
+		{".", 0, ErrInvalidMoney},
+		{"1.234", 0, ErrInvalidMoney},
+		{"abc", 0, ErrInvalidMoney},
+		{"1.-5", 0, ErrInvalidMoney},
+		{"--1", 0, ErrInvalidMoney},
+		{"1e5", 0, ErrInvalidMoney},
+		{"92233720368547758", 0, ErrMoneyTooLarge},
+		{"9223372036854775807", 0, ErrMoneyTooLarge},
+		{"99999999999999999999999", 0, ErrMoneyTooLarge},
+		{"-99999999999999999999999", 0, ErrMoneyTooLarge},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.input)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ParseMoney(%q): erro = %v, esperado %v", tt.input, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMoney(%q): erro inesperado %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, esperado %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		value Money
+		want  string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{1250, "12.50"},
+		{-320, "-3.20"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.value.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, esperado %q", int64(tt.value), got, tt.want)
+		}
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	for _, input := range []string{`"12.50"`, `12.5`, `"12,50"`} {
+		var m Money
+		if err := m.UnmarshalJSON([]byte(input)); err != nil {
+			t.Errorf("UnmarshalJSON(%s): erro inesperado %v", input, err)
+			continue
+		}
+		if m != 1250 {
+			t.Errorf("UnmarshalJSON(%s) = %d, esperado 1250", input, m)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package domain
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationKind é o tipo de aviso enviado ao leitor
+type NotificationKind string
+
+const (
+	// NotificationDueSoon avisa que o empréstimo vence em poucos dias
+	NotificationDueSoon NotificationKind = "due_soon"
+	// NotificationOverdue avisa que o empréstimo está atrasado
+	NotificationOverdue NotificationKind = "overdue"
+	// NotificationHoldReady avisa que o exemplar reservado está separado para retirada
+	NotificationHoldReady NotificationKind = "hold_ready"
+	// NotificationLoanReceipt é o comprovante de um empréstimo
+	NotificationLoanReceipt NotificationKind = "loan_receipt"
+	// NotificationDigest é o resumo diário dos avisos de quem optou por recebê-los juntos
+	NotificationDigest NotificationKind = "digest"
+)
+
+// NotificationEvents são os avisos que o leitor pode escolher receber
+var NotificationEvents = []NotificationKind{
+	NotificationDueSoon, NotificationOverdue, NotificationHoldReady, NotificationLoanReceipt,
+}
+
+// IsEvent indica se o tipo é um dos avisos que o leitor pode escolher
+func (k NotificationKind) IsEvent() bool {
+	for _, event := range NotificationEvents {
+		if k == event {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationChannel é o meio pelo qual o aviso chega ao leitor
+type NotificationChannel string
+
+const (
+	ChannelEmail NotificationChannel = "email"
+	// ChannelSMS envia o resumo do aviso para o telefone do usuário
+	ChannelSMS NotificationChannel = "sms"
+	// ChannelWebhook envia o aviso em JSON para o endereço informado pelo usuário
+	ChannelWebhook NotificationChannel = "webhook"
+)
+
+// IsValid indica se o canal é conhecido
+func (c NotificationChannel) IsValid() bool {
+	return c == ChannelEmail || c == ChannelSMS || c == ChannelWebhook
+}
+
+// NotificationStatus é a situação de um aviso na fila de envio
+type NotificationStatus string
+
+const (
+	NotificationPending NotificationStatus = "pending"
+	NotificationSending NotificationStatus = "sending"
+	NotificationSent    NotificationStatus = "sent"
+	NotificationFailed  NotificationStatus = "failed"
+	// NotificationHeld é o aviso guardado para o resumo diário
+	NotificationHeld NotificationStatus = "held"
+	// NotificationDigested é o aviso que já entrou num resumo diário
+	NotificationDigested NotificationStatus = "digested"
+)
+
+// Notification é um aviso na fila de envio (outbox). O texto é gerado ao
+// entrar na fila, e DedupKey identifica o fato avisado (ex.: o vencimento
+// de um empréstimo), de modo que o mesmo aviso não entra duas vezes no
+// mesmo canal.
+type Notification struct {
+	ID       uuid.UUID           `json:"id"`
+	UserID   uuid.UUID           `json:"user_id"`
+	Kind     NotificationKind    `json:"kind"`
+	Channel  NotificationChannel `json:"channel"`
+	DedupKey string              `json:"-"`
+	// Recipient é o email, o telefone ou o endereço do webhook, conforme o canal
+	Recipient string             `json:"recipient"`
+	Subject   string             `json:"subject"`
+	Body      string             `json:"body"`
+	Summary   string             `json:"summary"`
+	Status    NotificationStatus `json:"status"`
+	Attempts  int                `json:"attempts"`
+	LastError string             `json:"last_error,omitempty"`
+	// NextAttemptAt é quando o aviso pendente pode ser enviado (ou reenviado)
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Message é uma mensagem pronta para envio
+type Message struct {
+	// ID identifica a mensagem para o destinatário (ex.: Message-ID do email)
+	ID      string
+	Kind    NotificationKind
+	To      string
+	Subject string
+	Body    string
+	// Summary é o texto curto do aviso, usado nos canais que não comportam
+	// a mensagem completa (ex.: SMS)
+	Summary string
+}
+
+// Notifier envia mensagens aos leitores por um canal (email, SMS, webhook)
+type Notifier interface {
+	Send(message Message) error
+}
+
+// NoticeData são os dados disponíveis para os modelos de aviso
+type NoticeData struct {
+	Name   string
+	Title  string
+	Author string
+	// LoanDate e DueDate são as datas do empréstimo (avisos de empréstimo)
+	LoanDate time.Time
+	DueDate  time.Time
+	// Days é quantos dias faltam para o vencimento ou há quanto tempo ele passou
+	Days int
+	// PickupBy é o prazo para retirar a reserva (hold_ready)
+	PickupBy time.Time
+	// Items são os resumos dos avisos reunidos no resumo diário (digest)
+	Items []string
+}
+
+// RenderedNotice é o texto de um aviso: assunto, mensagem completa e um
+// resumo de uma linha
+type RenderedNotice struct {
+	Subject string
+	Body    string
+	Summary string
+}
+
+// MessageRenderer gera o texto de um aviso no idioma informado ("pt-BR" ou "en")
+type MessageRenderer interface {
+	Render(kind NotificationKind, language string, data NoticeData) (*RenderedNotice, error)
+}
+
+// QuietHours é o período do dia, no fuso do servidor, em que o leitor não
+// quer receber avisos, no formato "HH:MM". O período pode passar da
+// meia-noite (ex.: das 22:00 às 07:00).
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Validate verifica os horários do período
+func (q QuietHours) Validate() error {
+	start, errStart := parseClock(q.Start)
+	end, errEnd := parseClock(q.End)
+	if errStart != nil || errEnd != nil || start == end {
+		return NewFieldError("quiet_hours", "invalid_quiet_hours",
+			"horário de silêncio inválido: informe início e fim diferentes no formato HH:MM")
+	}
+	return nil
+}
+
+// Contains indica se t está dentro do período
+func (q QuietHours) Contains(t time.Time) bool {
+	start, _ := parseClock(q.Start)
+	end, _ := parseClock(q.End)
+	minute := t.Hour()*60 + t.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// EndAfter retorna o primeiro fim do período depois de t
+func (q QuietHours) EndAfter(t time.Time) time.Time {
+	end, _ := parseClock(q.End)
+	y, m, d := t.Date()
+	at := time.Date(y, m, d, end/60, end%60, 0, 0, t.Location())
+	if !at.After(t) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at
+}
+
+// parseClock converte "HH:MM" em minutos desde a meia-noite
+func parseClock(clock string) (int, error) {
+	hour, minute, ok := strings.Cut(clock, ":")
+	h, errHour := strconv.Atoi(hour)
+	m, errMinute := strconv.Atoi(minute)
+	if !ok || len(minute) != 2 || errHour != nil || errMinute != nil || h < 0 || h > 23 || m < 0 || m > 59 {
+		return 0, fmt.Errorf("horário inválido: %q", clock)
+	}
+	return h*60 + m, nil
+}
+
+// NotificationPreferences são as escolhas do leitor sobre os avisos: quais
+// recebe, por quais canais, em que horários e se prefere um resumo diário
+type NotificationPreferences struct {
+	UserID   uuid.UUID             `json:"user_id"`
+	Events   []NotificationKind    `json:"events"`
+	Channels []NotificationChannel `json:"channels"`
+	// WebhookURL é o endereço que recebe os avisos do canal webhook
+	WebhookURL string      `json:"webhook_url,omitempty"`
+	QuietHours *QuietHours `json:"quiet_hours,omitempty"`
+	// Digest reúne os avisos do dia numa única mensagem
+	Digest    bool       `json:"digest"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// DefaultNotificationPreferences são as preferências de quem nunca as
+// alterou: todos os avisos, por email, assim que gerados
+func DefaultNotificationPreferences(userID uuid.UUID) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:   userID,
+		Events:   append([]NotificationKind(nil), NotificationEvents...),
+		Channels: []NotificationChannel{ChannelEmail},
+	}
+}
+
+// Wants indica se o leitor quer receber o aviso
+func (p *NotificationPreferences) Wants(kind NotificationKind) bool {
+	for _, event := range p.Events {
+		if event == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate verifica os avisos, os canais, o webhook e o horário de silêncio
+func (p *NotificationPreferences) Validate() error {
+	for _, event := range p.Events {
+		if !event.IsEvent() {
+			return NewFieldError("events", "invalid_notification_event", "aviso desconhecido").
+				WithParams(map[string]interface{}{"event": event})
+		}
+	}
+	if len(p.Events) > 0 && len(p.Channels) == 0 {
+		return NewFieldError("channels", "notification_channel_required", "escolha ao menos um canal para receber os avisos")
+	}
+	for _, channel := range p.Channels {
+		if !channel.IsValid() {
+			return NewFieldError("channels", "invalid_notification_channel", "canal de aviso desconhecido").
+				WithParams(map[string]interface{}{"channel": channel})
+		}
+		if channel == ChannelWebhook {
+			if p.WebhookURL == "" {
+				return NewFieldError("webhook_url", "webhook_url_required", "endereço do webhook é obrigatório para o canal webhook")
+			}
+			u, err := url.Parse(p.WebhookURL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+				return NewFieldError("webhook_url", "invalid_webhook_url", "endereço do webhook deve ser uma URL http ou https")
+			}
+			// Nomes que apontam para a rede interna só são recusados no envio,
+			// quando o endereço é resolvido
+			host := u.Hostname()
+			if addr, err := netip.ParseAddr(host); strings.EqualFold(host, "localhost") || (err == nil && !IsPublicAddr(addr)) {
+				return NewFieldError("webhook_url", "private_webhook_url", "endereço do webhook não pode apontar para a rede interna")
+			}
+		}
+	}
+	if p.QuietHours != nil {
+		return p.QuietHours.Validate()
+	}
+	return nil
+}
+
+// nonPublicPrefixes são as faixas reservadas fora das classificações de
+// netip.Addr: rede compartilhada das operadoras (CGNAT) e redes de testes e
+// documentação
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// IsPublicAddr informa se o endereço IP é público: não é de loopback, de
+// rede privada, link-local, multicast, não especificado nem reservado. Os
+// webhooks informados pelos leitores só podem apontar para endereços públicos.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
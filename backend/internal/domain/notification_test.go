@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNotificationPreferencesWebhookURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		code string
+	}{
+		{"https://hooks.example.com/biblioteca", ""},
+		{"http://8.8.8.8:8080/aviso", ""},
+		{"http://[2606:4700::1111]/aviso", ""},
+		{"", "webhook_url_required"},
+		{"ftp://example.com/aviso", "invalid_webhook_url"},
+		{"https:///aviso", "invalid_webhook_url"},
+		{"http://localhost:8080/aviso", "private_webhook_url"},
+		{"http://LOCALHOST/aviso", "private_webhook_url"},
+		{"http://127.0.0.1/aviso", "private_webhook_url"},
+		{"http://10.0.0.5/aviso", "private_webhook_url"},
+		{"http://172.16.3.4/aviso", "private_webhook_url"},
+		{"http://192.168.1.10/aviso", "private_webhook_url"},
+		{"http://169.254.169.254/latest/meta-data", "private_webhook_url"},
+		{"http://100.64.0.1/aviso", "private_webhook_url"},
+		{"http://0.0.0.0/aviso", "private_webhook_url"},
+		{"http://[::1]/aviso", "private_webhook_url"},
+		{"http://[::ffff:127.0.0.1]/aviso", "private_webhook_url"},
+		{"http://[fd00::1]/aviso", "private_webhook_url"},
+		{"http://[fe80::1]/aviso", "private_webhook_url"},
+	}
+
+	for _, tt := range tests {
+		preferences := &NotificationPreferences{
+			Events:     []NotificationKind{NotificationOverdue},
+			Channels:   []NotificationChannel{ChannelWebhook},
+			WebhookURL: tt.url,
+		}
+		err := preferences.Validate()
+		if tt.code == "" {
+			if err != nil {
+				t.Errorf("Validate(%q): erro inesperado %v", tt.url, err)
+			}
+			continue
+		}
+		var domainErr *Error
+		if !errors.As(err, &domainErr) || domainErr.Code != tt.code {
+			t.Errorf("Validate(%q): erro = %v, esperado %s", tt.url, err, tt.code)
+		}
+	}
+}
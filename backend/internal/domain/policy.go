@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PatronCategory representa a categoria de um usuário, que determina a política de circulação
+type PatronCategory string
+
+const (
+	PatronCategoryStudent PatronCategory = "student"
+	PatronCategoryStaff   PatronCategory = "staff"
+	PatronCategoryVisitor PatronCategory = "visitor"
+)
+
+// DefaultPatronCategory é a categoria atribuída quando nenhuma é informada
+const DefaultPatronCategory = PatronCategoryStudent
+
+// IsValid indica se a categoria é um dos valores conhecidos
+func (c PatronCategory) IsValid() bool {
+	switch c {
+	case PatronCategoryStudent, PatronCategoryStaff, PatronCategoryVisitor:
+		return true
+	}
+	return false
+}
+
+// DefaultMaterialType é o tipo de material atribuído quando nenhum é informado
+const DefaultMaterialType = "book"
+
+// CirculationPolicy define as regras de circulação aplicadas aos empréstimos de
+// uma categoria de usuário. Uma política com MaterialType vazio vale para
+// qualquer tipo de material; uma com MaterialType preenchido tem prioridade
+// para aquele tipo.
+type CirculationPolicy struct {
+	ID             uuid.UUID      `json:"id"`
+	PatronCategory PatronCategory `json:"patron_category"`
+	MaterialType   string         `json:"material_type,omitempty"`
+	LoanPeriodDays int            `json:"loan_period_days"`
+	// MaxLoans é o número máximo de empréstimos simultâneos (zero = sem limite)
+	MaxLoans    int `json:"max_loans"`
+	MaxRenewals int `json:"max_renewals"`
+	// RenewalGraceDays é o atraso máximo, em dias, com que um empréstimo ainda pode ser renovado
+	RenewalGraceDays int `json:"renewal_grace_days"`
+	// HoldPickupDays é o prazo, em dias, para retirar um exemplar separado por reserva
+	HoldPickupDays int `json:"hold_pickup_days"`
+	// FineDailyRate é a multa cobrada por dia de atraso
+	FineDailyRate Money `json:"fine_daily_rate"`
+	// FineMaxPerItem é o valor máximo de multa por empréstimo (zero = sem limite)
+	FineMaxPerItem Money `json:"fine_max_per_item"`
+	// MaxDebt é o saldo devedor máximo com que o usuário ainda pode pegar livros emprestados (zero = sem limite)
+	MaxDebt   Money     `json:"max_debt"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FineFor calcula a multa de um atraso de days dias, respeitando o limite por item
+func (p CirculationPolicy) FineFor(days int) Money {
+	if days <= 0 {
+		return 0
+	}
+	fine := p.FineDailyRate * Money(days)
+	if p.FineMaxPerItem > 0 && fine > p.FineMaxPerItem {
+		fine = p.FineMaxPerItem
+	}
+	return fine
+}
+
+// DefaultCirculationPolicy retorna a política usada quando nenhuma política
+// cadastrada se aplica ao usuário e ao material
+func DefaultCirculationPolicy() CirculationPolicy {
+	return CirculationPolicy{
+		LoanPeriodDays:   14,
+		MaxLoans:         0,
+		MaxRenewals:      2,
+		RenewalGraceDays: 0,
+		HoldPickupDays:   3,
+		FineDailyRate:    100,  // R$ 1,00 por dia
+		FineMaxPerItem:   2000, // R$ 20,00 por empréstimo
+		MaxDebt:          1000, // R$ 10,00
+	}
+}
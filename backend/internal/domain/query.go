@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPageLimit é o tamanho de página usado quando o cliente não informa limit
+	DefaultPageLimit = 50
+	// MaxPageLimit é o maior tamanho de página aceito
+	MaxPageLimit = 200
+)
+
+// Page delimita a página de uma listagem
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// NewPage valida limit e offset; limit zero usa DefaultPageLimit e valores
+// acima de MaxPageLimit são reduzidos a ele
+func NewPage(limit, offset int) (Page, error) {
+	if limit < 0 {
+		return Page{}, NewFieldError("limit", "invalid_limit", "limit não pode ser negativo")
+	}
+	if offset < 0 {
+		return Page{}, NewFieldError("offset", "invalid_offset", "offset não pode ser negativo")
+	}
+	if limit == 0 {
+		limit = DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+	return Page{Limit: limit, Offset: offset}, nil
+}
+
+// SortOrder é a ordenação de uma listagem
+type SortOrder struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort interpreta a ordenação no formato "campo" ou "-campo"
+// (decrescente), aceitando apenas os campos de allowed. Se value for vazio,
+// retorna fallback.
+func ParseSort(value string, fallback SortOrder, allowed ...string) (SortOrder, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	order := SortOrder{Field: strings.TrimPrefix(value, "-"), Desc: strings.HasPrefix(value, "-")}
+	for _, field := range allowed {
+		if order.Field == field {
+			return order, nil
+		}
+	}
+	fields := strings.Join(allowed, ", ")
+	return SortOrder{}, NewFieldError("sort", "invalid_sort", "campo de ordenação inválido; use um de: "+fields).
+		WithParams(map[string]interface{}{"allowed": fields})
+}
+
+// Campos aceitos na ordenação de cada listagem
+var (
+	BookSortFields = []string{"title", "author", "year_published", "created_at"}
+	UserSortFields = []string{"name", "email", "category", "created_at"}
+	LoanSortFields = []string{"loan_date", "due_date", "return_date", "created_at"}
+)
+
+// BookQuery filtra, ordena e pagina a listagem de livros
+type BookQuery struct {
+	// Author filtra pelos livros cujo autor contém o texto
+	Author string
+	// YearFrom e YearTo limitam o ano de publicação (0 = sem limite)
+	YearFrom int
+	YearTo   int
+	// Available filtra pelos livros com (true) ou sem (false) exemplar disponível
+	Available    *bool
+	MaterialType string
+	// IncludeDeleted inclui os livros removidos e ainda não expurgados
+	IncludeDeleted bool
+	Sort           SortOrder
+	Page           Page
+}
+
+// UserQuery filtra, ordena e pagina a listagem de usuários
+type UserQuery struct {
+	// Name e Email filtram pelos usuários cujo nome ou email contém o texto
+	Name     string
+	Email    string
+	Category PatronCategory
+	Role     Role
+	// IncludeDeleted inclui os usuários removidos e ainda não expurgados
+	IncludeDeleted bool
+	Sort           SortOrder
+	Page           Page
+}
+
+// LoanQuery filtra, ordena e pagina a listagem de empréstimos. Os intervalos
+// de data são fechados no início e abertos no fim; nil significa sem limite.
+type LoanQuery struct {
+	// Status filtra pela situação; como filtro, "active" inclui os
+	// empréstimos em atraso (todos os não devolvidos)
+	Status     LoanStatus
+	UserID     string
+	BookID     string
+	LoanedFrom *time.Time
+	LoanedTo   *time.Time
+	DueFrom    *time.Time
+	DueTo      *time.Time
+	// Now é o instante usado para decidir se um empréstimo está em atraso
+	Now  time.Time
+	Sort SortOrder
+	Page Page
+}
+
+// BookSearch é uma busca textual no acervo
+type BookSearch struct {
+	// Text são os termos buscados no título, autor, ISBN e assuntos; cada
+	// termo também encontra palavras que começam com ele
+	Text string
+	Page Page
+}
+
+// BookSearchResult é um livro encontrado pela busca textual
+type BookSearchResult struct {
+	*Book
+	// Score é a relevância do livro para a busca (maior é melhor)
+	Score      float64        `json:"score"`
+	Highlights BookHighlights `json:"highlights"`
+}
+
+// BookHighlights traz os trechos do livro como HTML: o texto vem escapado e
+// os termos encontrados, entre <mark> e </mark>
+type BookHighlights struct {
+	Title   string `json:"title"`
+	Author  string `json:"author"`
+	Snippet string `json:"snippet"`
+}
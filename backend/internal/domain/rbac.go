@@ -0,0 +1,65 @@
+package domain
+
+// Role define o papel de um usuário no sistema
+type Role string
+
+const (
+	// RoleAdmin gerencia usuários e políticas, além de tudo o que a equipe faz
+	RoleAdmin Role = "admin"
+	// RoleLibrarian opera a circulação e edita o catálogo
+	RoleLibrarian Role = "librarian"
+	// RolePatron consulta o catálogo e apenas os próprios empréstimos, reservas e extrato
+	RolePatron Role = "patron"
+)
+
+// DefaultRole é o papel atribuído quando nenhum é informado
+const DefaultRole = RolePatron
+
+// IsValid indica se o papel é um dos valores conhecidos
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleAdmin, RoleLibrarian, RolePatron:
+		return true
+	}
+	return false
+}
+
+// Permission identifica uma ação protegida
+type Permission string
+
+const (
+	// PermCatalogWrite permite cadastrar, editar e remover livros e exemplares
+	PermCatalogWrite Permission = "catalog:write"
+	// PermCirculation permite emprestar, devolver, renovar, gerenciar reservas,
+	// multas e bloqueios e consultar os dados de circulação de qualquer usuário
+	PermCirculation Permission = "circulation:manage"
+	// PermUsersRead permite consultar os cadastros de todos os usuários
+	PermUsersRead Permission = "users:read"
+	// PermUsersManage permite cadastrar, editar e remover usuários e definir senhas e papéis
+	PermUsersManage Permission = "users:manage"
+	// PermPoliciesManage permite alterar as políticas de circulação
+	PermPoliciesManage Permission = "policies:manage"
+	// PermJobsManage permite consultar e disparar as tarefas agendadas
+	PermJobsManage Permission = "jobs:manage"
+	// PermAuditRead permite consultar o log de auditoria
+	PermAuditRead Permission = "audit:read"
+	// PermDeletedManage permite consultar e restaurar os livros e usuários removidos
+	PermDeletedManage Permission = "deleted:manage"
+)
+
+// rolePermissions associa cada papel às suas permissões
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin:     {PermCatalogWrite, PermCirculation, PermUsersRead, PermUsersManage, PermPoliciesManage, PermJobsManage, PermAuditRead, PermDeletedManage},
+	RoleLibrarian: {PermCatalogWrite, PermCirculation, PermUsersRead},
+	RolePatron:    {},
+}
+
+// Can indica se o papel concede a permissão
+func (r Role) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// ReportInterval é o período de agrupamento dos relatórios de circulação
+type ReportInterval string
+
+const (
+	IntervalDay   ReportInterval = "day"
+	IntervalWeek  ReportInterval = "week"
+	IntervalMonth ReportInterval = "month"
+	IntervalYear  ReportInterval = "year"
+)
+
+// IsValid verifica se o período é conhecido
+func (i ReportInterval) IsValid() bool {
+	switch i {
+	case IntervalDay, IntervalWeek, IntervalMonth, IntervalYear:
+		return true
+	}
+	return false
+}
+
+const (
+	// DefaultReportLimit é o tamanho dos rankings quando o cliente não informa limit
+	DefaultReportLimit = 10
+	// MaxReportLimit é o maior número de linhas aceito em limit
+	MaxReportLimit = 1000
+)
+
+// ReportFilter delimita um relatório de circulação. O intervalo de datas se
+// aplica à data do empréstimo, fechado no início e aberto no fim; nil
+// significa sem limite.
+type ReportFilter struct {
+	From *time.Time
+	To   *time.Time
+	// Interval agrupa os relatórios por período
+	Interval ReportInterval
+	// Limit é o número máximo de linhas; zero significa sem limite
+	Limit int
+	// Now é o instante usado para decidir se um empréstimo está em atraso
+	Now time.Time
+}
+
+// Report é o resultado de um relatório em forma de tabela: cada linha
+// associa o nome da coluna ao valor
+type Report struct {
+	Name     string                   `json:"report"`
+	From     *time.Time               `json:"from,omitempty"`
+	To       *time.Time               `json:"to,omitempty"`
+	Interval ReportInterval           `json:"interval,omitempty"`
+	Columns  []string                 `json:"columns"`
+	Rows     []map[string]interface{} `json:"rows"`
+}
+
+// Erros dos relatórios
+var (
+	ErrInvalidDateRange = NewFieldError("from", "invalid_date_range", "a data inicial deve ser anterior à final")
+	ErrInvalidInterval  = NewFieldError("interval", "invalid_interval", "período inválido; use day, week, month ou year")
+)
@@ -1,22 +1,61 @@
 package domain
 
-// BookRepository define os métodos para persistência de livros
+import "time"
+
+// BookRepository define os métodos para persistência de livros. Os livros
+// removidos (DeletedAt) ficam de fora das consultas, exceto de
+// GetByIDWithDeleted e de List com IncludeDeleted.
 type BookRepository interface {
 	Create(book *Book) error
 	GetByID(id string) (*Book, error)
-	GetAll() ([]*Book, error)
+	// GetByIDWithDeleted busca um livro pelo ID, mesmo que removido
+	GetByIDWithDeleted(id string) (*Book, error)
+	// GetByISBN busca um livro pelo ISBN-13 normalizado
+	GetByISBN(isbn string) (*Book, error)
+	// List retorna uma página dos livros filtrados e o total de livros que atendem aos filtros
+	List(query BookQuery) ([]*Book, int, error)
+	// Search retorna uma página dos livros encontrados pela busca textual, do
+	// mais ao menos relevante, e o total de livros encontrados
+	Search(search BookSearch) ([]*BookSearchResult, int, error)
+	// Each chama fn para cada livro, sem carregar todos de uma vez
+	Each(fn func(book *Book) error) error
+	// Update grava o livro, inclusive DeletedAt (remoção e restauração)
 	Update(book *Book) error
+	// Purge apaga de vez os livros removidos antes de deletedBefore que nunca
+	// foram emprestados nem reservados, com os exemplares, e retorna os ids
+	// dos livros
+	Purge(deletedBefore time.Time) ([]string, error)
+}
+
+// ItemRepository define os métodos para persistência de exemplares
+type ItemRepository interface {
+	Create(item *Item) error
+	GetByID(id string) (*Item, error)
+	GetByBarcode(barcode string) (*Item, error)
+	GetByBook(bookID string) ([]*Item, error)
+	Update(item *Item) error
 	Delete(id string) error
-	GetAvailable() ([]*Book, error)
+	GetAvailableByBook(bookID string) (*Item, error)
 }
 
-// UserRepository define os métodos para persistência de usuários
+// UserRepository define os métodos para persistência de usuários. Os
+// usuários removidos (DeletedAt) ficam de fora das consultas, exceto de
+// GetByIDWithDeleted e de List com IncludeDeleted.
 type UserRepository interface {
 	Create(user *User) error
 	GetByID(id string) (*User, error)
-	GetAll() ([]*User, error)
+	// GetByIDWithDeleted busca um usuário pelo ID, mesmo que removido
+	GetByIDWithDeleted(id string) (*User, error)
+	// List retorna uma página dos usuários filtrados e o total de usuários que atendem aos filtros
+	List(query UserQuery) ([]*User, int, error)
+	// Each chama fn para cada usuário, sem carregar todos de uma vez
+	Each(fn func(user *User) error) error
+	// Update grava o usuário, inclusive DeletedAt (remoção e restauração)
 	Update(user *User) error
-	Delete(id string) error
+	// Purge apaga de vez os usuários removidos antes de deletedBefore com a
+	// conta zerada, com tudo o que pertence a eles (empréstimos, reservas,
+	// conta, bloqueios, senha, sessões e avisos), e retorna os ids dos usuários
+	Purge(deletedBefore time.Time) ([]string, error)
 	GetByEmail(email string) (*User, error)
 }
 
@@ -24,12 +63,193 @@ type UserRepository interface {
 type LoanRepository interface {
 	Create(loan *Loan) error
 	GetByID(id string) (*Loan, error)
-	GetAll() ([]*Loan, error)
+	// List retorna uma página dos empréstimos filtrados e o total de empréstimos que atendem aos filtros
+	List(query LoanQuery) ([]*Loan, int, error)
 	Update(loan *Loan) error
 	Delete(id string) error
-	GetActiveLoans() ([]*Loan, error)
 	GetOverdueLoans() ([]*Loan, error)
 	GetLoansByUser(userID string) ([]*Loan, error)
-	GetLoansByBook(bookID string) ([]*Loan, error)
 	GetActiveLoanByBook(bookID string) (*Loan, error)
+	GetActiveLoanByItem(itemID string) (*Loan, error)
+	// MarkOverdue marca como atrasados os empréstimos não devolvidos vencidos
+	// antes de now e retorna os ids dos que foram marcados
+	MarkOverdue(now time.Time) ([]string, error)
+}
+
+// JobRepository guarda o histórico das tarefas agendadas e as reservas que
+// impedem que a mesma tarefa rode ao mesmo tempo em dois processos
+type JobRepository interface {
+	// Acquire reserva a tarefa para owner até until, se ela não estiver
+	// reservada por outro processo em now; retorna se conseguiu
+	Acquire(job, owner string, now, until time.Time) (bool, error)
+	// Extend prorroga até until a reserva de owner; retorna false se a
+	// reserva não é mais dele
+	Extend(job, owner string, until time.Time) (bool, error)
+	// Release libera a reserva de owner
+	Release(job, owner string) error
+	// CreateRun registra o início de uma execução; um agendamento
+	// (ScheduledFor) já atendido resulta em ErrDuplicate
+	CreateRun(run *JobRun) error
+	UpdateRun(run *JobRun) error
+	// ListRuns retorna uma página das execuções da tarefa, das mais recentes
+	// às mais antigas, e o total
+	ListRuns(job string, page Page) ([]*JobRun, int, error)
+	// LastRun retorna a execução mais recente da tarefa, ou nil se não houver
+	LastRun(job string) (*JobRun, error)
+	// FailInterrupted encerra como falhas as execuções que ficaram em
+	// andamento sem reserva válida em now (o processo parou no meio)
+	FailInterrupted(now time.Time) (int, error)
+}
+
+// NotificationRepository guarda a fila de avisos a enviar (outbox)
+type NotificationRepository interface {
+	// Enqueue coloca o aviso na fila; um DedupKey já usado resulta em ErrDuplicate
+	Enqueue(notification *Notification) error
+	// ClaimPending marca como em envio e retorna até limit avisos pendentes
+	// cujo horário de envio chegou em now
+	ClaimPending(now time.Time, limit int) ([]*Notification, error)
+	Update(notification *Notification) error
+	// FailSending encerra como falhas os avisos que ficaram em envio quando
+	// o processo parou, e retorna quantos eram
+	FailSending(now time.Time) (int, error)
+	// GetHeld retorna os avisos guardados para o resumo diário, dos mais antigos aos mais novos
+	GetHeld() ([]*Notification, error)
+}
+
+// NotificationPreferenceRepository guarda as preferências de aviso dos usuários
+type NotificationPreferenceRepository interface {
+	// Get retorna as preferências do usuário, ou nil se ele nunca as alterou
+	Get(userID string) (*NotificationPreferences, error)
+	// Save grava as preferências, criando-as ou substituindo as existentes
+	Save(preferences *NotificationPreferences) error
+}
+
+// AuditRepository guarda o log de auditoria, que só aceita inclusões
+type AuditRepository interface {
+	Append(entry *AuditEntry) error
+	// List retorna uma página dos registros filtrados, dos mais recentes aos
+	// mais antigos, e o total de registros que atendem aos filtros
+	List(query AuditQuery) ([]*AuditEntry, int, error)
+}
+
+// ReportRepository calcula os relatórios de circulação. Cada método
+// retorna as colunas e as linhas do relatório; o nome e o filtro são
+// preenchidos pelo serviço.
+type ReportRepository interface {
+	// LoansPerPeriod conta os empréstimos e os leitores distintos por período
+	LoansPerPeriod(filter ReportFilter) (*Report, error)
+	// TopTitles ordena os livros pelo número de empréstimos
+	TopTitles(filter ReportFilter) (*Report, error)
+	// TopAuthors ordena os autores pelo número de empréstimos
+	TopAuthors(filter ReportFilter) (*Report, error)
+	// ActivePatrons ordena os leitores pelo número de empréstimos
+	ActivePatrons(filter ReportFilter) (*Report, error)
+	// OverdueRate calcula, por período, a proporção de empréstimos atrasados
+	OverdueRate(filter ReportFilter) (*Report, error)
+	// LoanDuration calcula, por período, a duração dos empréstimos devolvidos
+	LoanDuration(filter ReportFilter) (*Report, error)
+	// NeverBorrowed lista os livros sem empréstimos no intervalo
+	NeverBorrowed(filter ReportFilter) (*Report, error)
+}
+
+// ReservationRepository define os métodos para persistência de reservas
+type ReservationRepository interface {
+	Create(reservation *Reservation) error
+	GetByID(id string) (*Reservation, error)
+	Update(reservation *Reservation) error
+	GetQueueByBook(bookID string) ([]*Reservation, error)
+	GetByUser(userID string) ([]*Reservation, error)
+	GetActiveByUserAndBook(userID, bookID string) (*Reservation, error)
+	GetExpiredReady(now time.Time) ([]*Reservation, error)
+	// GetReady retorna as reservas separadas aguardando retirada
+	GetReady() ([]*Reservation, error)
+}
+
+// AccountRepository define os métodos para persistência do extrato financeiro dos usuários
+type AccountRepository interface {
+	Create(entry *AccountEntry) error
+	GetByUser(userID string) ([]*AccountEntry, error)
+	GetBalance(userID string) (Money, error)
+	GetLoanCharges(loanID string) (Money, error)
+}
+
+// PolicyRepository define os métodos para persistência das políticas de circulação
+type PolicyRepository interface {
+	Create(policy *CirculationPolicy) error
+	GetByID(id string) (*CirculationPolicy, error)
+	GetAll() ([]*CirculationPolicy, error)
+	Update(policy *CirculationPolicy) error
+	Delete(id string) error
+	// Find retorna a política mais específica para a categoria e o tipo de material, ou nil se não houver
+	Find(category PatronCategory, materialType string) (*CirculationPolicy, error)
+}
+
+// BlockRepository define os métodos para persistência dos bloqueios manuais de usuários
+type BlockRepository interface {
+	Create(block *PatronBlock) error
+	GetByID(id string) (*PatronBlock, error)
+	Update(block *PatronBlock) error
+	GetByUser(userID string) ([]*PatronBlock, error)
+	GetActiveByUser(userID string, now time.Time) ([]*PatronBlock, error)
+}
+
+// CredentialRepository define os métodos para persistência das senhas dos usuários
+type CredentialRepository interface {
+	// Save cria ou substitui a senha do usuário
+	Save(credential *Credential) error
+	// GetByUser retorna a senha do usuário, ou nil se ele não tiver senha cadastrada
+	GetByUser(userID string) (*Credential, error)
+}
+
+// SessionRepository define os métodos para persistência das sessões de login
+type SessionRepository interface {
+	Create(session *Session) error
+	GetByID(id string) (*Session, error)
+	GetByTokenHash(tokenHash string) (*Session, error)
+	Update(session *Session) error
+	// RevokeByUser revoga todas as sessões ativas do usuário, exceto exceptID
+	RevokeByUser(userID, exceptID string, now time.Time) error
+}
+
+// MetadataCacheRepository guarda as respostas das fontes de dados
+// bibliográficos, inclusive as de ISBN desconhecido (metadata nil)
+type MetadataCacheRepository interface {
+	// Get retorna a resposta guardada para o ISBN e quando ela foi obtida, ou
+	// ErrNotFound se não houver resposta guardada
+	Get(isbn string) (*BookMetadata, time.Time, error)
+	// Put guarda (ou substitui) a resposta para o ISBN
+	Put(isbn string, metadata *BookMetadata, fetchedAt time.Time) error
+}
+
+// Repositories agrupa os repositórios disponíveis dentro de uma unidade de trabalho
+type Repositories struct {
+	Books         BookRepository
+	Items         ItemRepository
+	Users         UserRepository
+	Loans         LoanRepository
+	Reservations  ReservationRepository
+	Accounts      AccountRepository
+	Policies      PolicyRepository
+	Blocks        BlockRepository
+	Credentials   CredentialRepository
+	Sessions      SessionRepository
+	Notifications NotificationRepository
+	Preferences   NotificationPreferenceRepository
+	Audit         AuditRepository
+	// Savepoints desfaz uma parte da unidade de trabalho sem desfazer o resto
+	Savepoints Savepoints
+}
+
+// Savepoints executa trechos de uma unidade de trabalho que podem ser
+// desfeitos isoladamente
+type Savepoints interface {
+	// Do executa fn dentro de um savepoint. Se fn retornar erro, só as
+	// alterações feitas por fn são desfeitas, e o erro é retornado.
+	Do(fn func() error) error
+}
+
+// UnitOfWork executa operações de vários repositórios de forma atômica.
+// Se fn retornar erro, todas as alterações feitas através de repos são desfeitas.
+type UnitOfWork interface {
+	Do(fn func(repos Repositories) error) error
 }
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PatronBlock representa um bloqueio manual de empréstimos aplicado pela equipe
+type PatronBlock struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	Reason string    `json:"reason"`
+	// ExpiresAt é o fim do bloqueio (nil = até ser levantado manualmente)
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	LiftedAt  *time.Time `json:"lifted_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// IsActive indica se o bloqueio ainda vale em now
+func (b *PatronBlock) IsActive(now time.Time) bool {
+	if b.LiftedAt != nil {
+		return false
+	}
+	return b.ExpiresAt == nil || now.Before(*b.ExpiresAt)
+}
+
+// StandingReasonCode identifica o motivo pelo qual um usuário não pode pegar livros emprestados
+type StandingReasonCode string
+
+const (
+	StandingReasonOverdueLoans StandingReasonCode = "overdue_loans"
+	StandingReasonDebtLimit    StandingReasonCode = "debt_limit"
+	StandingReasonManualBlock  StandingReasonCode = "manual_block"
+)
+
+// StandingReason descreve um motivo de bloqueio de empréstimos
+type StandingReason struct {
+	Code    StandingReasonCode `json:"code"`
+	Message string             `json:"message"`
+	// Params são os valores variáveis da mensagem, usados para traduzi-la
+	Params map[string]interface{} `json:"-"`
+	// BlockID identifica o bloqueio manual, quando Code é manual_block
+	BlockID *uuid.UUID `json:"block_id,omitempty"`
+}
+
+// PatronStanding é a situação de um usuário perante a biblioteca
+type PatronStanding struct {
+	UserID       uuid.UUID        `json:"user_id"`
+	CanBorrow    bool             `json:"can_borrow"`
+	Reasons      []StandingReason `json:"reasons"`
+	OverdueLoans int              `json:"overdue_loans"`
+	Balance      Money            `json:"balance"`
+	MaxDebt      Money            `json:"max_debt"`
+	Blocks       []*PatronBlock   `json:"blocks"`
+}
+
+// BorrowingBlockedError é retornado quando o usuário não pode pegar livros
+// emprestados, listando todos os motivos
+type BorrowingBlockedError struct {
+	Reasons []StandingReason
+}
+
+// Error junta as mensagens de todos os motivos
+func (e *BorrowingBlockedError) Error() string {
+	messages := make([]string, len(e.Reasons))
+	for i, reason := range e.Reasons {
+		messages[i] = reason.Message
+	}
+	return "usuário impedido de pegar livros emprestados: " + strings.Join(messages, "; ")
+}
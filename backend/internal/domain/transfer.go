@@ -0,0 +1,85 @@
+package domain
+
+import "io"
+
+// ValueSeparator separa os valores dos campos que aceitam mais de um (como
+// os assuntos do livro) nos arquivos de importação e exportação
+const ValueSeparator = ";"
+
+// Record é um registro lido de um arquivo de importação, com os valores
+// indexados pelo nome do campo (já aplicado o mapeamento de colunas)
+type Record struct {
+	// Row é a posição do registro no arquivo: a linha, em CSV (o cabeçalho é
+	// a linha 1), ou a posição na lista, em JSON
+	Row    int
+	Fields map[string]string
+	// Unmapped lista os campos do registro que o formato não sabe converter
+	// (ex.: as etiquetas MARC sem correspondência em Book)
+	Unmapped []string
+}
+
+// RecordReader lê os registros de um arquivo de importação um a um, sem
+// carregar o arquivo inteiro. Read retorna io.EOF ao final do arquivo e
+// ErrInvalidFile se o arquivo estiver malformado.
+type RecordReader interface {
+	Read() (*Record, error)
+}
+
+// RecordWriter grava os registros de uma exportação, com as colunas na ordem
+// informada ao criá-lo. Close grava o final do arquivo.
+type RecordWriter interface {
+	Write(values map[string]interface{}) error
+	Close() error
+}
+
+// RecordFormat é um formato de arquivo de importação e exportação (CSV, JSON)
+type RecordFormat interface {
+	// ContentType é o tipo MIME do formato
+	ContentType() string
+	// Extension é a extensão dos arquivos do formato, sem o ponto
+	Extension() string
+	// NewReader cria um leitor; mapping associa cada campo à coluna do
+	// arquivo que o contém, quando os nomes diferem (ex.: "title" → "Título")
+	NewReader(r io.Reader, mapping map[string]string) (RecordReader, error)
+	NewWriter(w io.Writer, columns []string) RecordWriter
+}
+
+// ImportOptions configura uma importação em lote
+type ImportOptions struct {
+	// Format é o nome do formato do arquivo ("csv" ou "json")
+	Format string
+	// Mapping associa cada campo à coluna do arquivo que o contém
+	Mapping map[string]string
+	// DryRun valida e simula a importação sem gravar nada
+	DryRun bool
+}
+
+// ImportRowError descreve um registro recusado na importação
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Params são os valores variáveis da mensagem, para tradução
+	Params map[string]interface{} `json:"-"`
+}
+
+// ImportReport é o resultado de uma importação em lote. Os registros com erro
+// são ignorados e os demais, importados.
+type ImportReport struct {
+	Total   int              `json:"total"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Failed  int              `json:"failed"`
+	DryRun  bool             `json:"dry_run"`
+	Errors  []ImportRowError `json:"errors"`
+	// Unmapped conta, para cada coluna ou campo do arquivo que não
+	// corresponde a nenhum campo importável, em quantos registros ele ocorreu
+	Unmapped map[string]int `json:"unmapped"`
+}
+
+// Erros de importação e exportação
+var (
+	ErrInvalidFile   = NewValidation("invalid_file", "arquivo malformado")
+	ErrUnknownFormat = NewFieldError("format", "unknown_format", "formato de arquivo desconhecido")
+)
@@ -0,0 +1,31 @@
+package auth
+
+import "golang.org/x/crypto/bcrypt"
+
+// BcryptHasher implementa domain.PasswordHasher usando bcrypt
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher cria uma nova instância do BcryptHasher.
+// Um custo fora do intervalo aceito pelo bcrypt usa bcrypt.DefaultCost.
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{cost: cost}
+}
+
+// Hash gera o hash bcrypt da senha
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Compare retorna erro se a senha não corresponder ao hash
+func (h *BcryptHasher) Compare(hash, password string) error {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"errors"
+	"library-management/internal/domain"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// issuer identifica os tokens emitidos por esta aplicação
+const issuer = "library-management"
+
+// claims são as informações carregadas no token de acesso
+type claims struct {
+	SessionID string `json:"sid"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// JWTIssuer implementa domain.TokenIssuer com JWT assinado por HMAC-SHA256
+type JWTIssuer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewJWTIssuer cria uma nova instância do JWTIssuer
+func NewJWTIssuer(secret []byte, ttl time.Duration) *JWTIssuer {
+	return &JWTIssuer{secret: secret, ttl: ttl}
+}
+
+// Issue emite um token de acesso para o principal
+func (i *JWTIssuer) Issue(principal *domain.Principal) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(i.ttl)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		SessionID: principal.SessionID.String(),
+		Name:      principal.Name,
+		Email:     principal.Email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   principal.UserID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+
+	signed, err := token.SignedString(i.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// Parse valida a assinatura e a validade do token e retorna o principal
+func (i *JWTIssuer) Parse(token string) (*domain.Principal, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, func(*jwt.Token) (interface{}, error) {
+		return i.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return nil, errors.New("token sem usuário válido")
+	}
+	sessionID, err := uuid.Parse(c.SessionID)
+	if err != nil {
+		return nil, errors.New("token sem sessão válida")
+	}
+
+	return &domain.Principal{
+		UserID:    userID,
+		SessionID: sessionID,
+		Name:      c.Name,
+		Email:     c.Email,
+	}, nil
+}
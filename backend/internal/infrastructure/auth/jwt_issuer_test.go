@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"library-management/internal/domain"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestJWTIssuerRoundTrip(t *testing.T) {
+	issuer := NewJWTIssuer([]byte("segredo"), time.Minute)
+	principal := &domain.Principal{
+		UserID:    uuid.New(),
+		SessionID: uuid.New(),
+		Name:      "Ana",
+		Email:     "ana@example.com",
+	}
+
+	token, expiresAt, err := issuer.Issue(principal)
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if time.Until(expiresAt) <= 0 || time.Until(expiresAt) > time.Minute {
+		t.Errorf("expiração = %v, esperado em até um minuto", expiresAt)
+	}
+
+	got, err := issuer.Parse(token)
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if got.UserID != principal.UserID || got.SessionID != principal.SessionID ||
+		got.Name != principal.Name || got.Email != principal.Email {
+		t.Errorf("principal = %+v, esperado %+v", got, principal)
+	}
+}
+
+func TestJWTIssuerRejects(t *testing.T) {
+	principal := &domain.Principal{UserID: uuid.New(), SessionID: uuid.New()}
+	issuer := NewJWTIssuer([]byte("segredo"), time.Minute)
+	valid, _, err := issuer.Issue(principal)
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	expired, _, err := NewJWTIssuer([]byte("segredo"), -time.Minute).Issue(principal)
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	other, _, err := NewJWTIssuer([]byte("outro segredo"), time.Minute).Issue(principal)
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+		Issuer:    "library-management",
+		Subject:   principal.UserID.String(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	// Conteúdo do token expirado com a assinatura do token válido
+	parts, expiredParts := strings.Split(valid, "."), strings.Split(expired, ".")
+	tampered := parts[0] + "." + expiredParts[1] + "." + parts[2]
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"expirado", expired},
+		{"outro segredo", other},
+		{"sem assinatura", unsigned},
+		{"conteúdo alterado", tampered},
+		{"malformado", "abc"},
+		{"vazio", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := issuer.Parse(tt.token); err == nil {
+				t.Error("token aceito, esperado erro")
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"database/sql"
+	"library-management/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// AccountRepository implementa domain.AccountRepository usando SQLite
+type AccountRepository struct {
+	db dbExecutor
+}
+
+// NewAccountRepository cria uma nova instância do AccountRepository
+func NewAccountRepository(db *sql.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+// Create insere um novo lançamento no extrato
+func (r *AccountRepository) Create(entry *domain.AccountEntry) error {
+	entry.ID = uuid.New()
+	query := `
+		INSERT INTO account_entries (id, user_id, loan_id, type, amount, description, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.Exec(query, entry.ID.String(), entry.UserID.String(), nullableUUIDPtr(entry.LoanID),
+		entry.Type, int64(entry.Amount), entry.Description, entry.CreatedAt)
+	return translateError(err, nil)
+}
+
+// GetByUser retorna os lançamentos de um usuário, do mais recente para o mais antigo
+func (r *AccountRepository) GetByUser(userID string) ([]*domain.AccountEntry, error) {
+	query := `
+		SELECT id, user_id, loan_id, type, amount, description, created_at
+		FROM account_entries WHERE user_id = ? ORDER BY created_at DESC, rowid DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.AccountEntry
+	for rows.Next() {
+		entry := &domain.AccountEntry{}
+		var idStr, userIDStr string
+		var loanIDStr sql.NullString
+		var amount int64
+		err := rows.Scan(&idStr, &userIDStr, &loanIDStr, &entry.Type, &amount,
+			&entry.Description, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.ID, _ = uuid.Parse(idStr)
+		entry.UserID, _ = uuid.Parse(userIDStr)
+		entry.Amount = domain.Money(amount)
+		if loanIDStr.Valid {
+			if loanID, err := uuid.Parse(loanIDStr.String); err == nil {
+				entry.LoanID = &loanID
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// GetBalance retorna o saldo devedor do usuário
+func (r *AccountRepository) GetBalance(userID string) (domain.Money, error) {
+	var balance int64
+	err := r.db.QueryRow(`SELECT COALESCE(SUM(amount), 0) FROM account_entries WHERE user_id = ?`, userID).Scan(&balance)
+	return domain.Money(balance), err
+}
+
+// GetLoanCharges retorna o total de multas já lançadas para um empréstimo
+func (r *AccountRepository) GetLoanCharges(loanID string) (domain.Money, error) {
+	var total int64
+	err := r.db.QueryRow(`SELECT COALESCE(SUM(amount), 0) FROM account_entries WHERE loan_id = ? AND type = ?`,
+		loanID, domain.AccountEntryCharge).Scan(&total)
+	return domain.Money(total), err
+}
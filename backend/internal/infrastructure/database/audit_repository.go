@@ -0,0 +1,104 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"library-management/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// AuditRepository implementa domain.AuditRepository usando SQLite
+type AuditRepository struct {
+	db dbExecutor
+}
+
+// NewAuditRepository cria uma nova instância do AuditRepository
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Append inclui um registro no log. occurred_at é gravado em UTC para que
+// os filtros por intervalo comparem corretamente.
+func (r *AuditRepository) Append(entry *domain.AuditEntry) error {
+	entry.ID = uuid.New()
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return err
+	}
+
+	var actorID interface{}
+	if entry.ActorID != nil {
+		actorID = entry.ActorID.String()
+	}
+	var requestID interface{}
+	if entry.RequestID != "" {
+		requestID = entry.RequestID
+	}
+
+	query := `
+		INSERT INTO audit_log (id, occurred_at, actor_id, actor_name, action, entity, entity_id, changes, request_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = r.db.Exec(query, entry.ID.String(), entry.OccurredAt.UTC(), actorID, entry.ActorName, entry.Action,
+		entry.Entity, entry.EntityID, string(changes), requestID)
+	return translateError(err, nil)
+}
+
+// List retorna uma página dos registros filtrados, dos mais recentes aos mais antigos
+func (r *AuditRepository) List(query domain.AuditQuery) ([]*domain.AuditEntry, int, error) {
+	var where conditions
+	if query.Entity != "" {
+		where.add("entity = ?", query.Entity)
+	}
+	if query.EntityID != "" {
+		where.add("entity_id = ?", query.EntityID)
+	}
+	if query.ActorID != "" {
+		where.add("actor_id = ?", query.ActorID)
+	}
+	if query.From != nil {
+		where.add("occurred_at >= ?", query.From.UTC())
+	}
+	if query.To != nil {
+		where.add("occurred_at < ?", query.To.UTC())
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM audit_log`+where.where(), where.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	limit, args := limitOffset(query.Page, where.args)
+	rows, err := r.db.Query(`
+		SELECT id, occurred_at, actor_id, actor_name, action, entity, entity_id, changes, COALESCE(request_id, '')
+		FROM audit_log`+where.where()+` ORDER BY occurred_at DESC, id DESC`+limit, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []*domain.AuditEntry{}
+	for rows.Next() {
+		entry := &domain.AuditEntry{}
+		var idStr, changes string
+		var actorID sql.NullString
+		err := rows.Scan(&idStr, &entry.OccurredAt, &actorID, &entry.ActorName, &entry.Action, &entry.Entity,
+			&entry.EntityID, &changes, &entry.RequestID)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		entry.ID, _ = uuid.Parse(idStr)
+		entry.OccurredAt = entry.OccurredAt.Local()
+		if actorID.Valid {
+			id, _ := uuid.Parse(actorID.String)
+			entry.ActorID = &id
+		}
+		if err := json.Unmarshal([]byte(changes), &entry.Changes); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, rows.Err()
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"database/sql"
+	"library-management/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// blockSelect seleciona as colunas de um bloqueio manual
+const blockSelect = `
+	SELECT id, user_id, reason, expires_at, lifted_at, created_at
+	FROM patron_blocks
+`
+
+// BlockRepository implementa domain.BlockRepository usando SQLite
+type BlockRepository struct {
+	db dbExecutor
+}
+
+// NewBlockRepository cria uma nova instância do BlockRepository
+func NewBlockRepository(db *sql.DB) *BlockRepository {
+	return &BlockRepository{db: db}
+}
+
+// Create insere um novo bloqueio no banco
+func (r *BlockRepository) Create(block *domain.PatronBlock) error {
+	block.ID = uuid.New()
+	query := `
+		INSERT INTO patron_blocks (id, user_id, reason, expires_at, lifted_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.Exec(query, block.ID.String(), block.UserID.String(), block.Reason,
+		block.ExpiresAt, block.LiftedAt, block.CreatedAt)
+	return translateError(err, nil)
+}
+
+// GetByID busca um bloqueio pelo ID
+func (r *BlockRepository) GetByID(id string) (*domain.PatronBlock, error) {
+	block, err := scanBlock(r.db.QueryRow(blockSelect+` WHERE id = ?`, id))
+	return block, translateError(err, domain.ErrBlockNotFound)
+}
+
+// Update atualiza um bloqueio existente
+func (r *BlockRepository) Update(block *domain.PatronBlock) error {
+	query := `UPDATE patron_blocks SET reason = ?, expires_at = ?, lifted_at = ? WHERE id = ?`
+	_, err := r.db.Exec(query, block.Reason, block.ExpiresAt, block.LiftedAt, block.ID.String())
+	return translateError(err, nil)
+}
+
+// GetByUser retorna todos os bloqueios de um usuário, do mais recente ao mais antigo
+func (r *BlockRepository) GetByUser(userID string) ([]*domain.PatronBlock, error) {
+	return r.queryBlocks(blockSelect+` WHERE user_id = ? ORDER BY created_at DESC`, userID)
+}
+
+// GetActiveByUser retorna os bloqueios do usuário que ainda valem em now
+func (r *BlockRepository) GetActiveByUser(userID string, now time.Time) ([]*domain.PatronBlock, error) {
+	return r.queryBlocks(blockSelect+`
+		WHERE user_id = ? AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)
+		ORDER BY created_at DESC
+	`, userID, now)
+}
+
+// queryBlocks executa uma query e retorna os bloqueios
+func (r *BlockRepository) queryBlocks(query string, args ...interface{}) ([]*domain.PatronBlock, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocks []*domain.PatronBlock
+	for rows.Next() {
+		block, err := scanBlock(rows)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks, rows.Err()
+}
+
+// scanBlock constrói um bloqueio a partir de uma linha de blockSelect
+func scanBlock(row rowScanner) (*domain.PatronBlock, error) {
+	block := &domain.PatronBlock{}
+	var idStr, userIDStr string
+	var expiresAt, liftedAt sql.NullTime
+	err := row.Scan(&idStr, &userIDStr, &block.Reason, &expiresAt, &liftedAt, &block.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	block.ID, _ = uuid.Parse(idStr)
+	block.UserID, _ = uuid.Parse(userIDStr)
+	if expiresAt.Valid {
+		block.ExpiresAt = &expiresAt.Time
+	}
+	if liftedAt.Valid {
+		block.LiftedAt = &liftedAt.Time
+	}
+
+	return block, nil
+}
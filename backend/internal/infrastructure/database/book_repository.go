@@ -2,15 +2,53 @@ package database
 
 import (
 	"database/sql"
+	"html"
 	"library-management/internal/domain"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// bookColumns são as colunas dos livros junto com a contagem de exemplares
+const bookColumns = `
+	b.id, b.title, b.author, b.year_published, COALESCE(b.isbn, ''), COALESCE(b.isbn_display, ''),
+	COALESCE(b.publisher, ''), COALESCE(b.cover_url, ''), COALESCE(b.subjects, ''), b.material_type,
+	b.created_at, b.updated_at, b.deleted_at,
+	(SELECT COUNT(*) FROM items i WHERE i.book_id = b.id) AS total_copies,
+	(SELECT COUNT(*) FROM items i WHERE i.book_id = b.id AND i.status = 'available') AS available_copies
+`
+
+// bookSelect seleciona os livros junto com a contagem de exemplares
+const bookSelect = `SELECT ` + bookColumns + ` FROM books b`
+
+// bookNotDeleted exclui os livros removidos
+const bookNotDeleted = `b.deleted_at IS NULL`
+
+// bookRank é a relevância de um livro na busca textual. O bm25 pondera as
+// colunas de books_fts (book_id, title, author, isbn, subjects): um termo no
+// título vale mais que no autor, e assim por diante.
+const bookRank = `bm25(books_fts, 0, 10.0, 5.0, 2.0, 3.0)`
+
+// bookSearchSelect seleciona os livros encontrados pela busca textual com a
+// relevância e os trechos destacados. Os termos vêm entre os caracteres de
+// controle STX e ETX, trocados por <mark> depois de escapar o HTML do texto.
+const bookSearchSelect = `
+	SELECT ` + bookColumns + `,
+		-` + bookRank + ` AS score,
+		highlight(books_fts, 1, char(2), char(3)),
+		highlight(books_fts, 2, char(2), char(3)),
+		snippet(books_fts, -1, char(2), char(3), '…', 16)
+	FROM books_fts
+	JOIN books b ON b.id = books_fts.book_id
+	WHERE books_fts MATCH ?
+`
+
 // BookRepository implementa domain.BookRepository usando SQLite
 type BookRepository struct {
-	db *sql.DB
+	db dbExecutor
 }
 
 // NewBookRepository cria uma nova instância do BookRepository
@@ -22,119 +60,279 @@ func NewBookRepository(db *sql.DB) *BookRepository {
 func (r *BookRepository) Create(book *domain.Book) error {
 	book.ID = uuid.New()
 	query := `
-		INSERT INTO books (id, title, author, year_published, isbn, is_available, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO books (id, title, author, year_published, isbn, isbn_display, publisher, cover_url,
+			subjects, material_type, created_at, updated_at)
+		VALUES (?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?)
 	`
 	_, err := r.db.Exec(query, book.ID.String(), book.Title, book.Author, book.YearPublished,
-		book.ISBN, book.IsAvailable, book.CreatedAt, book.UpdatedAt)
-	return err
+		book.ISBN, book.ISBNDisplay, book.Publisher, book.CoverURL, joinSubjects(book.Subjects),
+		book.MaterialType, book.CreatedAt, book.UpdatedAt)
+	if err != nil {
+		return translateError(err, nil)
+	}
+	return r.index(book)
 }
 
 // GetByID busca um livro pelo ID
 func (r *BookRepository) GetByID(id string) (*domain.Book, error) {
-	query := `
-		SELECT id, title, author, year_published, isbn, is_available, created_at, updated_at
-		FROM books WHERE id = ?
-	`
-	row := r.db.QueryRow(query, id)
+	book, err := scanBook(r.db.QueryRow(bookSelect+` WHERE b.id = ? AND `+bookNotDeleted, id))
+	return book, translateError(err, domain.ErrBookNotFound)
+}
 
-	book := &domain.Book{}
-	var idStr string
-	err := row.Scan(&idStr, &book.Title, &book.Author, &book.YearPublished,
-		&book.ISBN, &book.IsAvailable, &book.CreatedAt, &book.UpdatedAt)
-	if err != nil {
-		return nil, err
+// GetByIDWithDeleted busca um livro pelo ID, mesmo que removido
+func (r *BookRepository) GetByIDWithDeleted(id string) (*domain.Book, error) {
+	book, err := scanBook(r.db.QueryRow(bookSelect+` WHERE b.id = ?`, id))
+	return book, translateError(err, domain.ErrBookNotFound)
+}
+
+// GetByISBN busca um livro pelo ISBN-13 normalizado
+func (r *BookRepository) GetByISBN(isbn string) (*domain.Book, error) {
+	book, err := scanBook(r.db.QueryRow(bookSelect+` WHERE b.isbn = ? AND `+bookNotDeleted, isbn))
+	return book, translateError(err, domain.ErrBookNotFound)
+}
+
+// bookSortColumns traduz os campos de ordenação de domain.BookSortFields
+var bookSortColumns = map[string]string{
+	"title":          "b.title",
+	"author":         "b.author",
+	"year_published": "b.year_published",
+	"created_at":     "b.created_at",
+}
+
+// List retorna uma página dos livros que atendem aos filtros, junto com o total
+func (r *BookRepository) List(query domain.BookQuery) ([]*domain.Book, int, error) {
+	var cond conditions
+	if !query.IncludeDeleted {
+		cond.add(bookNotDeleted)
+	}
+	if query.Author != "" {
+		cond.add(`b.author LIKE ? ESCAPE '\'`, likeContains(query.Author))
+	}
+	if query.YearFrom > 0 {
+		cond.add(`b.year_published >= ?`, query.YearFrom)
+	}
+	if query.YearTo > 0 {
+		cond.add(`b.year_published <= ?`, query.YearTo)
+	}
+	if query.MaterialType != "" {
+		cond.add(`b.material_type = ?`, query.MaterialType)
+	}
+	if query.Available != nil {
+		exists := `EXISTS (SELECT 1 FROM items i WHERE i.book_id = b.id AND i.status = 'available')`
+		if !*query.Available {
+			exists = "NOT " + exists
+		}
+		cond.add(exists)
 	}
 
-	book.ID, err = uuid.Parse(idStr)
-	if err != nil {
-		return nil, err
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM books b`+cond.where(), cond.args...).Scan(&total); err != nil {
+		return nil, 0, err
 	}
 
-	return book, nil
+	limit, args := limitOffset(query.Page, cond.args)
+	books, err := r.queryBooks(bookSelect+cond.where()+orderBy(query.Sort, bookSortColumns, "b.title", "b.id")+limit, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return books, total, nil
 }
 
-// GetAll retorna todos os livros
-func (r *BookRepository) GetAll() ([]*domain.Book, error) {
-	query := `
-		SELECT id, title, author, year_published, isbn, is_available, created_at, updated_at
-		FROM books ORDER BY title
-	`
-	rows, err := r.db.Query(query)
+// Each percorre todos os livros não removidos, em ordem de título, sem
+// carregá-los de uma vez; para no primeiro erro retornado por fn
+func (r *BookRepository) Each(fn func(book *domain.Book) error) error {
+	rows, err := r.db.Query(bookSelect + ` WHERE ` + bookNotDeleted + ` ORDER BY b.title, b.id`)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var books []*domain.Book
 	for rows.Next() {
-		book := &domain.Book{}
-		var idStr string
-		err := rows.Scan(&idStr, &book.Title, &book.Author, &book.YearPublished,
-			&book.ISBN, &book.IsAvailable, &book.CreatedAt, &book.UpdatedAt)
+		book, err := scanBook(rows)
 		if err != nil {
-			return nil, err
+			return err
 		}
-
-		book.ID, err = uuid.Parse(idStr)
-		if err != nil {
-			return nil, err
+		if err := fn(book); err != nil {
+			return err
 		}
-
-		books = append(books, book)
 	}
-
-	return books, nil
+	return rows.Err()
 }
 
-// Update atualiza um livro existente
+// Update atualiza um livro existente. O livro removido sai do índice de
+// busca e volta a ele quando é restaurado.
 func (r *BookRepository) Update(book *domain.Book) error {
 	query := `
-		UPDATE books 
-		SET title = ?, author = ?, year_published = ?, isbn = ?, is_available = ?, updated_at = ?
+		UPDATE books
+		SET title = ?, author = ?, year_published = ?, isbn = NULLIF(?, ''), isbn_display = NULLIF(?, ''),
+			publisher = NULLIF(?, ''), cover_url = NULLIF(?, ''), subjects = NULLIF(?, ''), material_type = ?,
+			updated_at = ?, deleted_at = ?
 		WHERE id = ?
 	`
-	_, err := r.db.Exec(query, book.Title, book.Author, book.YearPublished,
-		book.ISBN, book.IsAvailable, book.UpdatedAt, book.ID.String())
+	_, err := r.db.Exec(query, book.Title, book.Author, book.YearPublished, book.ISBN, book.ISBNDisplay,
+		book.Publisher, book.CoverURL, joinSubjects(book.Subjects), book.MaterialType, book.UpdatedAt,
+		book.DeletedAt, book.ID.String())
+	if err != nil {
+		return translateError(err, nil)
+	}
+	if err := r.unindex(book.ID.String()); err != nil {
+		return err
+	}
+	if book.DeletedAt != nil {
+		return nil
+	}
+	return r.index(book)
+}
+
+// purgedBooks seleciona os livros removidos antes do corte que nunca foram
+// emprestados nem reservados. Os empréstimos e as reservas são o histórico
+// dos leitores, e os livros que aparecem nele não são expurgados.
+const purgedBooks = `SELECT id FROM books WHERE deleted_at IS NOT NULL AND deleted_at < ?
+	AND id NOT IN (SELECT book_id FROM loans) AND id NOT IN (SELECT book_id FROM reservations)`
+
+// bookPurgeSteps apagam, nesta ordem, o que pertence aos livros removidos
+// antes do corte
+var bookPurgeSteps = []string{
+	`DELETE FROM items WHERE book_id IN (` + purgedBooks + `)`,
+	`DELETE FROM books_fts WHERE book_id IN (` + purgedBooks + `)`,
+	`DELETE FROM books WHERE id IN (` + purgedBooks + `)`,
+}
+
+// Purge apaga de vez os livros removidos antes de deletedBefore que nunca
+// foram emprestados nem reservados, junto com os exemplares deles, e retorna
+// os ids apagados.
+// Deve ser chamado dentro de uma transação (domain.UnitOfWork).
+func (r *BookRepository) Purge(deletedBefore time.Time) ([]string, error) {
+	return purge(r.db, purgedBooks, bookPurgeSteps, deletedBefore)
+}
+
+// index inclui o livro no índice de busca textual, com o ISBN normalizado e o
+// de exibição, para que a busca encontre qualquer um dos dois. Como a escrita no livro e
+// no índice são comandos separados, quem altera livros deve fazê-lo dentro de
+// uma transação (domain.UnitOfWork).
+func (r *BookRepository) index(book *domain.Book) error {
+	query := `INSERT INTO books_fts (book_id, title, author, isbn, subjects) VALUES (?, ?, ?, ?, ?)`
+	isbn := strings.TrimSpace(book.ISBN + " " + book.ISBNDisplay)
+	_, err := r.db.Exec(query, book.ID.String(), book.Title, book.Author, isbn, joinSubjects(book.Subjects))
 	return err
 }
 
-// Delete remove um livro
-func (r *BookRepository) Delete(id string) error {
-	query := `DELETE FROM books WHERE id = ?`
-	_, err := r.db.Exec(query, id)
+// unindex remove o livro do índice de busca textual
+func (r *BookRepository) unindex(id string) error {
+	_, err := r.db.Exec(`DELETE FROM books_fts WHERE book_id = ?`, id)
 	return err
 }
 
-// GetAvailable retorna todos os livros disponíveis
-func (r *BookRepository) GetAvailable() ([]*domain.Book, error) {
-	query := `
-		SELECT id, title, author, year_published, isbn, is_available, created_at, updated_at
-		FROM books WHERE is_available = true ORDER BY title
-	`
-	rows, err := r.db.Query(query)
+// Search retorna uma página dos livros encontrados pela busca textual, do mais
+// ao menos relevante, junto com o total
+func (r *BookRepository) Search(search domain.BookSearch) ([]*domain.BookSearchResult, int, error) {
+	match := matchExpression(search.Text)
+	if match == "" {
+		return nil, 0, nil
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM books_fts WHERE books_fts MATCH ?`, match).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	limit, args := limitOffset(search.Page, []interface{}{match})
+	rows, err := r.db.Query(bookSearchSelect+` ORDER BY `+bookRank+`, b.title, b.id`+limit, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var books []*domain.Book
+	var results []*domain.BookSearchResult
 	for rows.Next() {
-		book := &domain.Book{}
-		var idStr string
-		err := rows.Scan(&idStr, &book.Title, &book.Author, &book.YearPublished,
-			&book.ISBN, &book.IsAvailable, &book.CreatedAt, &book.UpdatedAt)
+		result := &domain.BookSearchResult{}
+		result.Book, err = scanBook(rows, &result.Score,
+			&result.Highlights.Title, &result.Highlights.Author, &result.Highlights.Snippet)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		result.Highlights.Title = markHighlight(result.Highlights.Title)
+		result.Highlights.Author = markHighlight(result.Highlights.Author)
+		result.Highlights.Snippet = markHighlight(result.Highlights.Snippet)
+		results = append(results, result)
+	}
+	return results, total, rows.Err()
+}
+
+// highlightMarks troca os marcadores STX e ETX do FTS5 por <mark> e </mark>
+var highlightMarks = strings.NewReplacer("\x02", "<mark>", "\x03", "</mark>")
+
+// markHighlight escapa o HTML do trecho destacado pelo FTS5 e só então
+// marca os termos encontrados, para que o texto do livro nunca vire HTML
+func markHighlight(text string) string {
+	return highlightMarks.Replace(html.EscapeString(text))
+}
+
+// matchExpression converte o texto digitado em uma expressão MATCH do FTS5:
+// cada palavra vira um termo entre aspas com busca por prefixo ("dom"
+// encontra "Dom Casmurro"), e todos os termos precisam ocorrer. Pontuação e
+// operadores do FTS5 são descartados, então o texto nunca gera erro de sintaxe.
+func matchExpression(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = `"` + word + `"*`
+	}
+	return strings.Join(terms, " ")
+}
+
+// queryBooks executa uma query e retorna os livros
+func (r *BookRepository) queryBooks(query string, args ...interface{}) ([]*domain.Book, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		book.ID, err = uuid.Parse(idStr)
+	var books []*domain.Book
+	for rows.Next() {
+		book, err := scanBook(rows)
 		if err != nil {
 			return nil, err
 		}
-
 		books = append(books, book)
 	}
 
-	return books, nil
+	return books, rows.Err()
+}
+
+// scanBook constrói um livro a partir de uma linha com as colunas de
+// bookColumns; extra recebe as colunas seguintes, se houver
+func scanBook(row rowScanner, extra ...interface{}) (*domain.Book, error) {
+	book := &domain.Book{}
+	var idStr, subjects string
+	var deletedAt sql.NullTime
+	dest := []interface{}{&idStr, &book.Title, &book.Author, &book.YearPublished,
+		&book.ISBN, &book.ISBNDisplay, &book.Publisher, &book.CoverURL, &subjects, &book.MaterialType,
+		&book.CreatedAt, &book.UpdatedAt, &deletedAt, &book.TotalCopies, &book.AvailableCopies}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	if deletedAt.Valid {
+		book.DeletedAt = &deletedAt.Time
+	}
+
+	book.ID, err = uuid.Parse(idStr)
+	if err != nil {
+		return nil, err
+	}
+	book.IsAvailable = book.AvailableCopies > 0
+	if subjects != "" {
+		book.Subjects = strings.Split(subjects, "\n")
+	}
+
+	return book, nil
+}
+
+// joinSubjects junta os assuntos do livro, um por linha, como ficam no banco
+func joinSubjects(subjects []string) string {
+	return strings.Join(subjects, "\n")
 }
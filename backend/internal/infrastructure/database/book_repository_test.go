@@ -0,0 +1,82 @@
+package database
+
+import (
+	"library-management/internal/domain"
+	"testing"
+)
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"palavra", "dom", `"dom"*`},
+		{"várias palavras", "Dom Casmurro", `"Dom"* "Casmurro"*`},
+		{"acentos", "São João", `"São"* "João"*`},
+		{"dígitos", "isbn 978", `"isbn"* "978"*`},
+		{"pontuação", "machado, de assis!", `"machado"* "de"* "assis"*`},
+		{"operadores do FTS5", `"dom" OR casm* -x (a) NEAR^b`, `"dom"* "OR"* "casm"* "x"* "a"* "NEAR"* "b"*`},
+		{"só pontuação", `"*-()`, ""},
+		{"vazio", "", ""},
+		{"espaços", "   ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchExpression(tt.text); got != tt.want {
+				t.Errorf("matchExpression(%q) = %q, esperado %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkHighlight(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"sem termos", "Dom Casmurro", "Dom Casmurro"},
+		{"termo", "\x02Dom\x03 Casmurro", "<mark>Dom</mark> Casmurro"},
+		{"HTML no texto", "<img src=x onerror=\"alert(1)\"> \x02Dom\x03", `&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>Dom</mark>`},
+		{"marcas no texto", "<mark>\x02Dom\x03</mark>", "&lt;mark&gt;<mark>Dom</mark>&lt;/mark&gt;"},
+		{"entidades", "Tom & Jerry", "Tom &amp; Jerry"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markHighlight(tt.text); got != tt.want {
+				t.Errorf("markHighlight(%q) = %q, esperado %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSearchHighlights confere que o HTML do título chega escapado nos
+// destaques da busca
+func TestSearchHighlights(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := embeddedMigrator(t, db).Up(); err != nil {
+		t.Fatalf("erro ao aplicar as migrações: %v", err)
+	}
+	repo := NewBookRepository(db)
+	book := &domain.Book{
+		Title:        `<img src=x onerror=alert(1)> Dom Casmurro`,
+		Author:       "Machado de Assis",
+		MaterialType: domain.DefaultMaterialType,
+	}
+	if err := repo.Create(book); err != nil {
+		t.Fatalf("erro ao criar o livro: %v", err)
+	}
+
+	results, total, err := repo.Search(domain.BookSearch{Text: "dom", Page: domain.Page{Limit: 10}})
+	if err != nil || total != 1 || len(results) != 1 {
+		t.Fatalf("Search = %d resultado(s), total %d, %v, esperado 1", len(results), total, err)
+	}
+	want := `&lt;img src=x onerror=alert(1)&gt; <mark>Dom</mark> Casmurro`
+	if got := results[0].Highlights.Title; got != want {
+		t.Errorf("destaque do título = %q, esperado %q", got, want)
+	}
+	if results[0].Title != book.Title {
+		t.Errorf("título = %q, esperado o original %q", results[0].Title, book.Title)
+	}
+}
@@ -3,70 +3,136 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
-// InitDB inicializa a conexão com o banco de dados e cria as tabelas
-func InitDB(dbPath string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+// Modos aceitos em Config.JournalMode e Config.Synchronous
+var (
+	journalModes     = []string{"DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF"}
+	synchronousModes = []string{"OFF", "NORMAL", "FULL", "EXTRA"}
+)
+
+// Config reúne as configurações da conexão com o SQLite. Os PRAGMAs são
+// passados ao driver, que os aplica em cada conexão aberta pelo pool.
+type Config struct {
+	Path string
+	// ForeignKeys liga a verificação das chaves estrangeiras
+	ForeignKeys bool
+	// JournalMode é o modo do journal; no WAL, as leituras não esperam as escritas
+	JournalMode string
+	// Synchronous define quando o SQLite força a gravação em disco; NORMAL é
+	// seguro no modo WAL
+	Synchronous string
+	// BusyTimeout é quanto uma conexão espera pelo lock antes de falhar com
+	// "database is locked"
+	BusyTimeout time.Duration
+	// MaxOpenConns e MaxIdleConns limitam o pool; 0 em MaxOpenConns é sem limite
+	MaxOpenConns int
+	MaxIdleConns int
+	// ConnMaxLifetime é o tempo máximo de uso de uma conexão; 0 é sem limite
+	ConnMaxLifetime time.Duration
+}
+
+// DefaultConfig retorna a configuração padrão para o banco em path
+func DefaultConfig(path string) Config {
+	return Config{
+		Path:         path,
+		ForeignKeys:  true,
+		JournalMode:  "WAL",
+		Synchronous:  "NORMAL",
+		BusyTimeout:  5 * time.Second,
+		MaxOpenConns: 10,
+		MaxIdleConns: 10,
+	}
+}
+
+// validate confere os modos e limites da configuração
+func (c Config) validate() error {
+	if !containsFold(journalModes, c.JournalMode) {
+		return fmt.Errorf("modo de journal inválido: %q (use %s)", c.JournalMode, strings.Join(journalModes, ", "))
+	}
+	if !containsFold(synchronousModes, c.Synchronous) {
+		return fmt.Errorf("modo synchronous inválido: %q (use %s)", c.Synchronous, strings.Join(synchronousModes, ", "))
+	}
+	if c.BusyTimeout < 0 || c.MaxOpenConns < 0 || c.MaxIdleConns < 0 || c.ConnMaxLifetime < 0 {
+		return fmt.Errorf("os limites da conexão não podem ser negativos")
+	}
+	return nil
+}
+
+// dsn monta o endereço do banco com os PRAGMAs da configuração.
+// _txlock=immediate faz com que toda transação reserve o lock de escrita já
+// no BEGIN, e _busy_timeout faz as demais conexões aguardarem em vez de
+// falharem imediatamente com "database is locked".
+func (c Config) dsn() string {
+	params := url.Values{}
+	params.Set("_txlock", "immediate")
+	params.Set("_busy_timeout", strconv.FormatInt(c.BusyTimeout.Milliseconds(), 10))
+	params.Set("_foreign_keys", strconv.FormatBool(c.ForeignKeys))
+	params.Set("_journal_mode", strings.ToUpper(c.JournalMode))
+	params.Set("_synchronous", strings.ToUpper(c.Synchronous))
+
+	separator := "?"
+	if strings.Contains(c.Path, "?") {
+		separator = "&"
+	}
+	return c.Path + separator + params.Encode()
+}
+
+// Open abre a conexão com o banco de dados sem aplicar migrações
+func Open(cfg Config) (*sql.DB, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", cfg.dsn())
 	if err != nil {
 		return nil, fmt.Errorf("erro ao conectar com o banco: %v", err)
 	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("erro ao verificar conexão: %v", err)
 	}
 
-	if err := createTables(db); err != nil {
-		return nil, fmt.Errorf("erro ao criar tabelas: %v", err)
-	}
-
 	return db, nil
 }
 
-// createTables cria as tabelas necessárias
-func createTables(db *sql.DB) error {
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS books (
-			id TEXT PRIMARY KEY,
-			title TEXT NOT NULL,
-			author TEXT NOT NULL,
-			year_published INTEGER,
-			isbn TEXT,
-			is_available BOOLEAN DEFAULT TRUE,
-			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-		)`,
-		`CREATE TABLE IF NOT EXISTS users (
-			id TEXT PRIMARY KEY,
-			name TEXT NOT NULL,
-			email TEXT UNIQUE NOT NULL,
-			phone TEXT,
-			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-		)`,
-		`CREATE TABLE IF NOT EXISTS loans (
-			id TEXT PRIMARY KEY,
-			book_id TEXT NOT NULL,
-			user_id TEXT NOT NULL,
-			loan_date DATETIME NOT NULL,
-			due_date DATETIME NOT NULL,
-			return_date DATETIME,
-			is_returned BOOLEAN DEFAULT FALSE,
-			is_overdue BOOLEAN DEFAULT FALSE,
-			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-			FOREIGN KEY (book_id) REFERENCES books(id),
-			FOREIGN KEY (user_id) REFERENCES users(id)
-		)`,
+// InitDB inicializa a conexão com o banco de dados e aplica as migrações pendentes
+func InitDB(cfg Config) (*sql.DB, error) {
+	db, err := Open(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, query := range queries {
-		if _, err := db.Exec(query); err != nil {
-			return fmt.Errorf("erro ao executar query: %v", err)
-		}
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		db.Close()
+		return nil, err
 	}
 
-	return nil
+	if _, err := migrator.Up(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("erro ao aplicar migrações: %v", err)
+	}
+
+	return db, nil
+}
+
+// containsFold informa se values contém value, sem diferenciar maiúsculas
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
 }
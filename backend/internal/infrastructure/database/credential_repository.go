@@ -0,0 +1,49 @@
+package database
+
+import (
+	"database/sql"
+	"library-management/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// CredentialRepository implementa domain.CredentialRepository usando SQLite
+type CredentialRepository struct {
+	db dbExecutor
+}
+
+// NewCredentialRepository cria uma nova instância do CredentialRepository
+func NewCredentialRepository(db *sql.DB) *CredentialRepository {
+	return &CredentialRepository{db: db}
+}
+
+// Save cria ou substitui a senha do usuário
+func (r *CredentialRepository) Save(credential *domain.Credential) error {
+	query := `
+		INSERT INTO credentials (user_id, password_hash, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET password_hash = excluded.password_hash, updated_at = excluded.updated_at
+	`
+	_, err := r.db.Exec(query, credential.UserID.String(), credential.PasswordHash, credential.UpdatedAt)
+	return translateError(err, nil)
+}
+
+// GetByUser retorna a senha do usuário, ou nil se ele não tiver senha cadastrada
+func (r *CredentialRepository) GetByUser(userID string) (*domain.Credential, error) {
+	credential := &domain.Credential{}
+	var userIDStr string
+	err := r.db.QueryRow(`SELECT user_id, password_hash, updated_at FROM credentials WHERE user_id = ?`, userID).
+		Scan(&userIDStr, &credential.PasswordHash, &credential.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	credential.UserID, err = uuid.Parse(userIDStr)
+	if err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
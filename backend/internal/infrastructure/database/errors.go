@@ -0,0 +1,56 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"library-management/internal/domain"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// uniqueConflicts associa as colunas das restrições de unicidade (na forma
+// usada pelo SQLite nas mensagens de erro) ao erro do domínio correspondente
+var uniqueConflicts = map[string]*domain.Error{
+	"users.email":   domain.ErrEmailTaken,
+	"items.barcode": domain.ErrBarcodeTaken,
+	"books.isbn":    domain.ErrISBNTaken,
+	"circulation_policies.patron_category, circulation_policies.material_type": domain.ErrPolicyExists,
+	"loans.item_id": domain.ErrItemOnLoan,
+	"loans.book_id": domain.ErrItemOnLoan,
+	"reservations.user_id, reservations.book_id": domain.ErrHoldExists,
+}
+
+// translateError converte os erros do banco nos erros do domínio:
+// sql.ErrNoRows vira notFound (ou domain.ErrNotFound, se nil) e as violações
+// de restrição viram Conflict. Os demais erros são retornados sem alteração.
+func translateError(err error, notFound *domain.Error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		if notFound == nil {
+			notFound = domain.ErrNotFound
+		}
+		return notFound.WithCause(err)
+	}
+
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) || sqliteErr.Code != sqlite3.ErrConstraint {
+		return err
+	}
+
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		// Mensagem no formato "UNIQUE constraint failed: tabela.coluna, ..."
+		_, columns, _ := strings.Cut(sqliteErr.Error(), "failed: ")
+		if conflict, ok := uniqueConflicts[columns]; ok {
+			return conflict.WithCause(err)
+		}
+		return domain.ErrDuplicate.WithCause(err)
+	case sqlite3.ErrConstraintForeignKey:
+		return domain.ErrReferenced.WithCause(err)
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"database/sql"
+	"library-management/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// itemSelect seleciona as colunas de um exemplar
+const itemSelect = `
+	SELECT id, book_id, barcode, shelf_location, status, created_at, updated_at
+	FROM items
+`
+
+// ItemRepository implementa domain.ItemRepository usando SQLite
+type ItemRepository struct {
+	db dbExecutor
+}
+
+// NewItemRepository cria uma nova instância do ItemRepository
+func NewItemRepository(db *sql.DB) *ItemRepository {
+	return &ItemRepository{db: db}
+}
+
+// Create insere um novo exemplar no banco
+func (r *ItemRepository) Create(item *domain.Item) error {
+	if item.ID == uuid.Nil {
+		item.ID = uuid.New()
+	}
+	query := `
+		INSERT INTO items (id, book_id, barcode, shelf_location, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.Exec(query, item.ID.String(), item.BookID.String(), item.Barcode,
+		item.ShelfLocation, item.Status, item.CreatedAt, item.UpdatedAt)
+	return translateError(err, nil)
+}
+
+// GetByID busca um exemplar pelo ID
+func (r *ItemRepository) GetByID(id string) (*domain.Item, error) {
+	item, err := scanItem(r.db.QueryRow(itemSelect+` WHERE id = ?`, id))
+	return item, translateError(err, domain.ErrItemNotFound)
+}
+
+// GetByBarcode busca um exemplar pelo código de barras
+func (r *ItemRepository) GetByBarcode(barcode string) (*domain.Item, error) {
+	item, err := scanItem(r.db.QueryRow(itemSelect+` WHERE barcode = ?`, barcode))
+	return item, translateError(err, domain.ErrItemNotFound)
+}
+
+// GetByBook retorna todos os exemplares de um livro
+func (r *ItemRepository) GetByBook(bookID string) ([]*domain.Item, error) {
+	rows, err := r.db.Query(itemSelect+` WHERE book_id = ? ORDER BY barcode`, bookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*domain.Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// Update atualiza um exemplar existente
+func (r *ItemRepository) Update(item *domain.Item) error {
+	query := `
+		UPDATE items
+		SET barcode = ?, shelf_location = ?, status = ?, updated_at = ?
+		WHERE id = ?
+	`
+	_, err := r.db.Exec(query, item.Barcode, item.ShelfLocation, item.Status,
+		item.UpdatedAt, item.ID.String())
+	return translateError(err, nil)
+}
+
+// Delete remove um exemplar
+func (r *ItemRepository) Delete(id string) error {
+	_, err := r.db.Exec(`DELETE FROM items WHERE id = ?`, id)
+	return translateError(err, nil)
+}
+
+// GetAvailableByBook retorna um exemplar disponível do livro, ou nil se não houver
+func (r *ItemRepository) GetAvailableByBook(bookID string) (*domain.Item, error) {
+	row := r.db.QueryRow(itemSelect+` WHERE book_id = ? AND status = ? ORDER BY barcode LIMIT 1`,
+		bookID, domain.ItemStatusAvailable)
+	item, err := scanItem(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return item, err
+}
+
+// scanItem constrói um exemplar a partir de uma linha de itemSelect
+func scanItem(row rowScanner) (*domain.Item, error) {
+	item := &domain.Item{}
+	var idStr, bookIDStr string
+	err := row.Scan(&idStr, &bookIDStr, &item.Barcode, &item.ShelfLocation,
+		&item.Status, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	item.ID, err = uuid.Parse(idStr)
+	if err != nil {
+		return nil, err
+	}
+	item.BookID, err = uuid.Parse(bookIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
@@ -0,0 +1,161 @@
+package database
+
+import (
+	"database/sql"
+	"library-management/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// jobRunSelect seleciona as colunas de uma execução
+const jobRunSelect = `
+	SELECT id, job, trigger, scheduled_for, status, started_at, finished_at, COALESCE(result, ''), COALESCE(error, '')
+	FROM job_runs
+`
+
+// JobRepository implementa domain.JobRepository usando SQLite
+type JobRepository struct {
+	db dbExecutor
+}
+
+// NewJobRepository cria uma nova instância do JobRepository
+func NewJobRepository(db *sql.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+// Acquire reserva a tarefa para owner até until, se não houver reserva ou a
+// reserva existente tiver expirado em now. Os horários são gravados em UTC
+// para que processos em fusos diferentes os comparem corretamente.
+func (r *JobRepository) Acquire(job, owner string, now, until time.Time) (bool, error) {
+	query := `
+		INSERT INTO job_locks (job, owner, locked_until)
+		VALUES (?, ?, ?)
+		ON CONFLICT (job) DO UPDATE SET owner = excluded.owner, locked_until = excluded.locked_until
+		WHERE job_locks.locked_until < ?
+	`
+	result, err := r.db.Exec(query, job, owner, until.UTC(), now.UTC())
+	if err != nil {
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	return count == 1, err
+}
+
+// Extend prorroga até until a reserva de owner; retorna false se a reserva
+// não é mais dele
+func (r *JobRepository) Extend(job, owner string, until time.Time) (bool, error) {
+	result, err := r.db.Exec(`UPDATE job_locks SET locked_until = ? WHERE job = ? AND owner = ?`, until.UTC(), job, owner)
+	if err != nil {
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	return count == 1, err
+}
+
+// Release libera a reserva de owner
+func (r *JobRepository) Release(job, owner string) error {
+	_, err := r.db.Exec(`DELETE FROM job_locks WHERE job = ? AND owner = ?`, job, owner)
+	return err
+}
+
+// CreateRun registra o início de uma execução. Os horários são gravados em
+// UTC, como em Acquire: o mesmo horário agendado precisa resultar no mesmo
+// valor em qualquer processo para que a duplicidade seja detectada, e a
+// ordem das execuções não pode depender do fuso de quem as gravou.
+func (r *JobRepository) CreateRun(run *domain.JobRun) error {
+	run.ID = uuid.New()
+	query := `
+		INSERT INTO job_runs (id, job, trigger, scheduled_for, status, started_at, finished_at, result, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.Exec(query, run.ID.String(), run.Job, run.Trigger, utc(run.ScheduledFor), run.Status,
+		run.StartedAt.UTC(), utc(run.FinishedAt), run.Result, run.Error)
+	return translateError(err, nil)
+}
+
+// UpdateRun grava a situação e o resultado de uma execução
+func (r *JobRepository) UpdateRun(run *domain.JobRun) error {
+	query := `UPDATE job_runs SET status = ?, finished_at = ?, result = ?, error = ? WHERE id = ?`
+	_, err := r.db.Exec(query, run.Status, utc(run.FinishedAt), run.Result, run.Error, run.ID.String())
+	return translateError(err, nil)
+}
+
+// ListRuns retorna uma página das execuções da tarefa, das mais recentes às mais antigas
+func (r *JobRepository) ListRuns(job string, page domain.Page) ([]*domain.JobRun, int, error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM job_runs WHERE job = ?`, job).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	limit, args := limitOffset(page, []interface{}{job})
+	rows, err := r.db.Query(jobRunSelect+` WHERE job = ? ORDER BY started_at DESC, id`+limit, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	runs := []*domain.JobRun{}
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, total, rows.Err()
+}
+
+// LastRun retorna a execução mais recente da tarefa, ou nil se não houver
+func (r *JobRepository) LastRun(job string) (*domain.JobRun, error) {
+	run, err := scanJobRun(r.db.QueryRow(jobRunSelect+` WHERE job = ? ORDER BY started_at DESC LIMIT 1`, job))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return run, err
+}
+
+// FailInterrupted encerra como falhas as execuções em andamento cuja tarefa
+// não tem reserva válida em now
+func (r *JobRepository) FailInterrupted(now time.Time) (int, error) {
+	query := `
+		UPDATE job_runs SET status = ?, finished_at = ?, error = 'execução interrompida'
+		WHERE status = ? AND job NOT IN (SELECT job FROM job_locks WHERE locked_until >= ?)
+	`
+	result, err := r.db.Exec(query, domain.JobStatusFailed, now.UTC(), domain.JobStatusRunning, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	count, err := result.RowsAffected()
+	return int(count), err
+}
+
+// utc converte um horário opcional para UTC
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// scanJobRun constrói uma execução a partir de uma linha de jobRunSelect
+func scanJobRun(row rowScanner) (*domain.JobRun, error) {
+	run := &domain.JobRun{}
+	var idStr string
+	var scheduledFor, finishedAt sql.NullTime
+	err := row.Scan(&idStr, &run.Job, &run.Trigger, &scheduledFor, &run.Status, &run.StartedAt,
+		&finishedAt, &run.Result, &run.Error)
+	if err != nil {
+		return nil, err
+	}
+
+	run.ID, _ = uuid.Parse(idStr)
+	if scheduledFor.Valid {
+		run.ScheduledFor = &scheduledFor.Time
+	}
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+	return run, nil
+}
@@ -8,9 +8,15 @@ import (
 	"github.com/google/uuid"
 )
 
+// loanSelect seleciona as colunas de um empréstimo
+const loanSelect = `
+	SELECT id, item_id, book_id, user_id, loan_date, due_date, return_date, is_returned, is_overdue, renewal_count, created_at, updated_at
+	FROM loans
+`
+
 // LoanRepository implementa domain.LoanRepository usando SQLite
 type LoanRepository struct {
-	db *sql.DB
+	db dbExecutor
 }
 
 // NewLoanRepository cria uma nova instância do LoanRepository
@@ -22,155 +28,122 @@ func NewLoanRepository(db *sql.DB) *LoanRepository {
 func (r *LoanRepository) Create(loan *domain.Loan) error {
 	loan.ID = uuid.New()
 	query := `
-		INSERT INTO loans (id, book_id, user_id, loan_date, due_date, return_date, is_returned, is_overdue, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO loans (id, item_id, book_id, user_id, loan_date, due_date, return_date, is_returned, is_overdue, renewal_count, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := r.db.Exec(query, loan.ID.String(), loan.BookID.String(), loan.UserID.String(),
-		loan.LoanDate, loan.DueDate, loan.ReturnDate, loan.IsReturned, loan.IsOverdue,
+	_, err := r.db.Exec(query, loan.ID.String(), nullableUUID(loan.ItemID), loan.BookID.String(), loan.UserID.String(),
+		loan.LoanDate, loan.DueDate, loan.ReturnDate, loan.IsReturned, loan.IsOverdue, loan.RenewalCount,
 		loan.CreatedAt, loan.UpdatedAt)
-	return err
+	return translateError(err, nil)
 }
 
 // GetByID busca um empréstimo pelo ID
 func (r *LoanRepository) GetByID(id string) (*domain.Loan, error) {
-	query := `
-		SELECT id, book_id, user_id, loan_date, due_date, return_date, is_returned, is_overdue, created_at, updated_at
-		FROM loans WHERE id = ?
-	`
-	row := r.db.QueryRow(query, id)
-
-	loan := &domain.Loan{}
-	var idStr, bookIDStr, userIDStr string
-	var returnDate sql.NullTime
-	err := row.Scan(&idStr, &bookIDStr, &userIDStr, &loan.LoanDate, &loan.DueDate,
-		&returnDate, &loan.IsReturned, &loan.IsOverdue, &loan.CreatedAt, &loan.UpdatedAt)
-	if err != nil {
-		return nil, err
-	}
+	loan, err := scanLoan(r.db.QueryRow(loanSelect+` WHERE id = ?`, id))
+	return loan, translateError(err, domain.ErrLoanNotFound)
+}
 
-	loan.ID, _ = uuid.Parse(idStr)
-	loan.BookID, _ = uuid.Parse(bookIDStr)
-	loan.UserID, _ = uuid.Parse(userIDStr)
+// loanSortColumns traduz os campos de ordenação de domain.LoanSortFields
+var loanSortColumns = map[string]string{
+	"loan_date":   "loan_date",
+	"due_date":    "due_date",
+	"return_date": "return_date",
+	"created_at":  "created_at",
+}
 
-	if returnDate.Valid {
-		loan.ReturnDate = &returnDate.Time
+// List retorna uma página dos empréstimos que atendem aos filtros, junto com o total
+func (r *LoanRepository) List(query domain.LoanQuery) ([]*domain.Loan, int, error) {
+	var cond conditions
+	switch query.Status {
+	case domain.LoanStatusActive:
+		cond.add(`is_returned = false`)
+	case domain.LoanStatusReturned:
+		cond.add(`is_returned = true`)
+	case domain.LoanStatusOverdue:
+		cond.add(`is_returned = false AND due_date < ?`, query.Now)
+	}
+	if query.UserID != "" {
+		cond.add(`user_id = ?`, query.UserID)
+	}
+	if query.BookID != "" {
+		cond.add(`book_id = ?`, query.BookID)
+	}
+	if query.LoanedFrom != nil {
+		cond.add(`loan_date >= ?`, *query.LoanedFrom)
+	}
+	if query.LoanedTo != nil {
+		cond.add(`loan_date < ?`, *query.LoanedTo)
+	}
+	if query.DueFrom != nil {
+		cond.add(`due_date >= ?`, *query.DueFrom)
+	}
+	if query.DueTo != nil {
+		cond.add(`due_date < ?`, *query.DueTo)
 	}
 
-	return loan, nil
-}
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM loans`+cond.where(), cond.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
 
-// GetAll retorna todos os empréstimos
-func (r *LoanRepository) GetAll() ([]*domain.Loan, error) {
-	query := `
-		SELECT id, book_id, user_id, loan_date, due_date, return_date, is_returned, is_overdue, created_at, updated_at
-		FROM loans ORDER BY loan_date DESC
-	`
-	return r.queryLoans(query)
+	limit, args := limitOffset(query.Page, cond.args)
+	loans, err := r.queryLoans(loanSelect+cond.where()+orderBy(query.Sort, loanSortColumns, "loan_date", "id")+limit, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return loans, total, nil
 }
 
 // Update atualiza um empréstimo existente
 func (r *LoanRepository) Update(loan *domain.Loan) error {
 	query := `
-		UPDATE loans 
-		SET book_id = ?, user_id = ?, loan_date = ?, due_date = ?, return_date = ?, 
-		    is_returned = ?, is_overdue = ?, updated_at = ?
+		UPDATE loans
+		SET item_id = ?, book_id = ?, user_id = ?, loan_date = ?, due_date = ?, return_date = ?,
+		    is_returned = ?, is_overdue = ?, renewal_count = ?, updated_at = ?
 		WHERE id = ?
 	`
-	_, err := r.db.Exec(query, loan.BookID.String(), loan.UserID.String(),
-		loan.LoanDate, loan.DueDate, loan.ReturnDate, loan.IsReturned, loan.IsOverdue,
+	_, err := r.db.Exec(query, nullableUUID(loan.ItemID), loan.BookID.String(), loan.UserID.String(),
+		loan.LoanDate, loan.DueDate, loan.ReturnDate, loan.IsReturned, loan.IsOverdue, loan.RenewalCount,
 		loan.UpdatedAt, loan.ID.String())
-	return err
+	return translateError(err, nil)
 }
 
 // Delete remove um empréstimo
 func (r *LoanRepository) Delete(id string) error {
 	query := `DELETE FROM loans WHERE id = ?`
 	_, err := r.db.Exec(query, id)
-	return err
-}
-
-// GetActiveLoans retorna todos os empréstimos ativos
-func (r *LoanRepository) GetActiveLoans() ([]*domain.Loan, error) {
-	query := `
-		SELECT id, book_id, user_id, loan_date, due_date, return_date, is_returned, is_overdue, created_at, updated_at
-		FROM loans WHERE is_returned = false ORDER BY loan_date DESC
-	`
-	return r.queryLoans(query)
+	return translateError(err, nil)
 }
 
 // GetOverdueLoans retorna todos os empréstimos em atraso
 func (r *LoanRepository) GetOverdueLoans() ([]*domain.Loan, error) {
-	query := `
-		SELECT id, book_id, user_id, loan_date, due_date, return_date, is_returned, is_overdue, created_at, updated_at
-		FROM loans WHERE is_returned = false AND due_date < ? ORDER BY due_date
-	`
-	rows, err := r.db.Query(query, time.Now())
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return r.scanLoans(rows)
+	return r.queryLoans(loanSelect+` WHERE is_returned = false AND due_date < ? ORDER BY due_date`, time.Now())
 }
 
 // GetLoansByUser retorna todos os empréstimos de um usuário
 func (r *LoanRepository) GetLoansByUser(userID string) ([]*domain.Loan, error) {
-	query := `
-		SELECT id, book_id, user_id, loan_date, due_date, return_date, is_returned, is_overdue, created_at, updated_at
-		FROM loans WHERE user_id = ? ORDER BY loan_date DESC
-	`
-	rows, err := r.db.Query(query, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return r.scanLoans(rows)
+	return r.queryLoans(loanSelect+` WHERE user_id = ? ORDER BY loan_date DESC`, userID)
 }
 
-// GetLoansByBook retorna todos os empréstimos de um livro
-func (r *LoanRepository) GetLoansByBook(bookID string) ([]*domain.Loan, error) {
-	query := `
-		SELECT id, book_id, user_id, loan_date, due_date, return_date, is_returned, is_overdue, created_at, updated_at
-		FROM loans WHERE book_id = ? ORDER BY loan_date DESC
-	`
-	rows, err := r.db.Query(query, bookID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	return r.scanLoans(rows)
+// GetActiveLoanByBook retorna um empréstimo ativo de qualquer exemplar do livro
+func (r *LoanRepository) GetActiveLoanByBook(bookID string) (*domain.Loan, error) {
+	return r.queryActiveLoan(loanSelect+` WHERE book_id = ? AND is_returned = false LIMIT 1`, bookID)
 }
 
-// GetActiveLoanByBook retorna o empréstimo ativo de um livro específico
-func (r *LoanRepository) GetActiveLoanByBook(bookID string) (*domain.Loan, error) {
-	query := `
-		SELECT id, book_id, user_id, loan_date, due_date, return_date, is_returned, is_overdue, created_at, updated_at
-		FROM loans WHERE book_id = ? AND is_returned = false LIMIT 1
-	`
-	row := r.db.QueryRow(query, bookID)
+// GetActiveLoanByItem retorna o empréstimo ativo de um exemplar específico
+func (r *LoanRepository) GetActiveLoanByItem(itemID string) (*domain.Loan, error) {
+	return r.queryActiveLoan(loanSelect+` WHERE item_id = ? AND is_returned = false LIMIT 1`, itemID)
+}
 
-	loan := &domain.Loan{}
-	var idStr, bookIDStr, userIDStr string
-	var returnDate sql.NullTime
-	err := row.Scan(&idStr, &bookIDStr, &userIDStr, &loan.LoanDate, &loan.DueDate,
-		&returnDate, &loan.IsReturned, &loan.IsOverdue, &loan.CreatedAt, &loan.UpdatedAt)
+// queryActiveLoan busca um único empréstimo, retornando nil se não houver
+func (r *LoanRepository) queryActiveLoan(query string, args ...interface{}) (*domain.Loan, error) {
+	loan, err := scanLoan(r.db.QueryRow(query, args...))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-
-	loan.ID, _ = uuid.Parse(idStr)
-	loan.BookID, _ = uuid.Parse(bookIDStr)
-	loan.UserID, _ = uuid.Parse(userIDStr)
-
-	if returnDate.Valid {
-		loan.ReturnDate = &returnDate.Time
-	}
-
 	return loan, nil
 }
 
@@ -189,25 +162,71 @@ func (r *LoanRepository) queryLoans(query string, args ...interface{}) ([]*domai
 func (r *LoanRepository) scanLoans(rows *sql.Rows) ([]*domain.Loan, error) {
 	var loans []*domain.Loan
 	for rows.Next() {
-		loan := &domain.Loan{}
-		var idStr, bookIDStr, userIDStr string
-		var returnDate sql.NullTime
-		err := rows.Scan(&idStr, &bookIDStr, &userIDStr, &loan.LoanDate, &loan.DueDate,
-			&returnDate, &loan.IsReturned, &loan.IsOverdue, &loan.CreatedAt, &loan.UpdatedAt)
+		loan, err := scanLoan(rows)
 		if err != nil {
 			return nil, err
 		}
+		loans = append(loans, loan)
+	}
+
+	return loans, rows.Err()
+}
 
-		loan.ID, _ = uuid.Parse(idStr)
-		loan.BookID, _ = uuid.Parse(bookIDStr)
-		loan.UserID, _ = uuid.Parse(userIDStr)
+// MarkOverdue marca como atrasados os empréstimos não devolvidos vencidos
+// antes de now e retorna os ids dos que foram marcados
+func (r *LoanRepository) MarkOverdue(now time.Time) ([]string, error) {
+	query := `
+		UPDATE loans SET is_overdue = true, updated_at = ?
+		WHERE is_returned = false AND is_overdue = false AND due_date < ?
+		RETURNING id
+	`
+	rows, err := r.db.Query(query, now, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		if returnDate.Valid {
-			loan.ReturnDate = &returnDate.Time
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
 		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
 
-		loans = append(loans, loan)
+// scanLoan constrói um empréstimo a partir de uma linha de loanSelect
+func scanLoan(row rowScanner) (*domain.Loan, error) {
+	loan := &domain.Loan{}
+	var idStr, bookIDStr, userIDStr string
+	var itemIDStr sql.NullString
+	var returnDate sql.NullTime
+	err := row.Scan(&idStr, &itemIDStr, &bookIDStr, &userIDStr, &loan.LoanDate, &loan.DueDate,
+		&returnDate, &loan.IsReturned, &loan.IsOverdue, &loan.RenewalCount, &loan.CreatedAt, &loan.UpdatedAt)
+	if err != nil {
+		return nil, err
 	}
 
-	return loans, nil
+	loan.ID, _ = uuid.Parse(idStr)
+	loan.BookID, _ = uuid.Parse(bookIDStr)
+	loan.UserID, _ = uuid.Parse(userIDStr)
+	if itemIDStr.Valid {
+		loan.ItemID, _ = uuid.Parse(itemIDStr.String)
+	}
+
+	if returnDate.Valid {
+		loan.ReturnDate = &returnDate.Time
+	}
+
+	return loan, nil
+}
+
+// nullableUUID converte uuid.Nil em NULL ao gravar
+func nullableUUID(id uuid.UUID) interface{} {
+	if id == uuid.Nil {
+		return nil
+	}
+	return id.String()
 }
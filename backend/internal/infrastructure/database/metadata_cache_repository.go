@@ -0,0 +1,58 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"library-management/internal/domain"
+	"time"
+)
+
+// MetadataCacheRepository implementa domain.MetadataCacheRepository usando SQLite.
+// Os dados bibliográficos são guardados em JSON.
+type MetadataCacheRepository struct {
+	db dbExecutor
+}
+
+// NewMetadataCacheRepository cria uma nova instância do MetadataCacheRepository
+func NewMetadataCacheRepository(db *sql.DB) *MetadataCacheRepository {
+	return &MetadataCacheRepository{db: db}
+}
+
+// Get retorna a resposta guardada para o ISBN e quando ela foi obtida
+func (r *MetadataCacheRepository) Get(isbn string) (*domain.BookMetadata, time.Time, error) {
+	var payload sql.NullString
+	var fetchedAt time.Time
+	err := r.db.QueryRow(`SELECT metadata, fetched_at FROM metadata_cache WHERE isbn = ?`, isbn).
+		Scan(&payload, &fetchedAt)
+	if err != nil {
+		return nil, time.Time{}, translateError(err, nil)
+	}
+	if !payload.Valid {
+		return nil, fetchedAt, nil
+	}
+
+	var metadata domain.BookMetadata
+	if err := json.Unmarshal([]byte(payload.String), &metadata); err != nil {
+		return nil, time.Time{}, err
+	}
+	return &metadata, fetchedAt, nil
+}
+
+// Put guarda (ou substitui) a resposta para o ISBN
+func (r *MetadataCacheRepository) Put(isbn string, metadata *domain.BookMetadata, fetchedAt time.Time) error {
+	var payload sql.NullString
+	if metadata != nil {
+		data, err := json.Marshal(metadata)
+		if err != nil {
+			return err
+		}
+		payload = sql.NullString{String: string(data), Valid: true}
+	}
+
+	query := `
+		INSERT INTO metadata_cache (isbn, metadata, fetched_at) VALUES (?, ?, ?)
+		ON CONFLICT (isbn) DO UPDATE SET metadata = excluded.metadata, fetched_at = excluded.fetched_at
+	`
+	_, err := r.db.Exec(query, isbn, payload, fetchedAt)
+	return translateError(err, nil)
+}
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_loans_active_book;
+DROP TABLE IF EXISTS loans;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS books;
@@ -0,0 +1,50 @@
+CREATE TABLE IF NOT EXISTS books (
+	id TEXT PRIMARY KEY,
+	title TEXT NOT NULL,
+	author TEXT NOT NULL,
+	year_published INTEGER,
+	isbn TEXT,
+	is_available BOOLEAN DEFAULT TRUE,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS users (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	email TEXT UNIQUE NOT NULL,
+	phone TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS loans (
+	id TEXT PRIMARY KEY,
+	book_id TEXT NOT NULL,
+	user_id TEXT NOT NULL,
+	loan_date DATETIME NOT NULL,
+	due_date DATETIME NOT NULL,
+	return_date DATETIME,
+	is_returned BOOLEAN DEFAULT FALSE,
+	is_overdue BOOLEAN DEFAULT FALSE,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (book_id) REFERENCES books(id),
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Bancos criados antes deste índice podem ter dois empréstimos ativos para o
+-- mesmo livro, justamente a corrida que ele impede. Mantém o empréstimo mais
+-- antigo e encerra os demais com a devolução na própria data do empréstimo,
+-- para que o índice possa ser criado.
+UPDATE loans
+SET is_returned = TRUE, return_date = loan_date, updated_at = CURRENT_TIMESTAMP
+WHERE is_returned = FALSE AND EXISTS (
+	SELECT 1 FROM loans older
+	WHERE older.book_id = loans.book_id AND older.is_returned = FALSE
+	  AND (older.loan_date < loans.loan_date OR (older.loan_date = loans.loan_date AND older.id < loans.id))
+);
+
+-- Garante no próprio banco que um livro tenha no máximo um empréstimo ativo
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loans_active_book
+	ON loans(book_id) WHERE is_returned = FALSE;
@@ -0,0 +1,34 @@
+ALTER TABLE books ADD COLUMN is_available BOOLEAN DEFAULT TRUE;
+
+UPDATE books SET is_available = EXISTS (
+	SELECT 1 FROM items i WHERE i.book_id = books.id AND i.status = 'available'
+);
+
+DROP INDEX IF EXISTS idx_loans_active_item;
+
+-- SQLite não permite remover uma coluna com FOREIGN KEY, então a tabela é recriada
+CREATE TABLE loans_old (
+	id TEXT PRIMARY KEY,
+	book_id TEXT NOT NULL,
+	user_id TEXT NOT NULL,
+	loan_date DATETIME NOT NULL,
+	due_date DATETIME NOT NULL,
+	return_date DATETIME,
+	is_returned BOOLEAN DEFAULT FALSE,
+	is_overdue BOOLEAN DEFAULT FALSE,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (book_id) REFERENCES books(id),
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+INSERT INTO loans_old (id, book_id, user_id, loan_date, due_date, return_date, is_returned, is_overdue, created_at, updated_at)
+SELECT id, book_id, user_id, loan_date, due_date, return_date, is_returned, is_overdue, created_at, updated_at FROM loans;
+
+DROP TABLE loans;
+
+ALTER TABLE loans_old RENAME TO loans;
+
+CREATE UNIQUE INDEX idx_loans_active_book ON loans(book_id) WHERE is_returned = FALSE;
+
+DROP TABLE items;
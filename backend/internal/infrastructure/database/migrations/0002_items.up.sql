@@ -0,0 +1,39 @@
+-- Exemplares físicos: um livro (título) pode ter vários exemplares
+CREATE TABLE items (
+	id TEXT PRIMARY KEY,
+	book_id TEXT NOT NULL,
+	barcode TEXT NOT NULL UNIQUE,
+	shelf_location TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT 'available',
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (book_id) REFERENCES books(id)
+);
+
+CREATE INDEX idx_items_book ON items(book_id, status);
+
+-- Cada livro existente passa a ter um exemplar, com o código de barras
+-- derivado do ID do livro e o status derivado de is_available
+INSERT INTO items (id, book_id, barcode, shelf_location, status, created_at, updated_at)
+SELECT
+	lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-'
+		|| substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6))),
+	id,
+	upper(substr(replace(id, '-', ''), 1, 12)),
+	'',
+	CASE WHEN is_available THEN 'available' ELSE 'on_loan' END,
+	created_at,
+	updated_at
+FROM books;
+
+-- Empréstimos passam a referenciar o exemplar
+ALTER TABLE loans ADD COLUMN item_id TEXT REFERENCES items(id);
+
+UPDATE loans SET item_id = (SELECT i.id FROM items i WHERE i.book_id = loans.book_id);
+
+DROP INDEX IF EXISTS idx_loans_active_book;
+
+CREATE UNIQUE INDEX idx_loans_active_item ON loans(item_id) WHERE is_returned = FALSE;
+
+-- A disponibilidade do livro agora é calculada a partir dos exemplares
+ALTER TABLE books DROP COLUMN is_available;
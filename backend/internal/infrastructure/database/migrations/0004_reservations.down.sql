@@ -0,0 +1,3 @@
+UPDATE items SET status = 'available' WHERE status = 'on_hold';
+
+DROP TABLE reservations;
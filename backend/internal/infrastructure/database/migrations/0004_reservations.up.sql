@@ -0,0 +1,21 @@
+CREATE TABLE reservations (
+	id TEXT PRIMARY KEY,
+	book_id TEXT NOT NULL,
+	user_id TEXT NOT NULL,
+	item_id TEXT,
+	status TEXT NOT NULL DEFAULT 'waiting',
+	ready_at DATETIME,
+	expires_at DATETIME,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (book_id) REFERENCES books(id),
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	FOREIGN KEY (item_id) REFERENCES items(id)
+);
+
+-- Fila FIFO por livro
+CREATE INDEX idx_reservations_queue ON reservations(book_id, status, created_at);
+
+-- Um usuário só pode ter uma reserva ativa por livro
+CREATE UNIQUE INDEX idx_reservations_active_user
+	ON reservations(book_id, user_id) WHERE status IN ('waiting', 'ready');
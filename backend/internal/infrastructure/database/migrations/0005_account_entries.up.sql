@@ -0,0 +1,16 @@
+-- Extrato financeiro dos usuários (multas, pagamentos, abonos e ajustes).
+-- Valores em centavos: positivos aumentam a dívida, negativos a reduzem.
+CREATE TABLE account_entries (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	loan_id TEXT,
+	type TEXT NOT NULL,
+	amount INTEGER NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	FOREIGN KEY (loan_id) REFERENCES loans(id)
+);
+
+CREATE INDEX idx_account_entries_user ON account_entries(user_id, created_at);
+CREATE INDEX idx_account_entries_loan ON account_entries(loan_id);
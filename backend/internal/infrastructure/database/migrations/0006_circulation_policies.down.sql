@@ -0,0 +1,5 @@
+DROP TABLE circulation_policies;
+
+ALTER TABLE books DROP COLUMN material_type;
+
+ALTER TABLE users DROP COLUMN category;
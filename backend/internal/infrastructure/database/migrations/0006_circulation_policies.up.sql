@@ -0,0 +1,29 @@
+ALTER TABLE users ADD COLUMN category TEXT NOT NULL DEFAULT 'student';
+
+ALTER TABLE books ADD COLUMN material_type TEXT NOT NULL DEFAULT 'book';
+
+-- Regras de circulação por categoria de usuário e, opcionalmente, por tipo de
+-- material (material_type vazio vale para qualquer tipo).
+-- Multas em centavos.
+CREATE TABLE circulation_policies (
+	id TEXT PRIMARY KEY,
+	patron_category TEXT NOT NULL,
+	material_type TEXT NOT NULL DEFAULT '',
+	loan_period_days INTEGER NOT NULL,
+	max_loans INTEGER NOT NULL DEFAULT 0,
+	max_renewals INTEGER NOT NULL DEFAULT 0,
+	renewal_grace_days INTEGER NOT NULL DEFAULT 0,
+	hold_pickup_days INTEGER NOT NULL DEFAULT 3,
+	fine_daily_rate INTEGER NOT NULL DEFAULT 0,
+	fine_max_per_item INTEGER NOT NULL DEFAULT 0,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (patron_category, material_type)
+);
+
+INSERT INTO circulation_policies
+	(id, patron_category, material_type, loan_period_days, max_loans, max_renewals, renewal_grace_days, hold_pickup_days, fine_daily_rate, fine_max_per_item)
+VALUES
+	('5f0e8a52-6c3e-4d55-9a61-0b1f3c2d7e01', 'student', '', 14, 5, 2, 0, 3, 100, 2000),
+	('5f0e8a52-6c3e-4d55-9a61-0b1f3c2d7e02', 'staff', '', 30, 15, 5, 3, 5, 100, 2000),
+	('5f0e8a52-6c3e-4d55-9a61-0b1f3c2d7e03', 'visitor', '', 7, 2, 0, 0, 2, 200, 3000);
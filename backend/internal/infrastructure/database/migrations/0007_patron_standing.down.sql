@@ -0,0 +1,3 @@
+ALTER TABLE circulation_policies DROP COLUMN max_debt;
+
+DROP TABLE patron_blocks;
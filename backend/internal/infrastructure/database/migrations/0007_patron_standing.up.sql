@@ -0,0 +1,20 @@
+-- Bloqueios manuais de empréstimo aplicados pela equipe
+CREATE TABLE patron_blocks (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	reason TEXT NOT NULL,
+	expires_at DATETIME,
+	lifted_at DATETIME,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX idx_patron_blocks_user ON patron_blocks(user_id, created_at);
+
+-- Saldo devedor máximo, em centavos, com que o usuário ainda pode pegar
+-- livros emprestados (0 = sem limite)
+ALTER TABLE circulation_policies ADD COLUMN max_debt INTEGER NOT NULL DEFAULT 0;
+
+UPDATE circulation_policies SET max_debt = 1000 WHERE patron_category = 'student';
+UPDATE circulation_policies SET max_debt = 5000 WHERE patron_category = 'staff';
+UPDATE circulation_policies SET max_debt = 500 WHERE patron_category = 'visitor';
@@ -0,0 +1,21 @@
+-- Senhas dos usuários (hash bcrypt)
+CREATE TABLE credentials (
+	user_id TEXT PRIMARY KEY,
+	password_hash TEXT NOT NULL,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Sessões de login; o refresh token é guardado apenas como hash SHA-256
+CREATE TABLE sessions (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	token_hash TEXT NOT NULL UNIQUE,
+	expires_at DATETIME NOT NULL,
+	revoked_at DATETIME,
+	last_used_at DATETIME,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_sessions_user ON sessions(user_id);
@@ -0,0 +1,4 @@
+-- Papel do usuário no sistema: admin, librarian ou patron. Usuários já
+-- existentes ficam como patron; o usuário inicial (ADMIN_EMAIL) é promovido
+-- a admin na inicialização.
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'patron';
@@ -0,0 +1,15 @@
+-- Índice de busca textual do acervo (requer o SQLite compilado com FTS5,
+-- tag de build sqlite_fts5). O tokenizer remove acentos, de modo que
+-- "memorias" encontra "Memórias". A coluna subjects fica reservada para os
+-- assuntos dos livros. O índice é mantido pelo BookRepository.
+CREATE VIRTUAL TABLE books_fts USING fts5(
+	book_id UNINDEXED,
+	title,
+	author,
+	isbn,
+	subjects,
+	tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO books_fts (book_id, title, author, isbn, subjects)
+SELECT id, title, author, COALESCE(isbn, ''), '' FROM books;
@@ -0,0 +1,8 @@
+DROP INDEX idx_books_isbn;
+
+UPDATE books SET isbn = COALESCE(isbn_display, isbn);
+
+ALTER TABLE books DROP COLUMN isbn_display;
+
+UPDATE books_fts
+SET isbn = (SELECT COALESCE(b.isbn, '') FROM books b WHERE b.id = books_fts.book_id);
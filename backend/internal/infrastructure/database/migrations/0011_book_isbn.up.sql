@@ -0,0 +1,47 @@
+-- O ISBN passa a ser guardado como ISBN-13 só com dígitos, e o texto
+-- informado pelo usuário fica em isbn_display
+ALTER TABLE books ADD COLUMN isbn_display TEXT;
+
+UPDATE books
+SET isbn_display = NULLIF(TRIM(isbn), ''),
+	isbn = UPPER(REPLACE(REPLACE(TRIM(isbn), '-', ''), ' ', ''));
+
+-- Valores que não têm o formato de um ISBN deixam de ser usados como ISBN
+-- (continuam em isbn_display). Os dígitos verificadores dos registros
+-- antigos não são conferidos; a aplicação passa a conferi-los na gravação.
+UPDATE books
+SET isbn = NULL
+WHERE isbn NOT GLOB '[0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9]' AND isbn NOT GLOB '[0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9X]';
+
+-- ISBN-10 vira ISBN-13: prefixo 978, os nove primeiros dígitos e um novo
+-- dígito verificador (pesos alternados 1 e 3, módulo 10)
+UPDATE books SET isbn = '978' || substr(isbn, 1, 9) WHERE length(isbn) = 10;
+UPDATE books
+SET isbn = isbn || ((10 - (
+		substr(isbn, 1, 1) + 3 * substr(isbn, 2, 1) +
+		substr(isbn, 3, 1) + 3 * substr(isbn, 4, 1) +
+		substr(isbn, 5, 1) + 3 * substr(isbn, 6, 1) +
+		substr(isbn, 7, 1) + 3 * substr(isbn, 8, 1) +
+		substr(isbn, 9, 1) + 3 * substr(isbn, 10, 1) +
+		substr(isbn, 11, 1) + 3 * substr(isbn, 12, 1)
+	) % 10) % 10)
+WHERE length(isbn) = 12;
+
+-- Em ISBNs repetidos, apenas o livro mais antigo mantém o ISBN
+UPDATE books
+SET isbn = NULL
+WHERE isbn IS NOT NULL AND EXISTS (
+	SELECT 1 FROM books older
+	WHERE older.isbn = books.isbn
+		AND (older.created_at < books.created_at
+			OR (older.created_at = books.created_at AND older.id < books.id))
+);
+
+CREATE UNIQUE INDEX idx_books_isbn ON books(isbn) WHERE isbn IS NOT NULL;
+
+-- O índice de busca passa a conter as duas formas do ISBN
+UPDATE books_fts
+SET isbn = (
+	SELECT TRIM(COALESCE(b.isbn, '') || ' ' || COALESCE(b.isbn_display, ''))
+	FROM books b WHERE b.id = books_fts.book_id
+);
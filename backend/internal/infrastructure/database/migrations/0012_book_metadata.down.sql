@@ -0,0 +1,4 @@
+DROP TABLE metadata_cache;
+
+ALTER TABLE books DROP COLUMN cover_url;
+ALTER TABLE books DROP COLUMN publisher;
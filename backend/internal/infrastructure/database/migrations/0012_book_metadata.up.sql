@@ -0,0 +1,11 @@
+-- Editora e capa dos livros, preenchidas pela importação por ISBN
+ALTER TABLE books ADD COLUMN publisher TEXT;
+ALTER TABLE books ADD COLUMN cover_url TEXT;
+
+-- Respostas das fontes de dados bibliográficos; metadata NULL indica que a
+-- fonte não conhecia o ISBN
+CREATE TABLE metadata_cache (
+	isbn TEXT PRIMARY KEY,
+	metadata TEXT,
+	fetched_at DATETIME NOT NULL
+);
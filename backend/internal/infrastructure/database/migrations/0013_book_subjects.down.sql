@@ -0,0 +1,3 @@
+UPDATE books_fts SET subjects = '';
+
+ALTER TABLE books DROP COLUMN subjects;
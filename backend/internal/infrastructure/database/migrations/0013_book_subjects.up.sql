@@ -0,0 +1,3 @@
+-- Assuntos dos livros (campo 650 do MARC), um por linha. A coluna subjects
+-- do índice de busca, reservada desde a 0010, passa a ser preenchida.
+ALTER TABLE books ADD COLUMN subjects TEXT;
@@ -0,0 +1,24 @@
+-- Reservas das tarefas agendadas: enquanto locked_until não passar, só o
+-- processo owner pode executar a tarefa
+CREATE TABLE job_locks (
+	job TEXT PRIMARY KEY,
+	owner TEXT NOT NULL,
+	locked_until DATETIME NOT NULL
+);
+
+-- Histórico das execuções; o índice único impede que um mesmo horário
+-- agendado seja atendido duas vezes (execuções manuais não têm horário)
+CREATE TABLE job_runs (
+	id TEXT PRIMARY KEY,
+	job TEXT NOT NULL,
+	trigger TEXT NOT NULL,
+	scheduled_for DATETIME,
+	status TEXT NOT NULL,
+	started_at DATETIME NOT NULL,
+	finished_at DATETIME,
+	result TEXT,
+	error TEXT
+);
+
+CREATE UNIQUE INDEX idx_job_runs_schedule ON job_runs(job, scheduled_for);
+CREATE INDEX idx_job_runs_started ON job_runs(job, started_at);
@@ -0,0 +1,21 @@
+-- Fila de avisos aos leitores (outbox). dedup_key identifica o fato avisado
+-- e impede que o mesmo aviso entre duas vezes na fila.
+CREATE TABLE notifications (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	dedup_key TEXT NOT NULL UNIQUE,
+	recipient TEXT NOT NULL,
+	subject TEXT NOT NULL,
+	body TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'pending',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT,
+	next_attempt_at DATETIME NOT NULL,
+	sent_at DATETIME,
+	created_at DATETIME NOT NULL,
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX idx_notifications_pending ON notifications(status, next_attempt_at);
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at);
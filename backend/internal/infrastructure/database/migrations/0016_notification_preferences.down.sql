@@ -0,0 +1,35 @@
+-- Volta a um aviso por fato: os avisos por SMS, webhook e os resumos diários
+-- são descartados
+CREATE TABLE notifications_old (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	dedup_key TEXT NOT NULL UNIQUE,
+	recipient TEXT NOT NULL,
+	subject TEXT NOT NULL,
+	body TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'pending',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT,
+	next_attempt_at DATETIME NOT NULL,
+	sent_at DATETIME,
+	created_at DATETIME NOT NULL,
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+INSERT INTO notifications_old (id, user_id, kind, dedup_key, recipient, subject, body, status, attempts,
+	last_error, next_attempt_at, sent_at, created_at)
+SELECT id, user_id, kind, dedup_key, recipient, subject, body,
+	CASE status WHEN 'held' THEN 'pending' WHEN 'digested' THEN 'sent' ELSE status END,
+	attempts, last_error, next_attempt_at, sent_at, created_at
+FROM notifications
+WHERE channel = 'email' AND kind != 'digest';
+
+DROP TABLE notifications;
+
+ALTER TABLE notifications_old RENAME TO notifications;
+
+CREATE INDEX idx_notifications_pending ON notifications(status, next_attempt_at);
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at);
+
+DROP TABLE notification_preferences;
@@ -0,0 +1,48 @@
+-- Preferências de aviso de cada usuário; quem não tem linha aqui recebe
+-- todos os avisos por email. events e channels são listas separadas por vírgula.
+CREATE TABLE notification_preferences (
+	user_id TEXT PRIMARY KEY,
+	events TEXT NOT NULL,
+	channels TEXT NOT NULL,
+	webhook_url TEXT,
+	quiet_start TEXT,
+	quiet_end TEXT,
+	digest BOOLEAN NOT NULL DEFAULT FALSE,
+	updated_at DATETIME NOT NULL,
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- A fila passa a ter um aviso por canal: o mesmo fato (dedup_key) pode
+-- entrar uma vez em cada canal. summary é o texto curto usado no SMS e no
+-- resumo diário.
+CREATE TABLE notifications_new (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	channel TEXT NOT NULL DEFAULT 'email',
+	dedup_key TEXT NOT NULL,
+	recipient TEXT NOT NULL,
+	subject TEXT NOT NULL,
+	body TEXT NOT NULL,
+	summary TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT 'pending',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT,
+	next_attempt_at DATETIME NOT NULL,
+	sent_at DATETIME,
+	created_at DATETIME NOT NULL,
+	UNIQUE (dedup_key, channel),
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+INSERT INTO notifications_new (id, user_id, kind, dedup_key, recipient, subject, body, status, attempts,
+	last_error, next_attempt_at, sent_at, created_at)
+SELECT id, user_id, kind, dedup_key, recipient, subject, body, status, attempts,
+	last_error, next_attempt_at, sent_at, created_at FROM notifications;
+
+DROP TABLE notifications;
+
+ALTER TABLE notifications_new RENAME TO notifications;
+
+CREATE INDEX idx_notifications_pending ON notifications(status, next_attempt_at);
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at);
@@ -0,0 +1,29 @@
+-- Log de auditoria: quem alterou o quê, quando e em qual requisição.
+-- actor_id é nulo nas alterações feitas pelo sistema. changes guarda, em
+-- JSON, os valores de cada campo antes e depois da alteração. Os gatilhos
+-- impedem que os registros sejam alterados ou removidos.
+CREATE TABLE audit_log (
+	id TEXT PRIMARY KEY,
+	occurred_at DATETIME NOT NULL,
+	actor_id TEXT,
+	actor_name TEXT NOT NULL,
+	action TEXT NOT NULL,
+	entity TEXT NOT NULL,
+	entity_id TEXT NOT NULL,
+	changes TEXT NOT NULL,
+	request_id TEXT
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log(entity, entity_id, occurred_at);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor_id, occurred_at);
+CREATE INDEX idx_audit_log_occurred_at ON audit_log(occurred_at);
+
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+	SELECT RAISE(ABORT, 'o log de auditoria não pode ser alterado');
+END;
+
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+	SELECT RAISE(ABORT, 'o log de auditoria não pode ser alterado');
+END;
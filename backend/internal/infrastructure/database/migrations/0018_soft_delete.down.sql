@@ -0,0 +1,11 @@
+-- Os livros e usuários removidos (e ainda não expurgados) voltam a aparecer;
+-- os livros voltam ao índice de busca
+INSERT INTO books_fts (book_id, title, author, isbn, subjects)
+SELECT id, title, author, TRIM(COALESCE(isbn, '') || ' ' || COALESCE(isbn_display, '')), COALESCE(subjects, '')
+FROM books WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
+DROP INDEX IF EXISTS idx_books_deleted_at;
+
+ALTER TABLE users DROP COLUMN deleted_at;
+ALTER TABLE books DROP COLUMN deleted_at;
@@ -0,0 +1,8 @@
+-- Livros e usuários passam a ser removidos logicamente: deleted_at marca a
+-- remoção, e as linhas só são apagadas de vez pela tarefa purge-deleted,
+-- depois do prazo de retenção. Os livros removidos saem do índice de busca.
+ALTER TABLE books ADD COLUMN deleted_at DATETIME;
+ALTER TABLE users ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX idx_books_deleted_at ON books(deleted_at);
+CREATE INDEX idx_users_deleted_at ON users(deleted_at);
@@ -0,0 +1,5 @@
+-- Os registros corrigidos na subida não são desfeitos: eles continuam
+-- válidos sem a verificação das chaves estrangeiras
+DROP INDEX IF EXISTS idx_loans_due;
+DROP INDEX IF EXISTS idx_loans_user;
+DROP INDEX IF EXISTS idx_loans_book;
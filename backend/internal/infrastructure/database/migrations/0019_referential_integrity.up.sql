@@ -0,0 +1,45 @@
+-- A verificação das chaves estrangeiras passa a ser ligada em cada conexão.
+-- Antes disso, os livros, exemplares, empréstimos e usuários apagados deixaram
+-- registros apontando para linhas inexistentes, que seriam recusados na
+-- próxima alteração. As referências opcionais órfãs são limpas; livros e
+-- usuários inexistentes ainda referenciados voltam como registros removidos,
+-- preservando o histórico até o expurgo. As datas seguem o formato gravado
+-- pela aplicação (UTC, com o fuso), e não o de CURRENT_TIMESTAMP.
+UPDATE loans SET item_id = NULL
+WHERE item_id IS NOT NULL AND item_id NOT IN (SELECT id FROM items);
+
+UPDATE reservations SET item_id = NULL
+WHERE item_id IS NOT NULL AND item_id NOT IN (SELECT id FROM items);
+
+UPDATE account_entries SET loan_id = NULL
+WHERE loan_id IS NOT NULL AND loan_id NOT IN (SELECT id FROM loans);
+
+INSERT INTO books (id, title, author, year_published, created_at, updated_at, deleted_at)
+SELECT book_id, '(livro removido)', '', 0, now, now, now
+FROM (
+	SELECT book_id FROM loans
+	UNION SELECT book_id FROM items
+	UNION SELECT book_id FROM reservations
+), (SELECT strftime('%Y-%m-%d %H:%M:%f+00:00', 'now') AS now)
+WHERE book_id NOT IN (SELECT id FROM books);
+
+INSERT INTO users (id, name, email, phone, created_at, updated_at, deleted_at)
+SELECT user_id, '(usuário removido)', user_id || '@removido.invalid', '', now, now, now
+FROM (
+	SELECT user_id FROM loans
+	UNION SELECT user_id FROM reservations
+	UNION SELECT user_id FROM account_entries
+	UNION SELECT user_id FROM patron_blocks
+	UNION SELECT user_id FROM notifications
+	UNION SELECT user_id FROM notification_preferences
+), (SELECT strftime('%Y-%m-%d %H:%M:%f+00:00', 'now') AS now)
+WHERE user_id NOT IN (SELECT id FROM users);
+
+-- Senhas e sessões são apagadas junto com o usuário (ON DELETE CASCADE)
+DELETE FROM credentials WHERE user_id NOT IN (SELECT id FROM users);
+DELETE FROM sessions WHERE user_id NOT IN (SELECT id FROM users);
+
+-- Índices das consultas de empréstimos por livro, por leitor e por vencimento
+CREATE INDEX idx_loans_book ON loans(book_id);
+CREATE INDEX idx_loans_user ON loans(user_id);
+CREATE INDEX idx_loans_due ON loans(is_returned, due_date);
@@ -0,0 +1,25 @@
+-- A unicidade volta a valer também para os registros removidos; a descida
+-- falha se um ISBN ou email estiver num registro ativo e num removido
+DROP INDEX idx_books_isbn;
+CREATE UNIQUE INDEX idx_books_isbn ON books(isbn) WHERE isbn IS NOT NULL;
+
+CREATE TABLE users_new (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	email TEXT UNIQUE NOT NULL,
+	phone TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	category TEXT NOT NULL DEFAULT 'student',
+	role TEXT NOT NULL DEFAULT 'patron',
+	deleted_at DATETIME
+);
+
+INSERT INTO users_new (id, name, email, phone, created_at, updated_at, category, role, deleted_at)
+SELECT id, name, email, phone, created_at, updated_at, category, role, deleted_at FROM users;
+
+DROP TABLE users;
+
+ALTER TABLE users_new RENAME TO users;
+
+CREATE INDEX idx_users_deleted_at ON users(deleted_at);
@@ -0,0 +1,29 @@
+-- O ISBN e o email só precisam ser únicos entre os registros ativos: um
+-- livro ou usuário removido não impede o cadastro de outro com o mesmo ISBN
+-- ou email. A restauração confere se algum registro ativo já os usa.
+DROP INDEX idx_books_isbn;
+CREATE UNIQUE INDEX idx_books_isbn ON books(isbn) WHERE isbn IS NOT NULL AND deleted_at IS NULL;
+
+-- A unicidade do email é uma restrição da tabela, que o SQLite só permite
+-- remover recriando a tabela
+CREATE TABLE users_new (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	email TEXT NOT NULL,
+	phone TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	category TEXT NOT NULL DEFAULT 'student',
+	role TEXT NOT NULL DEFAULT 'patron',
+	deleted_at DATETIME
+);
+
+INSERT INTO users_new (id, name, email, phone, created_at, updated_at, category, role, deleted_at)
+SELECT id, name, email, phone, created_at, updated_at, category, role, deleted_at FROM users;
+
+DROP TABLE users;
+
+ALTER TABLE users_new RENAME TO users;
+
+CREATE INDEX idx_users_deleted_at ON users(deleted_at);
+CREATE UNIQUE INDEX idx_users_email ON users(email) WHERE deleted_at IS NULL;
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+// embeddedMigrator cria o migrador com as migrações embutidas, ignorando o
+// teste se o SQLite foi compilado sem FTS5
+func embeddedMigrator(t *testing.T, db *sql.DB) *Migrator {
+	t.Helper()
+	migrator, err := NewMigrator(db)
+	if errors.Is(err, ErrFTS5Unavailable) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return migrator
+}
+
+// TestMigrations aplica, reverte e reaplica todas as migrações embutidas
+func TestMigrations(t *testing.T) {
+	db := openTestDB(t)
+	migrator := embeddedMigrator(t, db)
+
+	total := len(migrator.migrations)
+	if count, err := migrator.Up(); err != nil || count != total {
+		t.Fatalf("Up = %d, %v, esperado %d migrações aplicadas", count, err, total)
+	}
+	if count, err := migrator.Down(total); err != nil || count != total {
+		t.Fatalf("Down = %d, %v, esperado %d migrações revertidas", count, err, total)
+	}
+	if count, err := migrator.Up(); err != nil || count != total {
+		t.Fatalf("Up depois do Down = %d, %v, esperado %d migrações aplicadas", count, err, total)
+	}
+}
+
+// TestReferentialIntegrityMigration confere que a 0019 corrige as
+// referências quebradas deixadas pelas versões sem chaves estrangeiras
+func TestReferentialIntegrityMigration(t *testing.T) {
+	db := openTestDB(t)
+	migrator := embeddedMigrator(t, db)
+	all := migrator.migrations
+	migrator.migrations = all[:18]
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("erro ao aplicar as migrações até a 0018: %v", err)
+	}
+
+	// Um empréstimo de livro, exemplar e usuário apagados, com uma multa
+	const (
+		bookID = "0b8e7a4e-6d3c-4f0a-9f1e-2a1c3b4d5e6f"
+		userID = "1c9f8b5f-7e4d-4a1b-8a2f-3b2d4c5e6f70"
+		loanID = "2d0a9c6a-8f5e-4b2c-9b3a-4c3e5d6f7081"
+	)
+	_, err := db.Exec(`PRAGMA foreign_keys = OFF;
+		INSERT INTO loans (id, book_id, user_id, item_id, loan_date, due_date, is_returned)
+		VALUES ('` + loanID + `', '` + bookID + `', '` + userID + `', 'exemplar-apagado', '2020-01-01', '2020-01-15', TRUE);
+		INSERT INTO account_entries (id, user_id, loan_id, type, amount)
+		VALUES ('3e1b0d7b-9a6f-4c3d-8c4b-5d4f6e708192', '` + userID + `', 'emprestimo-apagado', 'charge', 100);
+		PRAGMA foreign_keys = ON`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrator.migrations = all
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("erro ao aplicar as migrações restantes: %v", err)
+	}
+
+	rows, err := db.Query(`PRAGMA foreign_key_check`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows.Next() {
+		t.Error("ainda há referências quebradas depois da migração")
+	}
+	rows.Close()
+
+	books, users := NewBookRepository(db), NewUserRepository(db)
+	book, err := books.GetByIDWithDeleted(bookID)
+	if err != nil || book.DeletedAt == nil {
+		t.Fatalf("livro recriado = %+v, %v, esperado um livro removido", book, err)
+	}
+	user, err := users.GetByIDWithDeleted(userID)
+	if err != nil || user.DeletedAt == nil {
+		t.Fatalf("usuário recriado = %+v, %v, esperado um usuário removido", user, err)
+	}
+	// As datas seguem o formato da aplicação e podem ser lidas e comparadas
+	if since := time.Since(*book.DeletedAt); since < 0 || since > time.Minute {
+		t.Errorf("deleted_at do livro = %v, esperado o momento da migração", book.DeletedAt)
+	}
+	var createdAt string
+	if err := db.QueryRow(`SELECT CAST(created_at AS TEXT) FROM users WHERE id = ?`, userID).Scan(&createdAt); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := time.Parse("2006-01-02 15:04:05.999999999-07:00", createdAt); err != nil {
+		t.Errorf("created_at do usuário = %q, esperado o formato gravado pela aplicação", createdAt)
+	}
+}
+
+// TestLegacyDuplicateLoans confere que a 0001 migra um banco criado antes das
+// migrações com dois empréstimos ativos para o mesmo livro, mantendo só o
+// mais antigo ativo
+func TestLegacyDuplicateLoans(t *testing.T) {
+	db := openTestDB(t)
+	migrator := embeddedMigrator(t, db)
+
+	// O schema criado pelas versões sem migrações
+	_, err := db.Exec(`
+		CREATE TABLE books (id TEXT PRIMARY KEY, title TEXT NOT NULL, author TEXT NOT NULL, year_published INTEGER,
+			isbn TEXT, is_available BOOLEAN DEFAULT TRUE,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP, updated_at DATETIME DEFAULT CURRENT_TIMESTAMP);
+		CREATE TABLE users (id TEXT PRIMARY KEY, name TEXT NOT NULL, email TEXT UNIQUE NOT NULL, phone TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP, updated_at DATETIME DEFAULT CURRENT_TIMESTAMP);
+		CREATE TABLE loans (id TEXT PRIMARY KEY, book_id TEXT NOT NULL, user_id TEXT NOT NULL,
+			loan_date DATETIME NOT NULL, due_date DATETIME NOT NULL, return_date DATETIME,
+			is_returned BOOLEAN DEFAULT FALSE, is_overdue BOOLEAN DEFAULT FALSE,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP, updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (book_id) REFERENCES books(id), FOREIGN KEY (user_id) REFERENCES users(id));
+		INSERT INTO books (id, title, author, is_available) VALUES ('4e2c1d8b-0a7f-4d3e-8c5b-6e5f7a8b9c0d', 'Dom Casmurro', 'Machado de Assis', FALSE);
+		INSERT INTO users (id, name, email) VALUES
+			('5f3d2e9c-1b8a-4e4f-9d6c-7f6a8b9c0d1e', 'Bentinho', 'bentinho@example.com'),
+			('6a4e3f0d-2c9b-4f5a-8e7d-8a7b9c0d1e2f', 'Escobar', 'escobar@example.com');
+		INSERT INTO loans (id, book_id, user_id, loan_date, due_date) VALUES
+			('7b5f4a1e-3d0c-4a6b-9f8e-9b8c0d1e2f30', '4e2c1d8b-0a7f-4d3e-8c5b-6e5f7a8b9c0d', '5f3d2e9c-1b8a-4e4f-9d6c-7f6a8b9c0d1e', '2024-03-01 10:00:00', '2024-03-15 10:00:00'),
+			('8c6a5b2f-4e1d-4b7c-8a9f-0c9d1e2f3041', '4e2c1d8b-0a7f-4d3e-8c5b-6e5f7a8b9c0d', '6a4e3f0d-2c9b-4f5a-8e7d-8a7b9c0d1e2f', '2024-03-01 10:00:01', '2024-03-15 10:00:01')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("erro ao migrar o banco antigo: %v", err)
+	}
+
+	var activeID string
+	if err := db.QueryRow(`SELECT id FROM loans WHERE is_returned = FALSE`).Scan(&activeID); err != nil {
+		t.Fatalf("erro ao buscar o empréstimo ativo: %v", err)
+	}
+	if activeID != "7b5f4a1e-3d0c-4a6b-9f8e-9b8c0d1e2f30" {
+		t.Errorf("empréstimo ativo = %s, esperado o mais antigo", activeID)
+	}
+	var closed bool
+	err = db.QueryRow(`SELECT is_returned AND return_date = loan_date FROM loans WHERE id = '8c6a5b2f-4e1d-4b7c-8a9f-0c9d1e2f3041'`).Scan(&closed)
+	if err != nil || !closed {
+		t.Errorf("empréstimo duplicado encerrado = %v, %v, esperado devolvido na data do empréstimo", closed, err)
+	}
+}
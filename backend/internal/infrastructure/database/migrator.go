@@ -0,0 +1,384 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationFileRegex reconhece nomes no formato 0001_descricao.up.sql / 0001_descricao.down.sql
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// ErrFTS5Unavailable indica que o SQLite foi compilado sem o FTS5, exigido
+// pelo índice de busca do acervo
+var ErrFTS5Unavailable = errors.New("o SQLite foi compilado sem FTS5; compile com -tags sqlite_fts5")
+
+// Migration representa uma migração versionada do schema
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// MigrationStatus representa a situação de uma migração no banco
+type MigrationStatus struct {
+	Version    int
+	Name       string
+	Applied    bool
+	AppliedAt  *time.Time
+	ChecksumOK bool
+	Missing    bool // aplicada no banco, mas desconhecida por este binário
+}
+
+// Migrator aplica e reverte as migrações embutidas no binário
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// appliedMigration representa uma linha da tabela schema_migrations
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// NewMigrator cria uma nova instância do Migrator com as migrações embutidas.
+// Falha com ErrFTS5Unavailable se o SQLite não tiver o FTS5, em vez de
+// deixar a migração do índice de busca falhar no meio do caminho.
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	if err := checkFTS5(db); err != nil {
+		return nil, err
+	}
+
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// checkFTS5 verifica se o SQLite foi compilado com o FTS5
+func checkFTS5(db *sql.DB) error {
+	var enabled bool
+	if err := db.QueryRow(`SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&enabled); err != nil {
+		return fmt.Errorf("erro ao verificar o FTS5: %v", err)
+	}
+	if !enabled {
+		return ErrFTS5Unavailable
+	}
+	return nil
+}
+
+// loadMigrations lê os arquivos de migração e os ordena por versão
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler migrações: %v", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		matches := migrationFileRegex.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("nome de migração inválido: %s", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(matches[1])
+		content, err := fs.ReadFile(fsys, "migrations/"+entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler migração %s: %v", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migração %d possui nomes divergentes: %s e %s", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migração %d (%s) não possui arquivo up", migration.Version, migration.Name)
+		}
+		sum := sha256.Sum256([]byte(migration.Up))
+		migration.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// ensureTable cria a tabela de controle das migrações
+func (m *Migrator) ensureTable() error {
+	_, err := m.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		applied_at DATETIME NOT NULL
+	)`)
+	return err
+}
+
+// applied retorna as migrações já aplicadas, indexadas por versão
+func (m *Migrator) applied() (map[int]appliedMigration, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query(`SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var version int
+		var a appliedMigration
+		if err := rows.Scan(&version, &a.name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = a
+	}
+
+	return applied, rows.Err()
+}
+
+// verify garante que as migrações aplicadas não foram alteradas e que o
+// banco não está em uma versão mais nova do que este binário conhece
+func (m *Migrator) verify(applied map[int]appliedMigration) error {
+	known := make(map[int]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	for version, a := range applied {
+		migration, ok := known[version]
+		if !ok {
+			return fmt.Errorf("migração %d (%s) aplicada no banco não existe neste binário", version, a.name)
+		}
+		if migration.Checksum != a.checksum {
+			return fmt.Errorf("checksum da migração %d (%s) não confere com a versão aplicada", version, migration.Name)
+		}
+	}
+
+	return nil
+}
+
+// Up aplica todas as migrações pendentes e retorna quantas foram aplicadas
+func (m *Migrator) Up() (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	if err := m.verify(applied); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := m.inTx(func(tx *sql.Tx) error {
+			if _, err := tx.Exec(migration.Up); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`,
+				migration.Version, migration.Name, migration.Checksum, time.Now())
+			return err
+		})
+		if err != nil {
+			return count, fmt.Errorf("erro ao aplicar migração %d (%s): %v", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// Down reverte as últimas steps migrações aplicadas e retorna quantas foram revertidas
+func (m *Migrator) Down(steps int) (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	if err := m.verify(applied); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return count, fmt.Errorf("migração %d (%s) não pode ser revertida", migration.Version, migration.Name)
+		}
+
+		err := m.inTx(func(tx *sql.Tx) error {
+			if _, err := tx.Exec(migration.Down); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, migration.Version)
+			return err
+		})
+		if err != nil {
+			return count, fmt.Errorf("erro ao reverter migração %d (%s): %v", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// Status retorna a situação de cada migração conhecida ou aplicada
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	known := make(map[int]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name, ChecksumOK: true}
+		if a, ok := applied[migration.Version]; ok {
+			appliedAt := a.appliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.ChecksumOK = a.checksum == migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+
+	for version, a := range applied {
+		if known[version] {
+			continue
+		}
+		appliedAt := a.appliedAt
+		statuses = append(statuses, MigrationStatus{
+			Version:   version,
+			Name:      a.name,
+			Applied:   true,
+			AppliedAt: &appliedAt,
+			Missing:   true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// inTx executa fn dentro de uma transação, com a verificação das chaves
+// estrangeiras desligada: as migrações recriam tabelas e movem dados, o que
+// a verificação impediria no meio do caminho. Como o PRAGMA não tem efeito
+// dentro de uma transação e vale só para a conexão, a migração usa uma
+// conexão reservada do pool, que volta a ele com o valor original. Antes do
+// commit, as chaves estrangeiras são conferidas com PRAGMA foreign_key_check,
+// e a migração falha se tiver deixado referências quebradas; as que já
+// existiam antes dela (bancos anteriores à 0019) não são consideradas.
+func (m *Migrator) inTx(fn func(tx *sql.Tx) error) error {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var foreignKeys bool
+	if err := conn.QueryRowContext(ctx, `PRAGMA foreign_keys`).Scan(&foreignKeys); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, `PRAGMA foreign_keys = `+strconv.FormatBool(foreignKeys))
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	before, err := foreignKeyViolations(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	after, err := foreignKeyViolations(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for reference, count := range after {
+		if count > before[reference] {
+			tx.Rollback()
+			return fmt.Errorf("a migração deixou %d referência(s) quebrada(s) em %s", count-before[reference], reference)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// foreignKeyViolations conta as referências quebradas de cada chave
+// estrangeira ("loans → books"). As linhas não são identificadas pelo rowid,
+// que muda quando uma migração recria a tabela.
+func foreignKeyViolations(tx *sql.Tx) (map[string]int, error) {
+	rows, err := tx.Query(`PRAGMA foreign_key_check`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	violations := make(map[string]int)
+	for rows.Next() {
+		var table, parent string
+		var rowID sql.NullInt64
+		var fkID int
+		if err := rows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			return nil, err
+		}
+		violations[table+" → "+parent]++
+	}
+	return violations, rows.Err()
+}
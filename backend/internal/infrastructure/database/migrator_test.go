@@ -0,0 +1,228 @@
+package database
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// openTestDB abre um banco vazio num diretório temporário
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Open(DefaultConfig(filepath.Join(t.TempDir(), "library.db")))
+	if err != nil {
+		t.Fatalf("erro ao abrir o banco: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// testMigrator cria um migrador com as migrações de files (nome → conteúdo)
+func testMigrator(t *testing.T, db *sql.DB, files map[string]string) *Migrator {
+	t.Helper()
+	fsys := fstest.MapFS{}
+	for name, content := range files {
+		fsys["migrations/"+name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("erro ao carregar as migrações: %v", err)
+	}
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// baseMigrations criam duas tabelas ligadas por uma chave estrangeira
+var baseMigrations = map[string]string{
+	"0001_authors.up.sql":   `CREATE TABLE authors (id TEXT PRIMARY KEY, name TEXT NOT NULL);`,
+	"0001_authors.down.sql": `DROP TABLE authors;`,
+	"0002_books.up.sql": `CREATE TABLE books (id TEXT PRIMARY KEY, author_id TEXT NOT NULL REFERENCES authors(id));
+INSERT INTO authors (id, name) VALUES ('a1', 'Machado de Assis');
+INSERT INTO books (id, author_id) VALUES ('b1', 'a1');`,
+	"0002_books.down.sql": `DROP TABLE books;`,
+}
+
+// withMigrations retorna baseMigrations acrescidas de extra
+func withMigrations(extra map[string]string) map[string]string {
+	files := make(map[string]string, len(baseMigrations)+len(extra))
+	for name, content := range baseMigrations {
+		files[name] = content
+	}
+	for name, content := range extra {
+		files[name] = content
+	}
+	return files
+}
+
+// tableExists informa se a tabela existe no banco
+func tableExists(t *testing.T, db *sql.DB, table string) bool {
+	t.Helper()
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	return count > 0
+}
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		err   string
+	}{
+		{"migrações válidas", baseMigrations, ""},
+		{"nome inválido", map[string]string{"1-authors.sql": ""}, "nome de migração inválido"},
+		{"sem o arquivo up", map[string]string{"0001_authors.down.sql": "DROP TABLE authors;"}, "não possui arquivo up"},
+		{"nomes divergentes", map[string]string{"0001_authors.up.sql": "", "0001_writers.down.sql": ""}, "nomes divergentes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, content := range tt.files {
+				fsys["migrations/"+name] = &fstest.MapFile{Data: []byte(content)}
+			}
+			migrations, err := loadMigrations(fsys)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("erro inesperado %v", err)
+				}
+				if len(migrations) != 2 || migrations[0].Version != 1 || migrations[1].Version != 2 || migrations[0].Checksum == "" {
+					t.Errorf("migrações = %+v, esperado as versões 1 e 2 com checksum", migrations)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("erro = %v, esperado %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestMigratorUpDown(t *testing.T) {
+	db := openTestDB(t)
+	migrator := testMigrator(t, db, baseMigrations)
+
+	if count, err := migrator.Up(); err != nil || count != 2 {
+		t.Fatalf("Up = %d, %v, esperado 2 migrações aplicadas", count, err)
+	}
+	if count, err := migrator.Up(); err != nil || count != 0 {
+		t.Fatalf("Up repetido = %d, %v, esperado nenhuma migração", count, err)
+	}
+
+	if count, err := migrator.Down(1); err != nil || count != 1 {
+		t.Fatalf("Down(1) = %d, %v, esperado 1 migração revertida", count, err)
+	}
+	if tableExists(t, db, "books") || !tableExists(t, db, "authors") {
+		t.Error("Down(1) deveria reverter só a última migração")
+	}
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || !statuses[0].Applied || statuses[1].Applied {
+		t.Errorf("status = %+v, esperado só a migração 1 aplicada", statuses)
+	}
+
+	if count, err := migrator.Down(5); err != nil || count != 1 {
+		t.Fatalf("Down(5) = %d, %v, esperado 1 migração revertida", count, err)
+	}
+	if tableExists(t, db, "authors") {
+		t.Error("a tabela authors deveria ter sido removida")
+	}
+}
+
+func TestMigratorIrreversible(t *testing.T) {
+	db := openTestDB(t)
+	files := withMigrations(nil)
+	delete(files, "0002_books.down.sql")
+	migrator := testMigrator(t, db, files)
+
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if _, err := migrator.Down(1); err == nil || !strings.Contains(err.Error(), "não pode ser revertida") {
+		t.Errorf("erro = %v, esperado a recusa da reversão", err)
+	}
+}
+
+func TestMigratorChecksum(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		err   string
+	}{
+		{"migração alterada", withMigrations(map[string]string{
+			"0001_authors.up.sql": `CREATE TABLE authors (id TEXT PRIMARY KEY, name TEXT);`,
+		}), "checksum da migração 1"},
+		{"migração desconhecida", map[string]string{
+			"0001_authors.up.sql": baseMigrations["0001_authors.up.sql"],
+		}, "migração 2 (books) aplicada no banco não existe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			if _, err := testMigrator(t, db, baseMigrations).Up(); err != nil {
+				t.Fatalf("erro inesperado %v", err)
+			}
+
+			migrator := testMigrator(t, db, tt.files)
+			if _, err := migrator.Up(); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Up: erro = %v, esperado %q", err, tt.err)
+			}
+			if _, err := migrator.Down(1); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Down: erro = %v, esperado %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestMigratorForeignKeyCheck(t *testing.T) {
+	db := openTestDB(t)
+	migrator := testMigrator(t, db, withMigrations(map[string]string{
+		"0003_orphan.up.sql": `INSERT INTO books (id, author_id) VALUES ('b2', 'inexistente');`,
+	}))
+
+	count, err := migrator.Up()
+	if err == nil || !strings.Contains(err.Error(), "referência(s) quebrada(s) em books → authors") {
+		t.Fatalf("erro = %v, esperado a recusa da referência quebrada", err)
+	}
+	if count != 2 {
+		t.Errorf("migrações aplicadas = %d, esperado 2", count)
+	}
+	var orphans int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM books WHERE id = 'b2'`).Scan(&orphans); err != nil || orphans != 0 {
+		t.Errorf("a migração com a referência quebrada deveria ter sido desfeita (%d, %v)", orphans, err)
+	}
+
+	// A verificação continua ligada nas conexões depois da migração
+	var foreignKeys bool
+	if err := db.QueryRow(`PRAGMA foreign_keys`).Scan(&foreignKeys); err != nil || !foreignKeys {
+		t.Errorf("foreign_keys = %v, %v, esperado ligado", foreignKeys, err)
+	}
+}
+
+// TestMigratorExistingViolations confere que as referências quebradas que já
+// existiam antes da migração não a impedem
+func TestMigratorExistingViolations(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := testMigrator(t, db, baseMigrations).Up(); err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if _, err := db.Exec(`PRAGMA foreign_keys = OFF; INSERT INTO books (id, author_id) VALUES ('b2', 'inexistente'); PRAGMA foreign_keys = ON`); err != nil {
+		t.Fatal(err)
+	}
+
+	migrator := testMigrator(t, db, withMigrations(map[string]string{
+		// Recria a tabela: as linhas mudam de rowid, mas continuam as mesmas
+		"0003_rebuild_books.up.sql": `CREATE TABLE books_new (id TEXT PRIMARY KEY, author_id TEXT NOT NULL REFERENCES authors(id), title TEXT);
+INSERT INTO books_new (id, author_id) SELECT id, author_id FROM books ORDER BY id DESC;
+DROP TABLE books;
+ALTER TABLE books_new RENAME TO books;`,
+	}))
+	if count, err := migrator.Up(); err != nil || count != 1 {
+		t.Errorf("Up = %d, %v, esperado 1 migração aplicada", count, err)
+	}
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"database/sql"
+	"library-management/internal/domain"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// NotificationPreferenceRepository implementa domain.NotificationPreferenceRepository usando SQLite
+type NotificationPreferenceRepository struct {
+	db dbExecutor
+}
+
+// NewNotificationPreferenceRepository cria uma nova instância do NotificationPreferenceRepository
+func NewNotificationPreferenceRepository(db *sql.DB) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{db: db}
+}
+
+// Get retorna as preferências do usuário, ou nil se ele nunca as alterou
+func (r *NotificationPreferenceRepository) Get(userID string) (*domain.NotificationPreferences, error) {
+	query := `
+		SELECT user_id, events, channels, COALESCE(webhook_url, ''), COALESCE(quiet_start, ''),
+			COALESCE(quiet_end, ''), digest, updated_at
+		FROM notification_preferences
+		WHERE user_id = ?
+	`
+	preferences := &domain.NotificationPreferences{}
+	var idStr, events, channels, quietStart, quietEnd string
+	var updatedAt sql.NullTime
+	err := r.db.QueryRow(query, userID).Scan(&idStr, &events, &channels, &preferences.WebhookURL,
+		&quietStart, &quietEnd, &preferences.Digest, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	preferences.UserID, _ = uuid.Parse(idStr)
+	preferences.Events = []domain.NotificationKind{}
+	for _, event := range splitList(events) {
+		preferences.Events = append(preferences.Events, domain.NotificationKind(event))
+	}
+	preferences.Channels = []domain.NotificationChannel{}
+	for _, channel := range splitList(channels) {
+		preferences.Channels = append(preferences.Channels, domain.NotificationChannel(channel))
+	}
+	if quietStart != "" {
+		preferences.QuietHours = &domain.QuietHours{Start: quietStart, End: quietEnd}
+	}
+	if updatedAt.Valid {
+		preferences.UpdatedAt = &updatedAt.Time
+	}
+	return preferences, nil
+}
+
+// Save grava as preferências, criando-as ou substituindo as existentes
+func (r *NotificationPreferenceRepository) Save(preferences *domain.NotificationPreferences) error {
+	events := make([]string, len(preferences.Events))
+	for i, event := range preferences.Events {
+		events[i] = string(event)
+	}
+	channels := make([]string, len(preferences.Channels))
+	for i, channel := range preferences.Channels {
+		channels[i] = string(channel)
+	}
+	var quietStart, quietEnd string
+	if preferences.QuietHours != nil {
+		quietStart, quietEnd = preferences.QuietHours.Start, preferences.QuietHours.End
+	}
+
+	query := `
+		INSERT INTO notification_preferences (user_id, events, channels, webhook_url, quiet_start, quiet_end, digest, updated_at)
+		VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+			events = excluded.events, channels = excluded.channels, webhook_url = excluded.webhook_url,
+			quiet_start = excluded.quiet_start, quiet_end = excluded.quiet_end, digest = excluded.digest,
+			updated_at = excluded.updated_at
+	`
+	_, err := r.db.Exec(query, preferences.UserID.String(), strings.Join(events, ","), strings.Join(channels, ","),
+		preferences.WebhookURL, quietStart, quietEnd, preferences.Digest, preferences.UpdatedAt)
+	return translateError(err, nil)
+}
+
+// splitList separa uma lista gravada com vírgulas; a lista vazia não tem itens
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
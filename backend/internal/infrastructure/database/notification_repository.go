@@ -0,0 +1,125 @@
+package database
+
+import (
+	"database/sql"
+	"library-management/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// notificationColumns são as colunas de um aviso, na ordem de scanNotification
+const notificationColumns = `
+	id, user_id, kind, channel, dedup_key, recipient, subject, body, summary, status, attempts,
+	COALESCE(last_error, ''), next_attempt_at, sent_at, created_at
+`
+
+// NotificationRepository implementa domain.NotificationRepository usando SQLite
+type NotificationRepository struct {
+	db dbExecutor
+}
+
+// NewNotificationRepository cria uma nova instância do NotificationRepository
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Enqueue coloca o aviso na fila. next_attempt_at é gravado em UTC para que
+// a comparação em ClaimPending não dependa do fuso do processo.
+func (r *NotificationRepository) Enqueue(notification *domain.Notification) error {
+	notification.ID = uuid.New()
+	query := `
+		INSERT INTO notifications (id, user_id, kind, channel, dedup_key, recipient, subject, body, summary,
+			status, attempts, last_error, next_attempt_at, sent_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.Exec(query, notification.ID.String(), notification.UserID.String(), notification.Kind,
+		notification.Channel, notification.DedupKey, notification.Recipient, notification.Subject,
+		notification.Body, notification.Summary, notification.Status, notification.Attempts, notification.LastError,
+		notification.NextAttemptAt.UTC(), notification.SentAt, notification.CreatedAt)
+	return translateError(err, nil)
+}
+
+// ClaimPending marca como em envio, numa única instrução, até limit avisos
+// pendentes cujo horário chegou em now, e os retorna
+func (r *NotificationRepository) ClaimPending(now time.Time, limit int) ([]*domain.Notification, error) {
+	query := `
+		UPDATE notifications SET status = ?
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at, created_at
+			LIMIT ?
+		)
+		RETURNING ` + notificationColumns
+	return r.queryNotifications(query, domain.NotificationSending, domain.NotificationPending, now.UTC(), limit)
+}
+
+// GetHeld retorna os avisos guardados para o resumo diário, dos mais antigos aos mais novos
+func (r *NotificationRepository) GetHeld() ([]*domain.Notification, error) {
+	return r.queryNotifications(`SELECT `+notificationColumns+` FROM notifications WHERE status = ? ORDER BY created_at, id`,
+		domain.NotificationHeld)
+}
+
+// queryNotifications executa uma query e retorna os avisos
+func (r *NotificationRepository) queryNotifications(query string, args ...interface{}) ([]*domain.Notification, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []*domain.Notification{}
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, rows.Err()
+}
+
+// Update grava a situação de envio do aviso
+func (r *NotificationRepository) Update(notification *domain.Notification) error {
+	query := `
+		UPDATE notifications SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, sent_at = ?
+		WHERE id = ?
+	`
+	_, err := r.db.Exec(query, notification.Status, notification.Attempts, notification.LastError,
+		notification.NextAttemptAt.UTC(), notification.SentAt, notification.ID.String())
+	return translateError(err, nil)
+}
+
+// FailSending encerra como falhas os avisos que ficaram em envio. Como não
+// se sabe se a mensagem chegou a sair, eles não voltam para a fila.
+func (r *NotificationRepository) FailSending(now time.Time) (int, error) {
+	query := `UPDATE notifications SET status = ?, last_error = 'envio interrompido', next_attempt_at = ? WHERE status = ?`
+	result, err := r.db.Exec(query, domain.NotificationFailed, now.UTC(), domain.NotificationSending)
+	if err != nil {
+		return 0, err
+	}
+	count, err := result.RowsAffected()
+	return int(count), err
+}
+
+// scanNotification constrói um aviso a partir de uma linha com notificationColumns
+func scanNotification(row rowScanner) (*domain.Notification, error) {
+	notification := &domain.Notification{}
+	var idStr, userIDStr string
+	var sentAt sql.NullTime
+	err := row.Scan(&idStr, &userIDStr, &notification.Kind, &notification.Channel, &notification.DedupKey,
+		&notification.Recipient, &notification.Subject, &notification.Body, &notification.Summary,
+		&notification.Status, &notification.Attempts,
+		&notification.LastError, &notification.NextAttemptAt, &sentAt, &notification.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	notification.ID, _ = uuid.Parse(idStr)
+	notification.UserID, _ = uuid.Parse(userIDStr)
+	if sentAt.Valid {
+		notification.SentAt = &sentAt.Time
+	}
+	return notification, nil
+}
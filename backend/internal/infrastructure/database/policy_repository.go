@@ -0,0 +1,123 @@
+package database
+
+import (
+	"database/sql"
+	"library-management/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// policySelect seleciona as colunas de uma política de circulação
+const policySelect = `
+	SELECT id, patron_category, material_type, loan_period_days, max_loans, max_renewals,
+		renewal_grace_days, hold_pickup_days, fine_daily_rate, fine_max_per_item, max_debt, created_at, updated_at
+	FROM circulation_policies
+`
+
+// PolicyRepository implementa domain.PolicyRepository usando SQLite
+type PolicyRepository struct {
+	db dbExecutor
+}
+
+// NewPolicyRepository cria uma nova instância do PolicyRepository
+func NewPolicyRepository(db *sql.DB) *PolicyRepository {
+	return &PolicyRepository{db: db}
+}
+
+// Create insere uma nova política no banco
+func (r *PolicyRepository) Create(policy *domain.CirculationPolicy) error {
+	policy.ID = uuid.New()
+	query := `
+		INSERT INTO circulation_policies (id, patron_category, material_type, loan_period_days, max_loans, max_renewals,
+			renewal_grace_days, hold_pickup_days, fine_daily_rate, fine_max_per_item, max_debt, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.Exec(query, policy.ID.String(), policy.PatronCategory, policy.MaterialType,
+		policy.LoanPeriodDays, policy.MaxLoans, policy.MaxRenewals, policy.RenewalGraceDays,
+		policy.HoldPickupDays, int64(policy.FineDailyRate), int64(policy.FineMaxPerItem), int64(policy.MaxDebt),
+		policy.CreatedAt, policy.UpdatedAt)
+	return translateError(err, nil)
+}
+
+// GetByID busca uma política pelo ID
+func (r *PolicyRepository) GetByID(id string) (*domain.CirculationPolicy, error) {
+	policy, err := scanPolicy(r.db.QueryRow(policySelect+` WHERE id = ?`, id))
+	return policy, translateError(err, domain.ErrPolicyNotFound)
+}
+
+// GetAll retorna todas as políticas
+func (r *PolicyRepository) GetAll() ([]*domain.CirculationPolicy, error) {
+	rows, err := r.db.Query(policySelect + ` ORDER BY patron_category, material_type`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []*domain.CirculationPolicy
+	for rows.Next() {
+		policy, err := scanPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, rows.Err()
+}
+
+// Update atualiza uma política existente
+func (r *PolicyRepository) Update(policy *domain.CirculationPolicy) error {
+	query := `
+		UPDATE circulation_policies
+		SET patron_category = ?, material_type = ?, loan_period_days = ?, max_loans = ?, max_renewals = ?,
+		    renewal_grace_days = ?, hold_pickup_days = ?, fine_daily_rate = ?, fine_max_per_item = ?, max_debt = ?, updated_at = ?
+		WHERE id = ?
+	`
+	_, err := r.db.Exec(query, policy.PatronCategory, policy.MaterialType, policy.LoanPeriodDays,
+		policy.MaxLoans, policy.MaxRenewals, policy.RenewalGraceDays, policy.HoldPickupDays,
+		int64(policy.FineDailyRate), int64(policy.FineMaxPerItem), int64(policy.MaxDebt), policy.UpdatedAt, policy.ID.String())
+	return translateError(err, nil)
+}
+
+// Delete remove uma política
+func (r *PolicyRepository) Delete(id string) error {
+	_, err := r.db.Exec(`DELETE FROM circulation_policies WHERE id = ?`, id)
+	return translateError(err, nil)
+}
+
+// Find retorna a política da categoria para o tipo de material, preferindo a
+// específica do tipo à genérica (material_type vazio). Retorna nil se não houver.
+func (r *PolicyRepository) Find(category domain.PatronCategory, materialType string) (*domain.CirculationPolicy, error) {
+	row := r.db.QueryRow(policySelect+`
+		WHERE patron_category = ? AND (material_type = ? OR material_type = '')
+		ORDER BY material_type DESC LIMIT 1
+	`, category, materialType)
+	policy, err := scanPolicy(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return policy, err
+}
+
+// scanPolicy constrói uma política a partir de uma linha de policySelect
+func scanPolicy(row rowScanner) (*domain.CirculationPolicy, error) {
+	policy := &domain.CirculationPolicy{}
+	var idStr string
+	var fineDailyRate, fineMaxPerItem, maxDebt int64
+	err := row.Scan(&idStr, &policy.PatronCategory, &policy.MaterialType, &policy.LoanPeriodDays,
+		&policy.MaxLoans, &policy.MaxRenewals, &policy.RenewalGraceDays, &policy.HoldPickupDays,
+		&fineDailyRate, &fineMaxPerItem, &maxDebt, &policy.CreatedAt, &policy.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	policy.ID, err = uuid.Parse(idStr)
+	if err != nil {
+		return nil, err
+	}
+	policy.FineDailyRate = domain.Money(fineDailyRate)
+	policy.FineMaxPerItem = domain.Money(fineMaxPerItem)
+	policy.MaxDebt = domain.Money(maxDebt)
+
+	return policy, nil
+}
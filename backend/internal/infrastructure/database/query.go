@@ -0,0 +1,89 @@
+package database
+
+import (
+	"library-management/internal/domain"
+	"strings"
+	"time"
+)
+
+// conditions acumula as condições do WHERE de uma listagem e seus argumentos
+type conditions struct {
+	clauses []string
+	args    []interface{}
+}
+
+// add inclui uma condição com seus argumentos
+func (c *conditions) add(clause string, args ...interface{}) {
+	c.clauses = append(c.clauses, clause)
+	c.args = append(c.args, args...)
+}
+
+// where retorna a cláusula WHERE com todas as condições, ou "" se não houver
+func (c *conditions) where() string {
+	if len(c.clauses) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(c.clauses, " AND ")
+}
+
+// orderBy monta a cláusula ORDER BY traduzindo o campo pedido pela coluna de
+// columns (campos desconhecidos usam fallback). O id entra como desempate
+// para que a paginação seja estável.
+func orderBy(sort domain.SortOrder, columns map[string]string, fallback, idColumn string) string {
+	column, ok := columns[sort.Field]
+	if !ok {
+		column = fallback
+	}
+	direction := " ASC"
+	if sort.Desc {
+		direction = " DESC"
+	}
+	return " ORDER BY " + column + direction + ", " + idColumn + direction
+}
+
+// limitOffset monta a cláusula LIMIT/OFFSET da página
+func limitOffset(page domain.Page, args []interface{}) (string, []interface{}) {
+	return " LIMIT ? OFFSET ?", append(args, page.Limit, page.Offset)
+}
+
+// likeContains monta o padrão de LIKE que procura text em qualquer posição,
+// escapando os curingas; deve ser usado com ESCAPE '\'
+func likeContains(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(text) + "%"
+}
+
+// purge seleciona com selectIDs os registros removidos antes do corte e
+// executa steps, em ordem, para apagá-los junto com o que pertence a eles.
+// selectIDs e cada passo recebem apenas o corte como argumento. Retorna os
+// ids apagados.
+func purge(db dbExecutor, selectIDs string, steps []string, cutoff time.Time) ([]string, error) {
+	rows, err := db.Query(selectIDs, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	for _, step := range steps {
+		if _, err := db.Exec(step, cutoff); err != nil {
+			return nil, translateError(err, nil)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,192 @@
+package database
+
+import (
+	"database/sql"
+	"library-management/internal/domain"
+)
+
+// reportPeriods agrupa a data do empréstimo, no fuso horário local, pelo
+// período do relatório. A semana é identificada pela data da segunda-feira.
+var reportPeriods = map[domain.ReportInterval]string{
+	domain.IntervalDay:   `strftime('%Y-%m-%d', l.loan_date, 'localtime')`,
+	domain.IntervalWeek:  `date(l.loan_date, 'localtime', 'weekday 0', '-6 days')`,
+	domain.IntervalMonth: `strftime('%Y-%m', l.loan_date, 'localtime')`,
+	domain.IntervalYear:  `strftime('%Y', l.loan_date, 'localtime')`,
+}
+
+// ReportRepository implementa domain.ReportRepository usando SQLite
+type ReportRepository struct {
+	db dbExecutor
+}
+
+// NewReportRepository cria uma nova instância do ReportRepository
+func NewReportRepository(db *sql.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// LoansPerPeriod conta os empréstimos, os leitores distintos e as
+// devoluções por período
+func (r *ReportRepository) LoansPerPeriod(filter domain.ReportFilter) (*domain.Report, error) {
+	cond := loanRange(filter)
+	query := `
+		SELECT ` + reportPeriods[filter.Interval] + ` AS period, COUNT(*) AS loans,
+		       COUNT(DISTINCT l.user_id) AS patrons, SUM(l.is_returned) AS returned
+		FROM loans l` + cond.where() + `
+		GROUP BY period ORDER BY period`
+	return r.queryReport(query, cond.args...)
+}
+
+// TopTitles ordena os livros pelo número de empréstimos
+func (r *ReportRepository) TopTitles(filter domain.ReportFilter) (*domain.Report, error) {
+	cond := loanRange(filter)
+	query := `
+		SELECT b.id AS book_id, b.title, b.author, COUNT(*) AS loans,
+		       COUNT(DISTINCT l.user_id) AS patrons
+		FROM loans l JOIN books b ON b.id = l.book_id` + cond.where() + `
+		GROUP BY b.id ORDER BY loans DESC, b.title, b.id LIMIT ?`
+	return r.queryReport(query, append(cond.args, reportLimit(filter))...)
+}
+
+// TopAuthors ordena os autores pelo número de empréstimos
+func (r *ReportRepository) TopAuthors(filter domain.ReportFilter) (*domain.Report, error) {
+	cond := loanRange(filter)
+	query := `
+		SELECT b.author, COUNT(*) AS loans, COUNT(DISTINCT b.id) AS titles,
+		       COUNT(DISTINCT l.user_id) AS patrons
+		FROM loans l JOIN books b ON b.id = l.book_id` + cond.where() + `
+		GROUP BY b.author ORDER BY loans DESC, b.author LIMIT ?`
+	return r.queryReport(query, append(cond.args, reportLimit(filter))...)
+}
+
+// ActivePatrons ordena os leitores pelo número de empréstimos
+func (r *ReportRepository) ActivePatrons(filter domain.ReportFilter) (*domain.Report, error) {
+	cond := loanRange(filter)
+	query := `
+		SELECT u.id AS user_id, u.name, u.email, u.category, COUNT(*) AS loans,
+		       COUNT(DISTINCT l.book_id) AS titles, ` + timestamp(`MAX(l.loan_date)`) + ` AS last_loan
+		FROM loans l JOIN users u ON u.id = l.user_id` + cond.where() + `
+		GROUP BY u.id ORDER BY loans DESC, u.name, u.id LIMIT ?`
+	return r.queryReport(query, append(cond.args, reportLimit(filter))...)
+}
+
+// OverdueRate calcula, por período, quantos empréstimos atrasaram: os
+// devolvidos depois do vencimento e os não devolvidos já vencidos em Now.
+// A taxa é o percentual sobre o total do período.
+func (r *ReportRepository) OverdueRate(filter domain.ReportFilter) (*domain.Report, error) {
+	cond := loanRange(filter)
+	query := `
+		SELECT period, loans, overdue, ROUND(100.0 * overdue / loans, 1) AS overdue_rate
+		FROM (
+			SELECT ` + reportPeriods[filter.Interval] + ` AS period, COUNT(*) AS loans,
+			       SUM(CASE
+			           WHEN l.is_returned THEN julianday(l.return_date) > julianday(l.due_date)
+			           ELSE julianday(l.due_date) < julianday(?)
+			       END) AS overdue
+			FROM loans l` + cond.where() + `
+			GROUP BY period
+		)
+		ORDER BY period`
+	return r.queryReport(query, append([]interface{}{filter.Now}, cond.args...)...)
+}
+
+// LoanDuration calcula, por período, a duração média, mínima e máxima em
+// dias dos empréstimos já devolvidos
+func (r *ReportRepository) LoanDuration(filter domain.ReportFilter) (*domain.Report, error) {
+	cond := loanRange(filter)
+	cond.add(`l.is_returned = true`)
+	duration := `julianday(l.return_date) - julianday(l.loan_date)`
+	query := `
+		SELECT ` + reportPeriods[filter.Interval] + ` AS period, COUNT(*) AS returned,
+		       ROUND(AVG(` + duration + `), 1) AS average_days,
+		       ROUND(MIN(` + duration + `), 1) AS min_days,
+		       ROUND(MAX(` + duration + `), 1) AS max_days
+		FROM loans l` + cond.where() + `
+		GROUP BY period ORDER BY period`
+	return r.queryReport(query, cond.args...)
+}
+
+// NeverBorrowed lista os livros cadastrados antes do fim do intervalo que
+// não tiveram empréstimos nele, candidatos ao descarte. Os nunca emprestados
+// vêm primeiro; os demais, do último empréstimo mais antigo ao mais recente.
+// Os livros removidos ficam de fora.
+func (r *ReportRepository) NeverBorrowed(filter domain.ReportFilter) (*domain.Report, error) {
+	loans := loanRange(filter)
+	loans.add(`l.book_id = b.id`)
+	var cond conditions
+	cond.add(`NOT EXISTS (SELECT 1 FROM loans l`+loans.where()+`)`, loans.args...)
+	cond.add(`b.deleted_at IS NULL`)
+	if filter.To != nil {
+		cond.add(`b.created_at < ?`, *filter.To)
+	}
+	query := `
+		SELECT b.id AS book_id, b.title, b.author, b.year_published, b.material_type,
+		       (SELECT COUNT(*) FROM items i WHERE i.book_id = b.id) AS copies,
+		       ` + timestamp(`(SELECT MAX(l.loan_date) FROM loans l WHERE l.book_id = b.id)`) + ` AS last_loan,
+		       ` + timestamp(`b.created_at`) + ` AS created_at
+		FROM books b` + cond.where() + `
+		ORDER BY last_loan IS NOT NULL, last_loan, b.title, b.id LIMIT ?`
+	return r.queryReport(query, append(cond.args, reportLimit(filter))...)
+}
+
+// loanRange monta as condições do intervalo de datas sobre loans (l)
+func loanRange(filter domain.ReportFilter) conditions {
+	var cond conditions
+	if filter.From != nil {
+		cond.add(`l.loan_date >= ?`, *filter.From)
+	}
+	if filter.To != nil {
+		cond.add(`l.loan_date < ?`, *filter.To)
+	}
+	return cond
+}
+
+// reportLimit traduz o limite do filtro para o LIMIT do SQLite, em que -1
+// significa sem limite
+func reportLimit(filter domain.ReportFilter) int {
+	if filter.Limit <= 0 {
+		return -1
+	}
+	return filter.Limit
+}
+
+// timestamp formata a expressão de data como RFC 3339 em UTC
+func timestamp(expr string) string {
+	return `strftime('%Y-%m-%dT%H:%M:%SZ', ` + expr + `)`
+}
+
+// queryReport executa a consulta e monta o relatório com as colunas e as
+// linhas do resultado. Textos vêm como string e números como int64 ou
+// float64; valores nulos, como nil.
+func (r *ReportRepository) queryReport(query string, args ...interface{}) (*domain.Report, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	report := &domain.Report{Columns: columns, Rows: []map[string]interface{}{}}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			row[column] = values[i]
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report, rows.Err()
+}
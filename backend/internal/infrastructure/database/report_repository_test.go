@@ -0,0 +1,68 @@
+package database
+
+import (
+	"library-management/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestLoanRange(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		filter domain.ReportFilter
+		where  string
+		args   int
+	}{
+		{"sem intervalo", domain.ReportFilter{}, "", 0},
+		{"só início", domain.ReportFilter{From: &from}, " WHERE l.loan_date >= ?", 1},
+		{"só fim", domain.ReportFilter{To: &to}, " WHERE l.loan_date < ?", 1},
+		{"início e fim", domain.ReportFilter{From: &from, To: &to}, " WHERE l.loan_date >= ? AND l.loan_date < ?", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond := loanRange(tt.filter)
+			if got := cond.where(); got != tt.where {
+				t.Errorf("where = %q, esperado %q", got, tt.where)
+			}
+			if len(cond.args) != tt.args {
+				t.Errorf("args = %v, esperados %d", cond.args, tt.args)
+			}
+		})
+	}
+}
+
+func TestReportLimit(t *testing.T) {
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{0, -1},
+		{-5, -1},
+		{1, 1},
+		{domain.DefaultReportLimit, domain.DefaultReportLimit},
+		{domain.MaxReportLimit, domain.MaxReportLimit},
+	}
+	for _, tt := range tests {
+		if got := reportLimit(domain.ReportFilter{Limit: tt.limit}); got != tt.want {
+			t.Errorf("reportLimit(%d) = %d, esperado %d", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestReportPeriods(t *testing.T) {
+	for _, interval := range []domain.ReportInterval{
+		domain.IntervalDay, domain.IntervalWeek, domain.IntervalMonth, domain.IntervalYear,
+	} {
+		if !interval.IsValid() {
+			t.Errorf("%q deveria ser válido", interval)
+		}
+		if reportPeriods[interval] == "" {
+			t.Errorf("%q não tem agrupamento em reportPeriods", interval)
+		}
+	}
+	if domain.ReportInterval("quarter").IsValid() {
+		t.Error(`"quarter" não deveria ser válido`)
+	}
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"database/sql"
+	"library-management/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// reservationSelect seleciona as colunas de uma reserva
+const reservationSelect = `
+	SELECT id, book_id, user_id, item_id, status, ready_at, expires_at, created_at, updated_at
+	FROM reservations
+`
+
+// ReservationRepository implementa domain.ReservationRepository usando SQLite
+type ReservationRepository struct {
+	db dbExecutor
+}
+
+// NewReservationRepository cria uma nova instância do ReservationRepository
+func NewReservationRepository(db *sql.DB) *ReservationRepository {
+	return &ReservationRepository{db: db}
+}
+
+// Create insere uma nova reserva no banco
+func (r *ReservationRepository) Create(reservation *domain.Reservation) error {
+	reservation.ID = uuid.New()
+	query := `
+		INSERT INTO reservations (id, book_id, user_id, item_id, status, ready_at, expires_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.Exec(query, reservation.ID.String(), reservation.BookID.String(), reservation.UserID.String(),
+		nullableUUIDPtr(reservation.ItemID), reservation.Status, reservation.ReadyAt, reservation.ExpiresAt,
+		reservation.CreatedAt, reservation.UpdatedAt)
+	return translateError(err, nil)
+}
+
+// GetByID busca uma reserva pelo ID
+func (r *ReservationRepository) GetByID(id string) (*domain.Reservation, error) {
+	reservation, err := scanReservation(r.db.QueryRow(reservationSelect+` WHERE id = ?`, id))
+	return reservation, translateError(err, domain.ErrHoldNotFound)
+}
+
+// Update atualiza uma reserva existente
+func (r *ReservationRepository) Update(reservation *domain.Reservation) error {
+	query := `
+		UPDATE reservations
+		SET item_id = ?, status = ?, ready_at = ?, expires_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+	_, err := r.db.Exec(query, nullableUUIDPtr(reservation.ItemID), reservation.Status,
+		reservation.ReadyAt, reservation.ExpiresAt, reservation.UpdatedAt, reservation.ID.String())
+	return translateError(err, nil)
+}
+
+// GetQueueByBook retorna as reservas ativas de um livro em ordem de chegada
+func (r *ReservationRepository) GetQueueByBook(bookID string) ([]*domain.Reservation, error) {
+	return r.queryReservations(reservationSelect+`
+		WHERE book_id = ? AND status IN (?, ?)
+		ORDER BY created_at, rowid
+	`, bookID, domain.ReservationStatusWaiting, domain.ReservationStatusReady)
+}
+
+// GetByUser retorna todas as reservas de um usuário
+func (r *ReservationRepository) GetByUser(userID string) ([]*domain.Reservation, error) {
+	return r.queryReservations(reservationSelect+` WHERE user_id = ? ORDER BY created_at DESC`, userID)
+}
+
+// GetActiveByUserAndBook retorna a reserva ativa do usuário para o livro, ou nil se não houver
+func (r *ReservationRepository) GetActiveByUserAndBook(userID, bookID string) (*domain.Reservation, error) {
+	row := r.db.QueryRow(reservationSelect+` WHERE user_id = ? AND book_id = ? AND status IN (?, ?) LIMIT 1`,
+		userID, bookID, domain.ReservationStatusWaiting, domain.ReservationStatusReady)
+	reservation, err := scanReservation(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return reservation, err
+}
+
+// GetExpiredReady retorna as reservas prontas para retirada cujo prazo terminou antes de now
+func (r *ReservationRepository) GetExpiredReady(now time.Time) ([]*domain.Reservation, error) {
+	return r.queryReservations(reservationSelect+` WHERE status = ? AND expires_at < ? ORDER BY expires_at`,
+		domain.ReservationStatusReady, now)
+}
+
+// GetReady retorna as reservas separadas aguardando retirada
+func (r *ReservationRepository) GetReady() ([]*domain.Reservation, error) {
+	return r.queryReservations(reservationSelect+` WHERE status = ? ORDER BY ready_at`, domain.ReservationStatusReady)
+}
+
+// queryReservations executa uma query e retorna as reservas
+func (r *ReservationRepository) queryReservations(query string, args ...interface{}) ([]*domain.Reservation, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reservations []*domain.Reservation
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	return reservations, rows.Err()
+}
+
+// scanReservation constrói uma reserva a partir de uma linha de reservationSelect
+func scanReservation(row rowScanner) (*domain.Reservation, error) {
+	reservation := &domain.Reservation{}
+	var idStr, bookIDStr, userIDStr string
+	var itemIDStr sql.NullString
+	var readyAt, expiresAt sql.NullTime
+	err := row.Scan(&idStr, &bookIDStr, &userIDStr, &itemIDStr, &reservation.Status,
+		&readyAt, &expiresAt, &reservation.CreatedAt, &reservation.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation.ID, _ = uuid.Parse(idStr)
+	reservation.BookID, _ = uuid.Parse(bookIDStr)
+	reservation.UserID, _ = uuid.Parse(userIDStr)
+	if itemIDStr.Valid {
+		itemID, err := uuid.Parse(itemIDStr.String)
+		if err == nil {
+			reservation.ItemID = &itemID
+		}
+	}
+	if readyAt.Valid {
+		reservation.ReadyAt = &readyAt.Time
+	}
+	if expiresAt.Valid {
+		reservation.ExpiresAt = &expiresAt.Time
+	}
+
+	return reservation, nil
+}
+
+// nullableUUIDPtr converte um ponteiro nil em NULL ao gravar
+func nullableUUIDPtr(id *uuid.UUID) interface{} {
+	if id == nil {
+		return nil
+	}
+	return id.String()
+}
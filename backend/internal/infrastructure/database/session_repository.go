@@ -0,0 +1,91 @@
+package database
+
+import (
+	"database/sql"
+	"library-management/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sessionSelect seleciona as colunas de uma sessão
+const sessionSelect = `
+	SELECT id, user_id, token_hash, expires_at, revoked_at, last_used_at, created_at
+	FROM sessions
+`
+
+// SessionRepository implementa domain.SessionRepository usando SQLite
+type SessionRepository struct {
+	db dbExecutor
+}
+
+// NewSessionRepository cria uma nova instância do SessionRepository
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create insere uma nova sessão no banco
+func (r *SessionRepository) Create(session *domain.Session) error {
+	session.ID = uuid.New()
+	query := `
+		INSERT INTO sessions (id, user_id, token_hash, expires_at, revoked_at, last_used_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.Exec(query, session.ID.String(), session.UserID.String(), session.TokenHash,
+		session.ExpiresAt, session.RevokedAt, session.LastUsedAt, session.CreatedAt)
+	return translateError(err, nil)
+}
+
+// GetByID busca uma sessão pelo ID
+func (r *SessionRepository) GetByID(id string) (*domain.Session, error) {
+	session, err := scanSession(r.db.QueryRow(sessionSelect+` WHERE id = ?`, id))
+	return session, translateError(err, domain.ErrSessionNotFound)
+}
+
+// GetByTokenHash busca uma sessão pelo hash do refresh token
+func (r *SessionRepository) GetByTokenHash(tokenHash string) (*domain.Session, error) {
+	session, err := scanSession(r.db.QueryRow(sessionSelect+` WHERE token_hash = ?`, tokenHash))
+	return session, translateError(err, domain.ErrSessionNotFound)
+}
+
+// Update atualiza uma sessão existente
+func (r *SessionRepository) Update(session *domain.Session) error {
+	query := `
+		UPDATE sessions
+		SET token_hash = ?, expires_at = ?, revoked_at = ?, last_used_at = ?
+		WHERE id = ?
+	`
+	_, err := r.db.Exec(query, session.TokenHash, session.ExpiresAt, session.RevokedAt,
+		session.LastUsedAt, session.ID.String())
+	return translateError(err, nil)
+}
+
+// RevokeByUser revoga todas as sessões ativas do usuário, exceto exceptID
+func (r *SessionRepository) RevokeByUser(userID, exceptID string, now time.Time) error {
+	query := `UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND id <> ? AND revoked_at IS NULL`
+	_, err := r.db.Exec(query, now, userID, exceptID)
+	return translateError(err, nil)
+}
+
+// scanSession constrói uma sessão a partir de uma linha de sessionSelect
+func scanSession(row rowScanner) (*domain.Session, error) {
+	session := &domain.Session{}
+	var idStr, userIDStr string
+	var revokedAt, lastUsedAt sql.NullTime
+	err := row.Scan(&idStr, &userIDStr, &session.TokenHash, &session.ExpiresAt,
+		&revokedAt, &lastUsedAt, &session.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	session.ID, _ = uuid.Parse(idStr)
+	session.UserID, _ = uuid.Parse(userIDStr)
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+	if lastUsedAt.Valid {
+		session.LastUsedAt = lastUsedAt.Time
+	}
+
+	return session, nil
+}
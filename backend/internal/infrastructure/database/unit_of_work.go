@@ -0,0 +1,100 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"library-management/internal/domain"
+)
+
+// dbExecutor abstrai *sql.DB e *sql.Tx para que os repositórios possam
+// ser usados tanto fora quanto dentro de uma transação
+type dbExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// rowScanner abstrai *sql.Row e *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// UnitOfWork implementa domain.UnitOfWork usando transações do SQLite
+type UnitOfWork struct {
+	db *sql.DB
+}
+
+// NewUnitOfWork cria uma nova instância do UnitOfWork
+func NewUnitOfWork(db *sql.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// Do executa fn dentro de uma transação, fazendo commit se fn retornar nil
+// e rollback caso contrário. As transações são abertas com BEGIN IMMEDIATE
+// (ver Config), o que serializa escritores concorrentes: a segunda requisição
+// só lê o estado depois que a primeira terminou.
+func (u *UnitOfWork) Do(fn func(repos domain.Repositories) error) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	repos := domain.Repositories{
+		Books:         &BookRepository{db: tx},
+		Items:         &ItemRepository{db: tx},
+		Users:         &UserRepository{db: tx},
+		Loans:         &LoanRepository{db: tx},
+		Reservations:  &ReservationRepository{db: tx},
+		Accounts:      &AccountRepository{db: tx},
+		Policies:      &PolicyRepository{db: tx},
+		Blocks:        &BlockRepository{db: tx},
+		Credentials:   &CredentialRepository{db: tx},
+		Sessions:      &SessionRepository{db: tx},
+		Notifications: &NotificationRepository{db: tx},
+		Preferences:   &NotificationPreferenceRepository{db: tx},
+		Audit:         &AuditRepository{db: tx},
+		Savepoints:    &savepoints{tx: tx},
+	}
+
+	if err := fn(repos); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// savepoints implementa domain.Savepoints com SAVEPOINT do SQLite dentro da
+// transação da unidade de trabalho
+type savepoints struct {
+	tx    *sql.Tx
+	count int
+}
+
+// Do executa fn entre SAVEPOINT e RELEASE; se fn falhar, volta ao savepoint
+// com ROLLBACK TO antes de liberá-lo
+func (s *savepoints) Do(fn func() error) error {
+	s.count++
+	name := fmt.Sprintf("sp%d", s.count)
+	if _, err := s.tx.Exec(`SAVEPOINT ` + name); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		if _, rbErr := s.tx.Exec(`ROLLBACK TO ` + name); rbErr != nil {
+			return rbErr
+		}
+		if _, relErr := s.tx.Exec(`RELEASE ` + name); relErr != nil {
+			return relErr
+		}
+		return err
+	}
+	_, err := s.tx.Exec(`RELEASE ` + name)
+	return err
+}
@@ -3,13 +3,14 @@ package database
 import (
 	"database/sql"
 	"library-management/internal/domain"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 // UserRepository implementa domain.UserRepository usando SQLite
 type UserRepository struct {
-	db *sql.DB
+	db dbExecutor
 }
 
 // NewUserRepository cria uma nova instância do UserRepository
@@ -21,105 +22,173 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 func (r *UserRepository) Create(user *domain.User) error {
 	user.ID = uuid.New()
 	query := `
-		INSERT INTO users (id, name, email, phone, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO users (id, name, email, phone, category, role, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	_, err := r.db.Exec(query, user.ID.String(), user.Name, user.Email,
-		user.Phone, user.CreatedAt, user.UpdatedAt)
-	return err
+		user.Phone, user.Category, user.Role, user.CreatedAt, user.UpdatedAt)
+	return translateError(err, nil)
 }
 
 // GetByID busca um usuário pelo ID
 func (r *UserRepository) GetByID(id string) (*domain.User, error) {
 	query := `
-		SELECT id, name, email, phone, created_at, updated_at
+		SELECT id, name, email, phone, category, role, created_at, updated_at, deleted_at
+		FROM users WHERE id = ? AND deleted_at IS NULL
+	`
+	user, err := scanUser(r.db.QueryRow(query, id))
+	return user, translateError(err, domain.ErrUserNotFound)
+}
+
+// GetByIDWithDeleted busca um usuário pelo ID, mesmo que removido
+func (r *UserRepository) GetByIDWithDeleted(id string) (*domain.User, error) {
+	query := `
+		SELECT id, name, email, phone, category, role, created_at, updated_at, deleted_at
 		FROM users WHERE id = ?
 	`
-	row := r.db.QueryRow(query, id)
+	user, err := scanUser(r.db.QueryRow(query, id))
+	return user, translateError(err, domain.ErrUserNotFound)
+}
 
-	user := &domain.User{}
-	var idStr string
-	err := row.Scan(&idStr, &user.Name, &user.Email, &user.Phone,
-		&user.CreatedAt, &user.UpdatedAt)
-	if err != nil {
-		return nil, err
-	}
+// userSortColumns traduz os campos de ordenação de domain.UserSortFields
+var userSortColumns = map[string]string{
+	"name":       "name",
+	"email":      "email",
+	"category":   "category",
+	"created_at": "created_at",
+}
 
-	user.ID, err = uuid.Parse(idStr)
-	if err != nil {
-		return nil, err
+// List retorna uma página dos usuários que atendem aos filtros, junto com o total
+func (r *UserRepository) List(query domain.UserQuery) ([]*domain.User, int, error) {
+	var cond conditions
+	if !query.IncludeDeleted {
+		cond.add(`deleted_at IS NULL`)
+	}
+	if query.Name != "" {
+		cond.add(`name LIKE ? ESCAPE '\'`, likeContains(query.Name))
+	}
+	if query.Email != "" {
+		cond.add(`email LIKE ? ESCAPE '\'`, likeContains(query.Email))
+	}
+	if query.Category != "" {
+		cond.add(`category = ?`, query.Category)
+	}
+	if query.Role != "" {
+		cond.add(`role = ?`, query.Role)
 	}
 
-	return user, nil
-}
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM users`+cond.where(), cond.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
 
-// GetAll retorna todos os usuários
-func (r *UserRepository) GetAll() ([]*domain.User, error) {
-	query := `
-		SELECT id, name, email, phone, created_at, updated_at
-		FROM users ORDER BY name
-	`
-	rows, err := r.db.Query(query)
+	limit, args := limitOffset(query.Page, cond.args)
+	rows, err := r.db.Query(`
+		SELECT id, name, email, phone, category, role, created_at, updated_at, deleted_at
+		FROM users`+cond.where()+orderBy(query.Sort, userSortColumns, "name", "id")+limit, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	var users []*domain.User
 	for rows.Next() {
-		user := &domain.User{}
-		var idStr string
-		err := rows.Scan(&idStr, &user.Name, &user.Email, &user.Phone,
-			&user.CreatedAt, &user.UpdatedAt)
+		user, err := scanUser(rows)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		users = append(users, user)
+	}
 
-		user.ID, err = uuid.Parse(idStr)
-		if err != nil {
-			return nil, err
-		}
+	return users, total, rows.Err()
+}
 
-		users = append(users, user)
+// Each percorre todos os usuários não removidos, em ordem de nome, sem
+// carregá-los de uma vez; para no primeiro erro retornado por fn
+func (r *UserRepository) Each(fn func(user *domain.User) error) error {
+	rows, err := r.db.Query(`
+		SELECT id, name, email, phone, category, role, created_at, updated_at, deleted_at
+		FROM users WHERE deleted_at IS NULL ORDER BY name, id`)
+	if err != nil {
+		return err
 	}
+	defer rows.Close()
 
-	return users, nil
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
 
 // Update atualiza um usuário existente
 func (r *UserRepository) Update(user *domain.User) error {
 	query := `
 		UPDATE users 
-		SET name = ?, email = ?, phone = ?, updated_at = ?
+		SET name = ?, email = ?, phone = ?, category = ?, role = ?, updated_at = ?, deleted_at = ?
 		WHERE id = ?
 	`
 	_, err := r.db.Exec(query, user.Name, user.Email, user.Phone,
-		user.UpdatedAt, user.ID.String())
-	return err
+		user.Category, user.Role, user.UpdatedAt, user.DeletedAt, user.ID.String())
+	return translateError(err, nil)
+}
+
+// purgedUsers seleciona os usuários removidos antes do corte com a conta
+// zerada. Quem ainda deve ou tem crédito não é expurgado, para que os
+// lançamentos que formam o saldo não se percam.
+const purgedUsers = `SELECT id FROM users WHERE deleted_at IS NOT NULL AND deleted_at < ?
+	AND (SELECT COALESCE(SUM(amount), 0) FROM account_entries WHERE user_id = users.id) = 0`
+
+// userPurgeSteps apagam, nesta ordem, o que pertence aos usuários removidos
+// antes do corte
+var userPurgeSteps = []string{
+	`DELETE FROM account_entries WHERE user_id IN (` + purgedUsers + `)`,
+	`DELETE FROM reservations WHERE user_id IN (` + purgedUsers + `)`,
+	`DELETE FROM loans WHERE user_id IN (` + purgedUsers + `)`,
+	`DELETE FROM patron_blocks WHERE user_id IN (` + purgedUsers + `)`,
+	`DELETE FROM credentials WHERE user_id IN (` + purgedUsers + `)`,
+	`DELETE FROM sessions WHERE user_id IN (` + purgedUsers + `)`,
+	`DELETE FROM notification_preferences WHERE user_id IN (` + purgedUsers + `)`,
+	`DELETE FROM notifications WHERE user_id IN (` + purgedUsers + `)`,
+	`DELETE FROM users WHERE id IN (` + purgedUsers + `)`,
 }
 
-// Delete remove um usuário
-func (r *UserRepository) Delete(id string) error {
-	query := `DELETE FROM users WHERE id = ?`
-	_, err := r.db.Exec(query, id)
-	return err
+// Purge apaga de vez os usuários removidos antes de deletedBefore com a conta
+// zerada, junto com tudo o que pertence a eles, e retorna os ids apagados. Deve ser chamado
+// dentro de uma transação (domain.UnitOfWork).
+func (r *UserRepository) Purge(deletedBefore time.Time) ([]string, error) {
+	return purge(r.db, purgedUsers, userPurgeSteps, deletedBefore)
 }
 
 // GetByEmail busca um usuário pelo email
 func (r *UserRepository) GetByEmail(email string) (*domain.User, error) {
 	query := `
-		SELECT id, name, email, phone, created_at, updated_at
-		FROM users WHERE email = ?
+		SELECT id, name, email, phone, category, role, created_at, updated_at, deleted_at
+		FROM users WHERE email = ? AND deleted_at IS NULL
 	`
-	row := r.db.QueryRow(query, email)
+	user, err := scanUser(r.db.QueryRow(query, email))
+	return user, translateError(err, domain.ErrUserNotFound)
+}
 
+// scanUser constrói um usuário a partir de uma linha com as colunas
+// id, name, email, phone, category, role, created_at, updated_at e deleted_at
+func scanUser(row rowScanner) (*domain.User, error) {
 	user := &domain.User{}
 	var idStr string
+	var deletedAt sql.NullTime
 	err := row.Scan(&idStr, &user.Name, &user.Email, &user.Phone,
-		&user.CreatedAt, &user.UpdatedAt)
+		&user.Category, &user.Role, &user.CreatedAt, &user.UpdatedAt, &deletedAt)
 	if err != nil {
 		return nil, err
 	}
+	if deletedAt.Valid {
+		user.DeletedAt = &deletedAt.Time
+	}
 
 	user.ID, err = uuid.Parse(idStr)
 	if err != nil {
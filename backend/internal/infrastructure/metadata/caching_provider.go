@@ -0,0 +1,53 @@
+package metadata
+
+import (
+	"errors"
+	"library-management/internal/domain"
+	"log"
+	"time"
+)
+
+// NotFoundTTL é por quanto tempo fica guardada a resposta de que nenhuma fonte
+// conhece o ISBN; é menor que o das respostas com dados, pois as fontes
+// externas passam a conhecer livros novos com o tempo
+const NotFoundTTL = 24 * time.Hour
+
+// CachingProvider implementa domain.MetadataProvider guardando as respostas
+// de outra fonte, para não consultar as fontes externas repetidamente pelo
+// mesmo ISBN. Falhas da fonte não são guardadas.
+type CachingProvider struct {
+	next  domain.MetadataProvider
+	cache domain.MetadataCacheRepository
+	ttl   time.Duration
+}
+
+// NewCachingProvider cria uma nova instância do CachingProvider; as respostas
+// com dados valem por ttl
+func NewCachingProvider(next domain.MetadataProvider, cache domain.MetadataCacheRepository, ttl time.Duration) *CachingProvider {
+	return &CachingProvider{next: next, cache: cache, ttl: ttl}
+}
+
+// LookupISBN retorna a resposta guardada, se ainda válida, ou consulta a fonte
+func (p *CachingProvider) LookupISBN(isbn string) (*domain.BookMetadata, error) {
+	now := time.Now()
+
+	metadata, fetchedAt, err := p.cache.Get(isbn)
+	switch {
+	case err == nil && metadata != nil && now.Sub(fetchedAt) < p.ttl:
+		return metadata, nil
+	case err == nil && metadata == nil && now.Sub(fetchedAt) < NotFoundTTL:
+		return nil, domain.ErrMetadataNotFound
+	case err != nil && !errors.Is(err, domain.ErrNotFound):
+		// Sem o cache, a consulta ainda pode ser feita na fonte
+		log.Printf("Erro ao ler o cache de dados bibliográficos (ISBN %s): %v", isbn, err)
+	}
+
+	metadata, err = p.next.LookupISBN(isbn)
+	if err != nil && !errors.Is(err, domain.ErrMetadataNotFound) {
+		return nil, err
+	}
+	if cacheErr := p.cache.Put(isbn, metadata, now); cacheErr != nil {
+		log.Printf("Erro ao guardar no cache de dados bibliográficos (ISBN %s): %v", isbn, cacheErr)
+	}
+	return metadata, err
+}
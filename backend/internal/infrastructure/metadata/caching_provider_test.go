@@ -0,0 +1,100 @@
+package metadata
+
+import (
+	"errors"
+	"library-management/internal/domain"
+	"testing"
+	"time"
+)
+
+// cachedResponse é uma resposta guardada no memoryCache
+type cachedResponse struct {
+	metadata  *domain.BookMetadata
+	fetchedAt time.Time
+}
+
+// memoryCache implementa domain.MetadataCacheRepository em memória
+type memoryCache map[string]cachedResponse
+
+func (c memoryCache) Get(isbn string) (*domain.BookMetadata, time.Time, error) {
+	response, ok := c[isbn]
+	if !ok {
+		return nil, time.Time{}, domain.ErrNotFound
+	}
+	return response.metadata, response.fetchedAt, nil
+}
+
+func (c memoryCache) Put(isbn string, metadata *domain.BookMetadata, fetchedAt time.Time) error {
+	c[isbn] = cachedResponse{metadata, fetchedAt}
+	return nil
+}
+
+const testISBN = "9788535902778"
+
+func TestCachingProviderFound(t *testing.T) {
+	found := &domain.BookMetadata{ISBN: testISBN, Title: "Dom Casmurro", Source: "stub"}
+	next := &stubProvider{metadata: found}
+	cache := memoryCache{}
+	provider := NewCachingProvider(next, cache, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if got, err := provider.LookupISBN(testISBN); err != nil || got.Title != found.Title {
+			t.Fatalf("consulta %d = %+v, %v, esperado %+v", i+1, got, err, found)
+		}
+	}
+	if next.calls != 1 {
+		t.Errorf("fonte consultada %d vez(es), esperado 1", next.calls)
+	}
+
+	// Vencido o ttl, a fonte é consultada de novo
+	cache[testISBN] = cachedResponse{found, time.Now().Add(-2 * time.Hour)}
+	if _, err := provider.LookupISBN(testISBN); err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if next.calls != 2 {
+		t.Errorf("fonte consultada %d vez(es) depois do ttl, esperado 2", next.calls)
+	}
+}
+
+func TestCachingProviderNotFound(t *testing.T) {
+	next := &stubProvider{err: domain.ErrMetadataNotFound}
+	cache := memoryCache{}
+	provider := NewCachingProvider(next, cache, 30*24*time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if _, err := provider.LookupISBN(testISBN); !errors.Is(err, domain.ErrMetadataNotFound) {
+			t.Fatalf("consulta %d: erro = %v, esperado %v", i+1, err, domain.ErrMetadataNotFound)
+		}
+	}
+	if next.calls != 1 {
+		t.Errorf("fonte consultada %d vez(es), esperado 1", next.calls)
+	}
+	if response, ok := cache[testISBN]; !ok || response.metadata != nil {
+		t.Errorf("cache = %+v, esperado a resposta de ISBN desconhecido", response)
+	}
+
+	// O não encontrado vale por NotFoundTTL, não pelo ttl das respostas com dados
+	cache[testISBN] = cachedResponse{nil, time.Now().Add(-NotFoundTTL - time.Minute)}
+	provider.LookupISBN(testISBN)
+	if next.calls != 2 {
+		t.Errorf("fonte consultada %d vez(es) depois do NotFoundTTL, esperado 2", next.calls)
+	}
+}
+
+func TestCachingProviderFailure(t *testing.T) {
+	next := &stubProvider{err: errOffline}
+	cache := memoryCache{}
+	provider := NewCachingProvider(next, cache, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if _, err := provider.LookupISBN(testISBN); !errors.Is(err, domain.ErrMetadataUnavailable) {
+			t.Fatalf("consulta %d: erro = %v, esperado %v", i+1, err, domain.ErrMetadataUnavailable)
+		}
+	}
+	if next.calls != 2 {
+		t.Errorf("fonte consultada %d vez(es), esperado 2: falhas não são guardadas", next.calls)
+	}
+	if _, ok := cache[testISBN]; ok {
+		t.Error("a falha da fonte foi guardada no cache")
+	}
+}
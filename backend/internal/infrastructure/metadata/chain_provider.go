@@ -0,0 +1,38 @@
+package metadata
+
+import (
+	"errors"
+	"library-management/internal/domain"
+)
+
+// ChainProvider implementa domain.MetadataProvider consultando várias fontes
+// em ordem e retornando a primeira que conhece o ISBN
+type ChainProvider struct {
+	providers []domain.MetadataProvider
+}
+
+// NewChainProvider cria uma nova instância do ChainProvider
+func NewChainProvider(providers ...domain.MetadataProvider) *ChainProvider {
+	return &ChainProvider{providers: providers}
+}
+
+// LookupISBN consulta as fontes em ordem. Uma fonte que falha não interrompe
+// a busca; se nenhuma conhecer o ISBN e alguma tiver falhado, retorna a falha,
+// já que a fonte indisponível poderia conhecê-lo.
+func (p *ChainProvider) LookupISBN(isbn string) (*domain.BookMetadata, error) {
+	var failure error
+	for _, provider := range p.providers {
+		metadata, err := provider.LookupISBN(isbn)
+		if err == nil {
+			return metadata, nil
+		}
+		if !errors.Is(err, domain.ErrMetadataNotFound) {
+			failure = err
+		}
+	}
+
+	if failure != nil {
+		return nil, failure
+	}
+	return nil, domain.ErrMetadataNotFound
+}
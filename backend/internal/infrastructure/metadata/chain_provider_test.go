@@ -0,0 +1,61 @@
+package metadata
+
+import (
+	"errors"
+	"library-management/internal/domain"
+	"testing"
+)
+
+// stubProvider responde sempre com metadata e err, contando as consultas
+type stubProvider struct {
+	metadata *domain.BookMetadata
+	err      error
+	calls    int
+}
+
+func (p *stubProvider) LookupISBN(isbn string) (*domain.BookMetadata, error) {
+	p.calls++
+	return p.metadata, p.err
+}
+
+// errOffline simula uma fonte fora do ar
+var errOffline = domain.ErrMetadataUnavailable.WithCause(errors.New("conexão recusada"))
+
+func TestChainProvider(t *testing.T) {
+	found := &domain.BookMetadata{ISBN: "9788535902778", Title: "Dom Casmurro", Source: "stub"}
+	tests := []struct {
+		name      string
+		providers []*stubProvider
+		want      *domain.BookMetadata
+		err       error
+		calls     []int
+	}{
+		{"primeira fonte conhece", []*stubProvider{{metadata: found}, {metadata: found}}, found, nil, []int{1, 0}},
+		{"segunda fonte conhece", []*stubProvider{{err: domain.ErrMetadataNotFound}, {metadata: found}}, found, nil, []int{1, 1}},
+		{"fonte fora do ar é pulada", []*stubProvider{{err: errOffline}, {metadata: found}}, found, nil, []int{1, 1}},
+		{"nenhuma conhece", []*stubProvider{{err: domain.ErrMetadataNotFound}, {err: domain.ErrMetadataNotFound}}, nil, domain.ErrMetadataNotFound, []int{1, 1}},
+		{"falha vence o não encontrado", []*stubProvider{{err: errOffline}, {err: domain.ErrMetadataNotFound}}, nil, domain.ErrMetadataUnavailable, []int{1, 1}},
+		{"sem fontes", nil, nil, domain.ErrMetadataNotFound, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providers := make([]domain.MetadataProvider, len(tt.providers))
+			for i, p := range tt.providers {
+				providers[i] = p
+			}
+			got, err := NewChainProvider(providers...).LookupISBN("9788535902778")
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("erro = %v, esperado %v", err, tt.err)
+				}
+			} else if err != nil || got != tt.want {
+				t.Errorf("LookupISBN = %+v, %v, esperado %+v", got, err, tt.want)
+			}
+			for i, p := range tt.providers {
+				if p.calls != tt.calls[i] {
+					t.Errorf("fonte %d consultada %d vez(es), esperado %d", i, p.calls, tt.calls[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"library-management/internal/domain"
+	"os"
+)
+
+// FileProvider implementa domain.MetadataProvider a partir de um arquivo JSON
+// local, útil em testes e em instalações sem acesso à internet. O arquivo é
+// uma lista de domain.BookMetadata; o ISBN de cada item pode estar em qualquer
+// formato aceito por domain.NormalizeISBN.
+type FileProvider struct {
+	books map[string]*domain.BookMetadata
+}
+
+// NewFileProvider lê o arquivo e cria uma nova instância do FileProvider
+func NewFileProvider(path string) (*FileProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*domain.BookMetadata
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	books := make(map[string]*domain.BookMetadata, len(entries))
+	for i, entry := range entries {
+		isbn, err := domain.NormalizeISBN(entry.ISBN)
+		if err != nil {
+			return nil, fmt.Errorf("%s: item %d: ISBN inválido %q", path, i+1, entry.ISBN)
+		}
+		entry.ISBN = isbn
+		if entry.Source == "" {
+			entry.Source = "file"
+		}
+		books[isbn] = entry
+	}
+	return &FileProvider{books: books}, nil
+}
+
+// LookupISBN busca os dados do livro no arquivo
+func (p *FileProvider) LookupISBN(isbn string) (*domain.BookMetadata, error) {
+	book, ok := p.books[isbn]
+	if !ok {
+		return nil, domain.ErrMetadataNotFound
+	}
+	copy := *book
+	return &copy, nil
+}
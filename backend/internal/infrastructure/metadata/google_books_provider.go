@@ -0,0 +1,83 @@
+package metadata
+
+import (
+	"library-management/internal/domain"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GoogleBooksURL é o endereço padrão da API do Google Books
+const GoogleBooksURL = "https://www.googleapis.com/books/v1"
+
+// GoogleBooksProvider implementa domain.MetadataProvider usando a busca de
+// volumes do Google Books (q=isbn:...)
+type GoogleBooksProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+// NewGoogleBooksProvider cria uma nova instância do GoogleBooksProvider.
+// baseURL vazio usa GoogleBooksURL; apiKey é opcional (sem ela a cota de
+// consultas é menor).
+func NewGoogleBooksProvider(client *http.Client, baseURL, apiKey string) *GoogleBooksProvider {
+	if baseURL == "" {
+		baseURL = GoogleBooksURL
+	}
+	return &GoogleBooksProvider{client: client, baseURL: strings.TrimRight(baseURL, "/"), apiKey: apiKey}
+}
+
+// googleBooksResponse é a resposta da busca de volumes do Google Books
+type googleBooksResponse struct {
+	TotalItems int `json:"totalItems"`
+	Items      []struct {
+		VolumeInfo struct {
+			Title         string   `json:"title"`
+			Subtitle      string   `json:"subtitle"`
+			Authors       []string `json:"authors"`
+			Publisher     string   `json:"publisher"`
+			PublishedDate string   `json:"publishedDate"`
+			ImageLinks    struct {
+				SmallThumbnail string `json:"smallThumbnail"`
+				Thumbnail      string `json:"thumbnail"`
+			} `json:"imageLinks"`
+		} `json:"volumeInfo"`
+	} `json:"items"`
+}
+
+// LookupISBN busca os dados do livro no Google Books
+func (p *GoogleBooksProvider) LookupISBN(isbn string) (*domain.BookMetadata, error) {
+	query := url.Values{"q": {"isbn:" + isbn}}
+	if p.apiKey != "" {
+		query.Set("key", p.apiKey)
+	}
+
+	var response googleBooksResponse
+	if err := getJSON(p.client, p.baseURL+"/volumes?"+query.Encode(), &response); err != nil {
+		return nil, err
+	}
+	if len(response.Items) == 0 {
+		return nil, domain.ErrMetadataNotFound
+	}
+
+	info := response.Items[0].VolumeInfo
+	metadata := &domain.BookMetadata{
+		ISBN:          isbn,
+		Title:         info.Title,
+		Authors:       info.Authors,
+		YearPublished: parseYear(info.PublishedDate),
+		Publisher:     info.Publisher,
+		CoverURL:      info.ImageLinks.Thumbnail,
+		Source:        "googlebooks",
+	}
+	if info.Subtitle != "" {
+		metadata.Title += ": " + info.Subtitle
+	}
+	if metadata.CoverURL == "" {
+		metadata.CoverURL = info.ImageLinks.SmallThumbnail
+	}
+	// O Google Books devolve as capas por http; o https funciona no mesmo endereço
+	metadata.CoverURL = strings.Replace(metadata.CoverURL, "http://", "https://", 1)
+	return metadata, nil
+}
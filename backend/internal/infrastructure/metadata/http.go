@@ -0,0 +1,70 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"library-management/internal/domain"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// DefaultTimeout é o tempo máximo de espera por uma fonte externa
+const DefaultTimeout = 10 * time.Second
+
+// maxResponseSize limita o corpo lido de uma fonte externa; respostas maiores
+// ficam truncadas e falham na decodificação
+const maxResponseSize = 2 << 20
+
+// NewHTTPClient cria o cliente HTTP usado pelas fontes externas
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+// getJSON faz um GET e decodifica a resposta JSON em dest. Falhas de rede e
+// respostas de erro viram domain.ErrMetadataUnavailable com a causa anexada;
+// 404 vira domain.ErrMetadataNotFound.
+func getJSON(client *http.Client, url string, dest interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return domain.ErrMetadataUnavailable.WithCause(err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return domain.ErrMetadataNotFound
+	case resp.StatusCode != http.StatusOK:
+		return domain.ErrMetadataUnavailable.WithCause(fmt.Errorf("GET %s: status %d", url, resp.StatusCode))
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(dest); err != nil {
+		return domain.ErrMetadataUnavailable.WithCause(fmt.Errorf("GET %s: %w", url, err))
+	}
+	return nil
+}
+
+// yearRegex encontra um ano de quatro dígitos em datas como "1899",
+// "March 2003" ou "2003-05-01"
+var yearRegex = regexp.MustCompile(`\b(1[0-9]{3}|20[0-9]{2})\b`)
+
+// parseYear extrai o ano de uma data em formato livre, ou 0 se não houver
+func parseYear(date string) int {
+	match := yearRegex.FindString(date)
+	if match == "" {
+		return 0
+	}
+	year, _ := strconv.Atoi(match)
+	return year
+}
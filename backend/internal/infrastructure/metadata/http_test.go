@@ -0,0 +1,46 @@
+package metadata
+
+import (
+	"errors"
+	"library-management/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		err    error
+	}{
+		{"resposta válida", http.StatusOK, `{"title":"Dom Casmurro"}`, nil},
+		{"não encontrado", http.StatusNotFound, ``, domain.ErrMetadataNotFound},
+		{"erro do servidor", http.StatusBadGateway, ``, domain.ErrMetadataUnavailable},
+		{"JSON inválido", http.StatusOK, `{"title":`, domain.ErrMetadataUnavailable},
+		{"resposta grande demais", http.StatusOK, `{"title":"` + strings.Repeat("a", maxResponseSize) + `"}`, domain.ErrMetadataUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			var dest struct{ Title string }
+			err := getJSON(server.Client(), server.URL, &dest)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("erro = %v, esperado %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil || dest.Title != "Dom Casmurro" {
+				t.Errorf("getJSON = %+v, %v, esperado Dom Casmurro", dest, err)
+			}
+		})
+	}
+}
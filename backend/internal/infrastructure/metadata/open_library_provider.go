@@ -0,0 +1,82 @@
+package metadata
+
+import (
+	"library-management/internal/domain"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// OpenLibraryURL é o endereço padrão da API da Open Library
+const OpenLibraryURL = "https://openlibrary.org"
+
+// OpenLibraryProvider implementa domain.MetadataProvider usando a API de
+// livros da Open Library (/api/books com jscmd=data)
+type OpenLibraryProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewOpenLibraryProvider cria uma nova instância do OpenLibraryProvider.
+// baseURL vazio usa OpenLibraryURL.
+func NewOpenLibraryProvider(client *http.Client, baseURL string) *OpenLibraryProvider {
+	if baseURL == "" {
+		baseURL = OpenLibraryURL
+	}
+	return &OpenLibraryProvider{client: client, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// openLibraryName é um autor ou editora na resposta da Open Library
+type openLibraryName struct {
+	Name string `json:"name"`
+}
+
+// openLibraryBook é um livro na resposta da Open Library
+type openLibraryBook struct {
+	Title       string            `json:"title"`
+	Subtitle    string            `json:"subtitle"`
+	Authors     []openLibraryName `json:"authors"`
+	Publishers  []openLibraryName `json:"publishers"`
+	PublishDate string            `json:"publish_date"`
+	Cover       struct {
+		Medium string `json:"medium"`
+		Large  string `json:"large"`
+	} `json:"cover"`
+}
+
+// LookupISBN busca os dados do livro na Open Library
+func (p *OpenLibraryProvider) LookupISBN(isbn string) (*domain.BookMetadata, error) {
+	key := "ISBN:" + isbn
+	query := url.Values{"bibkeys": {key}, "format": {"json"}, "jscmd": {"data"}}
+
+	// A resposta é um objeto indexado pela chave pedida; vazio se o ISBN não existir
+	var response map[string]openLibraryBook
+	if err := getJSON(p.client, p.baseURL+"/api/books?"+query.Encode(), &response); err != nil {
+		return nil, err
+	}
+	book, ok := response[key]
+	if !ok {
+		return nil, domain.ErrMetadataNotFound
+	}
+
+	metadata := &domain.BookMetadata{
+		ISBN:          isbn,
+		Title:         book.Title,
+		YearPublished: parseYear(book.PublishDate),
+		CoverURL:      book.Cover.Large,
+		Source:        "openlibrary",
+	}
+	if book.Subtitle != "" {
+		metadata.Title += ": " + book.Subtitle
+	}
+	for _, author := range book.Authors {
+		metadata.Authors = append(metadata.Authors, author.Name)
+	}
+	if len(book.Publishers) > 0 {
+		metadata.Publisher = book.Publishers[0].Name
+	}
+	if metadata.CoverURL == "" {
+		metadata.CoverURL = book.Cover.Medium
+	}
+	return metadata, nil
+}
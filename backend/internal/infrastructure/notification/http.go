@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"library-management/internal/domain"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// DefaultHTTPTimeout é o tempo máximo de espera pelo gateway de SMS e pelos webhooks
+const DefaultHTTPTimeout = 10 * time.Second
+
+// NewHTTPClient cria o cliente HTTP usado pelo gateway de SMS e pelos webhooks
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = DefaultHTTPTimeout
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+// NewPublicHTTPClient cria o cliente HTTP dos webhooks, cujos endereços são
+// informados pelos leitores. Ele só se conecta a endereços públicos: o IP é
+// conferido na conexão, depois da resolução do nome, para que um nome
+// apontando para a rede interna também seja recusado. Os redirecionamentos
+// não são seguidos e o proxy do ambiente não é usado, pois ambos levariam a
+// conexão para um destino não conferido.
+func NewPublicHTTPClient(timeout time.Duration) *http.Client {
+	client := NewHTTPClient(timeout)
+	dialer := &net.Dialer{Timeout: client.Timeout, Control: publicOnly}
+	client.Transport = &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: client.Timeout,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return client
+}
+
+// publicOnly recusa as conexões para endereços que não são públicos
+func publicOnly(network, address string, c syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !domain.IsPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("conexão com o endereço não público %s recusada", addrPort.Addr())
+	}
+	return nil
+}
+
+// postJSON envia payload em JSON para url com os cabeçalhos informados. Uma
+// resposta fora da faixa 2xx, inclusive um redirecionamento não seguido, é um
+// erro.
+func postJSON(client *http.Client, url string, payload interface{}, headers func(body []byte, header http.Header)) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if headers != nil {
+		headers(body, req.Header)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("POST %s: status %d", url, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package notification
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPublicHTTPClient(t *testing.T) {
+	var received bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	defer server.Close()
+	client := NewPublicHTTPClient(time.Second)
+
+	// O servidor de teste escuta em 127.0.0.1; o nome localhost também é
+	// conferido depois de resolvido
+	urls := []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1), "http://[::1]:9/", "http://169.254.169.254/"}
+	for _, url := range urls {
+		if err := postJSON(client, url, map[string]string{}, nil); err == nil || !strings.Contains(err.Error(), "não público") {
+			t.Errorf("postJSON(%s): erro = %v, esperado a recusa do endereço", url, err)
+		}
+	}
+	if received {
+		t.Error("o servidor na rede interna recebeu o webhook")
+	}
+}
+
+func TestPublicHTTPClientRedirect(t *testing.T) {
+	client := NewPublicHTTPClient(time.Second)
+	if err := client.CheckRedirect(nil, nil); err != http.ErrUseLastResponse {
+		t.Errorf("CheckRedirect = %v, esperado %v", err, http.ErrUseLastResponse)
+	}
+}
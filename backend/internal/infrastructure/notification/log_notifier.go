@@ -0,0 +1,23 @@
+package notification
+
+import (
+	"library-management/internal/domain"
+	"log"
+)
+
+// LogNotifier implementa domain.Notifier apenas registrando as mensagens no
+// log; é usado no lugar dos canais que não estão configurados
+type LogNotifier struct {
+	channel domain.NotificationChannel
+}
+
+// NewLogNotifier cria uma nova instância do LogNotifier para o canal informado
+func NewLogNotifier(channel domain.NotificationChannel) *LogNotifier {
+	return &LogNotifier{channel: channel}
+}
+
+// Send registra a mensagem no log
+func (n *LogNotifier) Send(message domain.Message) error {
+	log.Printf("Aviso (%s) para %s: %s", n.channel, message.To, message.Subject)
+	return nil
+}
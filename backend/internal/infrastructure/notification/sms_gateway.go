@@ -0,0 +1,36 @@
+package notification
+
+import (
+	"library-management/internal/domain"
+	"net/http"
+)
+
+// SMSGateway implementa domain.Notifier enviando o resumo do aviso por SMS
+// através de um gateway HTTP, com um POST em JSON {"to": ..., "message": ...}.
+// Outros provedores podem ser ligados implementando domain.Notifier.
+type SMSGateway struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+// NewSMSGateway cria uma nova instância do SMSGateway. token é opcional e,
+// se informado, vai no cabeçalho Authorization como Bearer.
+func NewSMSGateway(client *http.Client, url, token string) *SMSGateway {
+	return &SMSGateway{client: client, url: url, token: token}
+}
+
+// smsRequest é o corpo enviado ao gateway
+type smsRequest struct {
+	To      string `json:"to"`
+	Message string `json:"message"`
+}
+
+// Send envia o resumo da mensagem para o telefone do destinatário
+func (g *SMSGateway) Send(message domain.Message) error {
+	return postJSON(g.client, g.url, smsRequest{To: message.To, Message: message.Summary}, func(_ []byte, header http.Header) {
+		if g.token != "" {
+			header.Set("Authorization", "Bearer "+g.token)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package notification
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"library-management/internal/domain"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSMTPTimeout é o tempo máximo de uma conversa com o servidor SMTP
+const DefaultSMTPTimeout = 30 * time.Second
+
+// SMTPConfig reúne os dados de acesso ao servidor SMTP
+type SMTPConfig struct {
+	Host string
+	Port int
+	// Username e Password são opcionais; sem eles o envio não se autentica
+	Username string
+	Password string
+	// From é o remetente, com ou sem nome (ex.: "Biblioteca <avisos@biblioteca.org>")
+	From    string
+	Timeout time.Duration
+}
+
+// SMTPNotifier implementa domain.Notifier enviando email por SMTP. A conexão
+// passa para TLS (STARTTLS) quando o servidor oferece.
+type SMTPNotifier struct {
+	config SMTPConfig
+	from   *mail.Address
+}
+
+// NewSMTPNotifier cria uma nova instância do SMTPNotifier
+func NewSMTPNotifier(config SMTPConfig) (*SMTPNotifier, error) {
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("remetente inválido %q: %w", config.From, err)
+	}
+	if config.Port == 0 {
+		config.Port = 25
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultSMTPTimeout
+	}
+	return &SMTPNotifier{config: config, from: from}, nil
+}
+
+// Send entrega a mensagem ao servidor SMTP
+func (n *SMTPNotifier) Send(message domain.Message) error {
+	data, err := n.compose(message)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	conn, err := net.DialTimeout("tcp", addr, n.config.Timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(n.config.Timeout)); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, n.config.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.config.Host}); err != nil {
+			return err
+		}
+	}
+	if n.config.Username != "" {
+		auth := smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// compose monta a mensagem de email: cabeçalhos e texto em UTF-8, com o
+// texto codificado em quoted-printable
+func (n *SMTPNotifier) compose(message domain.Message) ([]byte, error) {
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return nil, fmt.Errorf("destinatário inválido %q: %w", message.To, err)
+	}
+
+	var buf bytes.Buffer
+	headers := [][2]string{
+		{"From", n.from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", message.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=UTF-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	if message.ID != "" {
+		_, domainPart, _ := strings.Cut(n.from.Address, "@")
+		headers = append(headers, [2]string{"Message-ID", "<" + message.ID + "@" + domainPart + ">"})
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", header[0], header[1])
+	}
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(message.Body)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
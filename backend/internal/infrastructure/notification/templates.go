@@ -0,0 +1,116 @@
+// Package notification implementa o envio de avisos aos leitores: os modelos
+// de mensagem em cada idioma e os canais de envio (email via SMTP, SMS por um
+// gateway HTTP e webhook).
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"library-management/internal/domain"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultLanguage é o idioma usado quando o idioma pedido não tem modelos
+const DefaultLanguage = "pt-BR"
+
+// Os modelos ficam em templates/<idioma>/<tipo>.txt e definem os blocos
+// "subject", "summary" (o resumo de uma linha) e "body"
+//
+//go:embed templates/*/*.txt
+var templateFiles embed.FS
+
+// templateBlocks são os blocos que todo modelo define
+var templateBlocks = []string{"subject", "summary", "body"}
+
+// dateLayouts é o formato de data de cada idioma nas mensagens
+var dateLayouts = map[string]string{
+	"pt-BR": "02/01/2006",
+	"en":    "January 2, 2006",
+}
+
+// templates guarda os modelos de cada idioma, por tipo de aviso
+var templates = loadTemplates()
+
+func loadTemplates() map[string]map[domain.NotificationKind]*template.Template {
+	languages, err := templateFiles.ReadDir("templates")
+	if err != nil {
+		panic(err)
+	}
+
+	templates := make(map[string]map[domain.NotificationKind]*template.Template)
+	for _, language := range languages {
+		dir := path.Join("templates", language.Name())
+		entries, err := templateFiles.ReadDir(dir)
+		if err != nil {
+			panic(err)
+		}
+
+		layout := dateLayouts[language.Name()]
+		funcs := template.FuncMap{
+			"date": func(t time.Time) string { return t.Local().Format(layout) },
+		}
+		byKind := make(map[domain.NotificationKind]*template.Template)
+		for _, entry := range entries {
+			data, err := templateFiles.ReadFile(path.Join(dir, entry.Name()))
+			if err != nil {
+				panic(err)
+			}
+			tmpl, err := template.New(entry.Name()).Funcs(funcs).Option("missingkey=error").Parse(string(data))
+			if err != nil {
+				panic(fmt.Sprintf("modelo %s/%s inválido: %v", language.Name(), entry.Name(), err))
+			}
+			for _, block := range templateBlocks {
+				if tmpl.Lookup(block) == nil {
+					panic(fmt.Sprintf("modelo %s/%s sem o bloco %q", language.Name(), entry.Name(), block))
+				}
+			}
+			byKind[domain.NotificationKind(strings.TrimSuffix(entry.Name(), ".txt"))] = tmpl
+		}
+		templates[language.Name()] = byKind
+	}
+	return templates
+}
+
+// TemplateRenderer implementa domain.MessageRenderer com os modelos embutidos
+type TemplateRenderer struct{}
+
+// NewTemplateRenderer cria uma nova instância do TemplateRenderer
+func NewTemplateRenderer() *TemplateRenderer {
+	return &TemplateRenderer{}
+}
+
+// Render gera o texto do aviso. Um idioma sem modelos usa DefaultLanguage.
+func (r *TemplateRenderer) Render(kind domain.NotificationKind, language string, data domain.NoticeData) (*domain.RenderedNotice, error) {
+	byKind, ok := templates[language]
+	if !ok {
+		byKind = templates[DefaultLanguage]
+	}
+	tmpl, ok := byKind[kind]
+	if !ok {
+		return nil, fmt.Errorf("sem modelo para o aviso %q", kind)
+	}
+
+	parts := make(map[string]string, len(templateBlocks))
+	for _, name := range templateBlocks {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+			return nil, err
+		}
+		parts[name] = strings.TrimSpace(buf.String())
+	}
+	return &domain.RenderedNotice{
+		Subject: parts["subject"],
+		Summary: parts["summary"],
+		Body:    parts["body"] + "\n",
+	}, nil
+}
+
+// SupportsLanguage indica se há modelos no idioma informado
+func SupportsLanguage(language string) bool {
+	_, ok := templates[language]
+	return ok
+}
@@ -0,0 +1,56 @@
+package notification
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"library-management/internal/domain"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader é o cabeçalho com a assinatura HMAC-SHA256 do corpo do webhook
+const SignatureHeader = "X-Library-Signature"
+
+// WebhookNotifier implementa domain.Notifier enviando o aviso em JSON para o
+// endereço escolhido pelo leitor. Com um segredo configurado, o corpo é
+// assinado em SignatureHeader ("sha256=<hex>") para que o destino possa
+// conferir a origem.
+type WebhookNotifier struct {
+	client *http.Client
+	secret []byte
+}
+
+// NewWebhookNotifier cria uma nova instância do WebhookNotifier; secret é opcional
+func NewWebhookNotifier(client *http.Client, secret string) *WebhookNotifier {
+	return &WebhookNotifier{client: client, secret: []byte(secret)}
+}
+
+// webhookPayload é o corpo enviado ao webhook
+type webhookPayload struct {
+	ID      string                  `json:"id"`
+	Kind    domain.NotificationKind `json:"kind"`
+	Subject string                  `json:"subject"`
+	Summary string                  `json:"summary"`
+	Body    string                  `json:"body"`
+	SentAt  time.Time               `json:"sent_at"`
+}
+
+// Send envia a mensagem para o endereço do destinatário
+func (n *WebhookNotifier) Send(message domain.Message) error {
+	payload := webhookPayload{
+		ID:      message.ID,
+		Kind:    message.Kind,
+		Subject: message.Subject,
+		Summary: message.Summary,
+		Body:    message.Body,
+		SentAt:  time.Now(),
+	}
+	return postJSON(n.client, message.To, payload, func(body []byte, header http.Header) {
+		if len(n.secret) > 0 {
+			mac := hmac.New(sha256.New, n.secret)
+			mac.Write(body)
+			header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		}
+	})
+}
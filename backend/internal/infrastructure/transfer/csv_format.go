@@ -0,0 +1,144 @@
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"library-management/internal/domain"
+)
+
+// CSVFormat implementa domain.RecordFormat para arquivos CSV com cabeçalho.
+// O separador (vírgula ou ponto e vírgula, comum em planilhas em português)
+// é detectado pelo cabeçalho.
+type CSVFormat struct{}
+
+// ContentType é o tipo MIME do CSV
+func (CSVFormat) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+// Extension é a extensão dos arquivos CSV
+func (CSVFormat) Extension() string {
+	return "csv"
+}
+
+// NewReader lê o cabeçalho e cria o leitor dos registros
+func (CSVFormat) NewReader(r io.Reader, mapping map[string]string) (domain.RecordReader, error) {
+	buffered := bufio.NewReader(r)
+	header, _ := buffered.Peek(4096)
+	if line, _, ok := bytes.Cut(header, []byte("\n")); ok {
+		header = line
+	}
+
+	reader := csv.NewReader(buffered)
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, invalidFile(1, errors.New("arquivo vazio"))
+	}
+	if err != nil {
+		return nil, invalidFile(1, err)
+	}
+
+	names := columnNames(mapping)
+	fields := make([]string, len(columns))
+	for i, column := range columns {
+		fields[i] = fieldName(names, column)
+	}
+	return &csvReader{reader: reader, fields: fields}, nil
+}
+
+// csvReader lê os registros de um CSV, uma linha por vez
+type csvReader struct {
+	reader *csv.Reader
+	fields []string
+}
+
+// Read retorna o próximo registro, ignorando linhas em branco
+func (r *csvReader) Read() (*domain.Record, error) {
+	values, err := r.reader.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	line, _ := r.reader.FieldPos(0)
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			line = parseErr.StartLine
+		}
+		return nil, invalidFile(line, err)
+	}
+
+	record := &domain.Record{Row: line, Fields: make(map[string]string, len(r.fields))}
+	for i, value := range values {
+		if i < len(r.fields) {
+			record.Fields[r.fields[i]] = value
+		}
+	}
+	return record, nil
+}
+
+// NewWriter cria o escritor, gravando o cabeçalho com as colunas
+func (CSVFormat) NewWriter(w io.Writer, columns []string) domain.RecordWriter {
+	return &csvWriter{out: w, writer: csv.NewWriter(w), columns: columns}
+}
+
+// csvWriter grava os registros em CSV
+type csvWriter struct {
+	out     io.Writer
+	writer  *csv.Writer
+	columns []string
+	count   int
+}
+
+// Write grava um registro, precedido do cabeçalho se for o primeiro
+func (w *csvWriter) Write(values map[string]interface{}) error {
+	if w.count == 0 {
+		if err := w.writer.Write(w.columns); err != nil {
+			return err
+		}
+	}
+
+	row := make([]string, len(w.columns))
+	for i, column := range w.columns {
+		row[i] = formatValue(values[column])
+	}
+	if err := w.writer.Write(row); err != nil {
+		return err
+	}
+
+	w.count++
+	if w.count%flushEvery == 0 {
+		return w.flush()
+	}
+	return nil
+}
+
+// Close grava o cabeçalho, se nenhum registro foi gravado, e envia o restante
+func (w *csvWriter) Close() error {
+	if w.count == 0 {
+		if err := w.writer.Write(w.columns); err != nil {
+			return err
+		}
+	}
+	return w.flush()
+}
+
+// flush envia o que já foi gravado
+func (w *csvWriter) flush() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		return err
+	}
+	if f, ok := w.out.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
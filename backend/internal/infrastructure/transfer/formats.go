@@ -0,0 +1,82 @@
+// Package transfer implementa os formatos de arquivo (CSV e JSON) usados na
+// importação e exportação em lote.
+package transfer
+
+import (
+	"fmt"
+	"library-management/internal/domain"
+	"strings"
+	"time"
+)
+
+// flushEvery é a cada quantos registros os escritores enviam o que já foi
+// gravado, para que a exportação chegue ao cliente aos poucos
+const flushEvery = 100
+
+// Formats retorna os formatos disponíveis para qualquer tipo de registro,
+// indexados pelo nome
+func Formats() map[string]domain.RecordFormat {
+	return map[string]domain.RecordFormat{
+		"csv":  CSVFormat{},
+		"json": JSONFormat{},
+	}
+}
+
+// CatalogFormats retorna os formatos disponíveis para o acervo: os de
+// Formats e os bibliográficos (MARC 21 em ISO 2709 e MARCXML)
+func CatalogFormats() map[string]domain.RecordFormat {
+	formats := Formats()
+	formats["marc"] = MARCFormat{}
+	formats["marcxml"] = MARCXMLFormat{}
+	return formats
+}
+
+// flusher é implementado por escritores com buffer, como *bufio.Writer
+type flusher interface {
+	Flush() error
+}
+
+// normalizeName padroniza um nome de coluna para comparação, removendo a
+// marca de ordem de bytes (BOM) que planilhas gravam no início do arquivo
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))
+}
+
+// columnNames inverte o mapeamento campo → coluna para coluna → campo, com
+// os nomes de coluna padronizados
+func columnNames(mapping map[string]string) map[string]string {
+	names := make(map[string]string, len(mapping))
+	for field, column := range mapping {
+		names[normalizeName(column)] = field
+	}
+	return names
+}
+
+// fieldName retorna o campo correspondente a uma coluna do arquivo
+func fieldName(names map[string]string, column string) string {
+	column = normalizeName(column)
+	if field, ok := names[column]; ok {
+		return field
+	}
+	return column
+}
+
+// invalidFile cria o erro de arquivo malformado com a posição do problema
+func invalidFile(row int, err error) error {
+	return domain.ErrInvalidFile.WithParams(map[string]interface{}{"row": row}).
+		WithCause(fmt.Errorf("registro %d: %w", row, err))
+}
+
+// formatValue converte um valor exportado em texto
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, domain.ValueSeparator+" ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,163 @@
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"library-management/internal/domain"
+	"strconv"
+	"strings"
+)
+
+// JSONFormat implementa domain.RecordFormat para arquivos com uma lista JSON
+// de objetos. Os valores podem ser texto, número, booleano ou lista de
+// textos (unida por domain.ValueSeparator, como os assuntos).
+type JSONFormat struct{}
+
+// ContentType é o tipo MIME do JSON
+func (JSONFormat) ContentType() string {
+	return "application/json"
+}
+
+// Extension é a extensão dos arquivos JSON
+func (JSONFormat) Extension() string {
+	return "json"
+}
+
+// NewReader lê o início da lista e cria o leitor dos registros
+func (JSONFormat) NewReader(r io.Reader, mapping map[string]string) (domain.RecordReader, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, invalidFile(0, err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, invalidFile(0, errors.New("o arquivo deve conter uma lista de objetos"))
+	}
+	return &jsonReader{decoder: decoder, names: columnNames(mapping)}, nil
+}
+
+// jsonReader lê os objetos de uma lista JSON, um por vez
+type jsonReader struct {
+	decoder *json.Decoder
+	names   map[string]string
+	row     int
+}
+
+// Read retorna o próximo objeto da lista
+func (r *jsonReader) Read() (*domain.Record, error) {
+	if !r.decoder.More() {
+		if _, err := r.decoder.Token(); err != nil {
+			return nil, invalidFile(r.row+1, err)
+		}
+		return nil, io.EOF
+	}
+
+	r.row++
+	var object map[string]interface{}
+	if err := r.decoder.Decode(&object); err != nil {
+		return nil, invalidFile(r.row, err)
+	}
+
+	record := &domain.Record{Row: r.row, Fields: make(map[string]string, len(object))}
+	for key, value := range object {
+		text, err := jsonText(value)
+		if err != nil {
+			return nil, invalidFile(r.row, fmt.Errorf("%s: %w", key, err))
+		}
+		record.Fields[fieldName(r.names, key)] = text
+	}
+	return record, nil
+}
+
+// jsonText converte um valor JSON em texto
+func jsonText(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			text, ok := item.(string)
+			if !ok {
+				return "", errors.New("listas só podem conter textos")
+			}
+			parts[i] = text
+		}
+		return strings.Join(parts, domain.ValueSeparator+" "), nil
+	}
+	return "", errors.New("valor não suportado")
+}
+
+// NewWriter cria o escritor de uma lista JSON com as colunas informadas
+func (JSONFormat) NewWriter(w io.Writer, columns []string) domain.RecordWriter {
+	return &jsonWriter{out: w, columns: columns}
+}
+
+// jsonWriter grava os registros como uma lista JSON, um objeto por linha,
+// com as chaves na ordem das colunas
+type jsonWriter struct {
+	out     io.Writer
+	columns []string
+	count   int
+}
+
+// Write grava um registro
+func (w *jsonWriter) Write(values map[string]interface{}) error {
+	var b strings.Builder
+	if w.count == 0 {
+		b.WriteString("[\n")
+	} else {
+		b.WriteString(",\n")
+	}
+
+	b.WriteByte('{')
+	for i, column := range w.columns {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(column)
+		value, err := json.Marshal(values[column])
+		if err != nil {
+			return err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+
+	if _, err := io.WriteString(w.out, b.String()); err != nil {
+		return err
+	}
+
+	w.count++
+	if f, ok := w.out.(flusher); ok && w.count%flushEvery == 0 {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close fecha a lista e envia o restante
+func (w *jsonWriter) Close() error {
+	end := "\n]\n"
+	if w.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := io.WriteString(w.out, end); err != nil {
+		return err
+	}
+	if f, ok := w.out.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
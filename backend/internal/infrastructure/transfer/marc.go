@@ -0,0 +1,184 @@
+package transfer
+
+import (
+	"library-management/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// marcLeader é o líder dos registros exportados: registro novo (n) de
+// material textual (a) monográfico (m), em UTF-8 (a), sem a pontuação ISBD
+// (c). O tamanho do registro e o endereço dos dados são preenchidos pelo
+// formato ISO 2709 e ficam zerados no MARCXML.
+const marcLeader = "00000nam a2200000 c 4500"
+
+// subjectSubdivision separa as subdivisões de um assunto (650 $x, $y, $z, $v)
+const subjectSubdivision = " -- "
+
+// marcRecord é um registro MARC 21 bibliográfico
+type marcRecord struct {
+	Leader string
+	Fields []marcField
+}
+
+// marcField é um campo MARC: de controle (001 a 009), só com o valor, ou de
+// dados, com dois indicadores e subcampos
+type marcField struct {
+	Tag       string
+	Value     string
+	Ind1      byte
+	Ind2      byte
+	Subfields []marcSubfield
+}
+
+// marcSubfield é um subcampo de um campo de dados ($a, $b...)
+type marcSubfield struct {
+	Code  byte
+	Value string
+}
+
+// isControlTag indica se a etiqueta é de um campo de controle (001 a 009)
+func isControlTag(tag string) bool {
+	return strings.HasPrefix(tag, "00")
+}
+
+// subfield retorna o primeiro subcampo com o código, sem a pontuação final
+func (f marcField) subfield(code byte) string {
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			return trimPunctuation(sf.Value)
+		}
+	}
+	return ""
+}
+
+// trimPunctuation remove a pontuação ISBD do fim de um subcampo
+// ("Dom Casmurro /" vira "Dom Casmurro")
+func trimPunctuation(value string) string {
+	return strings.TrimRight(strings.TrimSpace(value), " /:;,.=")
+}
+
+// toRecord converte um registro MARC nos campos de importação de livros:
+// 020 $a (isbn), 100 $a (author), 245 $a e $b (title), 260 ou 264 $b e $c
+// (publisher e year_published) e 650 (subjects). As etiquetas dos demais
+// campos vão para Unmapped.
+func (m *marcRecord) toRecord(row int) *domain.Record {
+	fields := make(map[string]string)
+	var subjects, unmapped []string
+	seen := make(map[string]bool)
+
+	for _, field := range m.Fields {
+		switch field.Tag {
+		case "020":
+			// O ISBN pode vir seguido de qualificadores: "8535902775 (broch.)"
+			if words := strings.Fields(field.subfield('a')); len(words) > 0 && fields["isbn"] == "" {
+				fields["isbn"] = words[0]
+			}
+		case "100":
+			fields["author"] = field.subfield('a')
+		case "245":
+			title := field.subfield('a')
+			if subtitle := field.subfield('b'); subtitle != "" {
+				title += ": " + subtitle
+			}
+			fields["title"] = title
+		case "260", "264":
+			if fields["publisher"] == "" {
+				fields["publisher"] = field.subfield('b')
+			}
+			if fields["year_published"] == "" {
+				fields["year_published"] = marcYear(field.subfield('c'))
+			}
+		case "650":
+			var parts []string
+			for _, sf := range field.Subfields {
+				if strings.IndexByte("axyzv", sf.Code) >= 0 {
+					if value := trimPunctuation(sf.Value); value != "" {
+						parts = append(parts, value)
+					}
+				}
+			}
+			if len(parts) > 0 {
+				subjects = append(subjects, strings.Join(parts, subjectSubdivision))
+			}
+		default:
+			if !seen[field.Tag] {
+				seen[field.Tag] = true
+				unmapped = append(unmapped, field.Tag)
+			}
+		}
+	}
+	if len(subjects) > 0 {
+		fields["subjects"] = strings.Join(subjects, domain.ValueSeparator+" ")
+	}
+
+	return &domain.Record{Row: row, Fields: fields, Unmapped: unmapped}
+}
+
+// marcYear extrai o ano de publicação de 260/264 $c ("c1899.", "[1899?]")
+func marcYear(value string) string {
+	digits := 0
+	for i, r := range value {
+		if r >= '0' && r <= '9' {
+			digits++
+			if digits == 4 {
+				return value[i-3 : i+1]
+			}
+		} else {
+			digits = 0
+		}
+	}
+	return ""
+}
+
+// bookToMARC converte os valores exportados de um livro em um registro MARC
+func bookToMARC(values map[string]interface{}) *marcRecord {
+	text := func(column string) string {
+		return formatValue(values[column])
+	}
+	year := ""
+	if n, ok := values["year_published"].(int); ok && n > 0 {
+		year = strconv.Itoa(n)
+	}
+
+	record := &marcRecord{Leader: marcLeader}
+	add := func(tag string, ind1, ind2 byte, subfields ...marcSubfield) {
+		var filled []marcSubfield
+		for _, sf := range subfields {
+			if sf.Value != "" {
+				filled = append(filled, sf)
+			}
+		}
+		if len(filled) > 0 {
+			record.Fields = append(record.Fields, marcField{Tag: tag, Ind1: ind1, Ind2: ind2, Subfields: filled})
+		}
+	}
+
+	if id := text("id"); id != "" {
+		record.Fields = append(record.Fields, marcField{Tag: "001", Value: id})
+	}
+	if updatedAt, ok := values["updated_at"].(time.Time); ok {
+		record.Fields = append(record.Fields, marcField{Tag: "005", Value: updatedAt.UTC().Format("20060102150405.0")})
+	}
+	add("020", ' ', ' ', marcSubfield{'a', text("isbn")})
+	add("100", '1', ' ', marcSubfield{'a', text("author")})
+	titleInd1 := byte('0')
+	if text("author") != "" {
+		titleInd1 = '1'
+	}
+	add("245", titleInd1, '0', marcSubfield{'a', text("title")})
+	add("264", ' ', '1', marcSubfield{'b', text("publisher")}, marcSubfield{'c', year})
+	if subjects, ok := values["subjects"].([]string); ok {
+		for _, subject := range subjects {
+			parts := strings.Split(subject, subjectSubdivision)
+			subfields := []marcSubfield{{'a', parts[0]}}
+			for _, part := range parts[1:] {
+				subfields = append(subfields, marcSubfield{'x', part})
+			}
+			// Segundo indicador 4: o vocabulário do assunto não é informado
+			add("650", ' ', '4', subfields...)
+		}
+	}
+	return record
+}
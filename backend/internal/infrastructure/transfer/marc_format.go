@@ -0,0 +1,236 @@
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"library-management/internal/domain"
+	"strings"
+)
+
+// Delimitadores do ISO 2709
+const (
+	subfieldDelimiter = 0x1F
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+)
+
+// leaderLength é o tamanho do líder de um registro MARC
+const leaderLength = 24
+
+// MARCFormat implementa domain.RecordFormat para registros MARC 21
+// bibliográficos no formato de troca ISO 2709 (arquivos .mrc). Só se aplica
+// a livros; o mapeamento de colunas é ignorado, pois os campos MARC têm
+// correspondência fixa (ver marcRecord.toRecord). O texto é lido como UTF-8:
+// registros em MARC-8 só são lidos corretamente se contiverem apenas ASCII.
+type MARCFormat struct{}
+
+// ContentType é o tipo MIME do MARC
+func (MARCFormat) ContentType() string {
+	return "application/marc"
+}
+
+// Extension é a extensão dos arquivos MARC
+func (MARCFormat) Extension() string {
+	return "mrc"
+}
+
+// NewReader cria o leitor dos registros
+func (MARCFormat) NewReader(r io.Reader, mapping map[string]string) (domain.RecordReader, error) {
+	return &marcReader{reader: bufio.NewReader(r)}, nil
+}
+
+// marcReader lê os registros ISO 2709, um por vez
+type marcReader struct {
+	reader *bufio.Reader
+	row    int
+}
+
+// Read retorna o próximo registro, ignorando quebras de linha entre registros
+func (r *marcReader) Read() (*domain.Record, error) {
+	for {
+		b, err := r.reader.ReadByte()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, invalidFile(r.row+1, err)
+		}
+		if b != '\n' && b != '\r' {
+			r.reader.UnreadByte()
+			break
+		}
+	}
+
+	r.row++
+	// Os cinco primeiros dígitos do líder são o tamanho do registro
+	data := make([]byte, 5)
+	if _, err := io.ReadFull(r.reader, data); err != nil {
+		return nil, invalidFile(r.row, err)
+	}
+	length, ok := parseDigits(data)
+	if !ok || length <= leaderLength {
+		return nil, invalidFile(r.row, fmt.Errorf("tamanho de registro inválido: %q", data))
+	}
+	data = append(data, make([]byte, length-5)...)
+	if _, err := io.ReadFull(r.reader, data[5:]); err != nil {
+		return nil, invalidFile(r.row, err)
+	}
+
+	record, err := decodeISO2709(data)
+	if err != nil {
+		return nil, invalidFile(r.row, err)
+	}
+	return record.toRecord(r.row), nil
+}
+
+// decodeISO2709 decodifica um registro ISO 2709 completo: o líder, o
+// diretório (etiqueta, tamanho e posição de cada campo) e os campos
+func decodeISO2709(data []byte) (*marcRecord, error) {
+	if data[len(data)-1] != recordTerminator {
+		return nil, errors.New("registro sem o terminador")
+	}
+	base, ok := parseDigits(data[12:17])
+	if !ok || base <= leaderLength || base > len(data) || data[base-1] != fieldTerminator {
+		return nil, fmt.Errorf("endereço dos dados inválido: %q", data[12:17])
+	}
+	directory := data[leaderLength : base-1]
+	if len(directory)%12 != 0 {
+		return nil, errors.New("diretório com tamanho inválido")
+	}
+
+	record := &marcRecord{Leader: string(data[:leaderLength])}
+	for entry := directory; len(entry) > 0; entry = entry[12:] {
+		tag := string(entry[:3])
+		length, ok1 := parseDigits(entry[3:7])
+		start, ok2 := parseDigits(entry[7:12])
+		end := base + start + length
+		if !ok1 || !ok2 || length <= 0 || base+start > end || end > len(data)-1 {
+			return nil, fmt.Errorf("campo %s fora do registro", tag)
+		}
+		value := bytes.TrimSuffix(data[base+start:end], []byte{fieldTerminator})
+
+		field := marcField{Tag: tag}
+		if isControlTag(tag) {
+			field.Value = marcText(value)
+		} else {
+			if len(value) < 2 {
+				return nil, fmt.Errorf("campo %s sem indicadores", tag)
+			}
+			field.Ind1, field.Ind2 = value[0], value[1]
+			// O que vem antes do primeiro delimitador não pertence a subcampo
+			for _, part := range bytes.Split(value[2:], []byte{subfieldDelimiter})[1:] {
+				if len(part) > 0 {
+					field.Subfields = append(field.Subfields, marcSubfield{Code: part[0], Value: marcText(part[1:])})
+				}
+			}
+		}
+		record.Fields = append(record.Fields, field)
+	}
+	return record, nil
+}
+
+// parseDigits lê um número do líder ou do diretório, que só pode ter dígitos
+// ASCII; um sinal, que strconv.Atoi aceitaria, torna o número inválido
+func parseDigits(b []byte) (int, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	n := 0
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, true
+}
+
+// marcText converte o conteúdo de um campo em texto, substituindo bytes que
+// não são UTF-8 válido
+func marcText(value []byte) string {
+	return strings.ToValidUTF8(string(value), "\uFFFD")
+}
+
+// encodeISO2709 codifica um registro no formato ISO 2709, preenchendo no
+// líder o tamanho do registro e o endereço dos dados
+func encodeISO2709(record *marcRecord) ([]byte, error) {
+	var directory, fields bytes.Buffer
+	for _, field := range record.Fields {
+		start := fields.Len()
+		if isControlTag(field.Tag) {
+			fields.WriteString(field.Value)
+		} else {
+			fields.WriteByte(field.Ind1)
+			fields.WriteByte(field.Ind2)
+			for _, sf := range field.Subfields {
+				fields.WriteByte(subfieldDelimiter)
+				fields.WriteByte(sf.Code)
+				fields.WriteString(sf.Value)
+			}
+		}
+		fields.WriteByte(fieldTerminator)
+
+		length := fields.Len() - start
+		if length > 9999 || start > 99999 {
+			return nil, fmt.Errorf("campo %s grande demais para o ISO 2709", field.Tag)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", field.Tag, length, start)
+	}
+	directory.WriteByte(fieldTerminator)
+
+	base := leaderLength + directory.Len()
+	length := base + fields.Len() + 1
+	if length > 99999 {
+		return nil, errors.New("registro grande demais para o ISO 2709")
+	}
+
+	leader := []byte(record.Leader)
+	copy(leader[0:5], fmt.Sprintf("%05d", length))
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+
+	data := make([]byte, 0, length)
+	data = append(data, leader...)
+	data = append(data, directory.Bytes()...)
+	data = append(data, fields.Bytes()...)
+	return append(data, recordTerminator), nil
+}
+
+// NewWriter cria o escritor de registros ISO 2709; as colunas são as da
+// exportação de livros
+func (MARCFormat) NewWriter(w io.Writer, columns []string) domain.RecordWriter {
+	return &marcWriter{out: w}
+}
+
+// marcWriter grava os livros como registros ISO 2709, um após o outro
+type marcWriter struct {
+	out   io.Writer
+	count int
+}
+
+// Write grava um livro
+func (w *marcWriter) Write(values map[string]interface{}) error {
+	data, err := encodeISO2709(bookToMARC(values))
+	if err != nil {
+		return err
+	}
+	if _, err := w.out.Write(data); err != nil {
+		return err
+	}
+
+	w.count++
+	if f, ok := w.out.(flusher); ok && w.count%flushEvery == 0 {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close envia o restante
+func (w *marcWriter) Close() error {
+	if f, ok := w.out.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package transfer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"library-management/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// domCasmurroMRC é um registro ISO 2709 com ISBN qualificado, pontuação ISBD,
+// subtítulo, assunto com subdivisão e um campo sem correspondência (500)
+const domCasmurroMRC = "00272nam a2200109 c 4500" +
+	"001000600000020002400006100002300030245002900053260002100082500002300103650003600126\x1e" +
+	"rec-1\x1e" +
+	"  \x1fa8535902775 (broch.)\x1e" +
+	"1 \x1faAssis, Machado de,\x1e" +
+	"10\x1faDom Casmurro /\x1fbromance.\x1e" +
+	"  \x1fbGarnier,\x1fcc1899.\x1e" +
+	"  \x1faPrimeira edição.\x1e" +
+	" 4\x1faLiteratura brasileira\x1fxRomance.\x1e" +
+	"\x1d"
+
+// domCasmurroRecord é o registro esperado na leitura de domCasmurroMRC
+var domCasmurroRecord = &domain.Record{
+	Row: 1,
+	Fields: map[string]string{
+		"isbn":           "8535902775",
+		"author":         "Assis, Machado de",
+		"title":          "Dom Casmurro: romance",
+		"publisher":      "Garnier",
+		"year_published": "1899",
+		"subjects":       "Literatura brasileira -- Romance",
+	},
+	Unmapped: []string{"001", "500"},
+}
+
+// readAll lê todos os registros do arquivo no formato
+func readAll(format domain.RecordFormat, input string) ([]*domain.Record, error) {
+	reader, err := format.NewReader(strings.NewReader(input), nil)
+	if err != nil {
+		return nil, err
+	}
+	var records []*domain.Record
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
+
+func TestMARCRead(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []*domain.Record
+		err   error
+	}{
+		{"registro completo", domCasmurroMRC, []*domain.Record{domCasmurroRecord}, nil},
+		{"quebras de linha entre registros", domCasmurroMRC + "\r\n" + domCasmurroMRC + "\n",
+			[]*domain.Record{domCasmurroRecord, {Row: 2, Fields: domCasmurroRecord.Fields, Unmapped: domCasmurroRecord.Unmapped}}, nil},
+		{"arquivo vazio", "", nil, nil},
+		{"tamanho inválido", "abcde" + domCasmurroMRC[5:], nil, domain.ErrInvalidFile},
+		{"registro truncado", domCasmurroMRC[:100], nil, domain.ErrInvalidFile},
+		{"sem o terminador", domCasmurroMRC[:len(domCasmurroMRC)-1] + "x", nil, domain.ErrInvalidFile},
+		{"endereço dos dados inválido", domCasmurroMRC[:12] + "00099" + domCasmurroMRC[17:], nil, domain.ErrInvalidFile},
+		{"campo fora do registro", strings.Replace(domCasmurroMRC, "650003600126", "650009900126", 1), nil, domain.ErrInvalidFile},
+		{"sinal no tamanho do registro", "+0272" + domCasmurroMRC[5:], nil, domain.ErrInvalidFile},
+		{"sinal no endereço dos dados", domCasmurroMRC[:12] + "+0109" + domCasmurroMRC[17:], nil, domain.ErrInvalidFile},
+		{"posição negativa no diretório", strings.Replace(domCasmurroMRC, "650003600126", "6500036-9999", 1), nil, domain.ErrInvalidFile},
+		{"tamanho negativo no diretório", strings.Replace(domCasmurroMRC, "650003600126", "650-99900126", 1), nil, domain.ErrInvalidFile},
+		{"tamanho zero no diretório", strings.Replace(domCasmurroMRC, "650003600126", "650000000126", 1), nil, domain.ErrInvalidFile},
+		{"espaço no diretório", strings.Replace(domCasmurroMRC, "650003600126", "650 03600126", 1), nil, domain.ErrInvalidFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readAll(MARCFormat{}, tt.input)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("erro = %v, esperado %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("registros = %+v, esperado %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMARCYear(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"1899", "1899"},
+		{"c1899.", "1899"},
+		{"[1899?]", "1899"},
+		{"Rio de Janeiro, 1899-1900", "1899"},
+		{"189-", ""},
+		{"s.d.", ""},
+		{"١٨٩٩", ""},
+		{"c١٨٩٩1900", "1900"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := marcYear(tt.value); got != tt.want {
+			t.Errorf("marcYear(%q) = %q, esperado %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMARCXMLRead(t *testing.T) {
+	const record = `<record>
+	<leader>00000nam a2200000 c 4500</leader>
+	<controlfield tag="001">rec-1</controlfield>
+	<datafield tag="020" ind1=" " ind2=" "><subfield code="a">8535902775 (broch.)</subfield></datafield>
+	<datafield tag="100" ind1="1" ind2=" "><subfield code="a">Assis, Machado de,</subfield></datafield>
+	<datafield tag="245" ind1="1" ind2="0">
+		<subfield code="a">Dom Casmurro /</subfield>
+		<subfield code="b">romance.</subfield>
+	</datafield>
+	<datafield tag="264" ind1=" " ind2="1"><subfield code="b">Garnier,</subfield><subfield code="c">[1899?]</subfield></datafield>
+	<datafield tag="500" ind1=" " ind2=" "><subfield code="a">Primeira edição.</subfield></datafield>
+	<datafield tag="650" ind1=" " ind2="4">
+		<subfield code="a">Literatura brasileira</subfield>
+		<subfield code="x">Romance.</subfield>
+	</datafield>
+</record>`
+	prefixed := strings.NewReplacer("<record", "<marc:record", "</record", "</marc:record",
+		"<leader", "<marc:leader", "</leader", "</marc:leader",
+		"<controlfield", "<marc:controlfield", "</controlfield", "</marc:controlfield",
+		"<datafield", "<marc:datafield", "</datafield", "</marc:datafield",
+		"<subfield", "<marc:subfield", "</subfield", "</marc:subfield").Replace(record)
+	second := &domain.Record{Row: 2, Fields: domCasmurroRecord.Fields, Unmapped: domCasmurroRecord.Unmapped}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []*domain.Record
+		err   error
+	}{
+		{"registro único", record, []*domain.Record{domCasmurroRecord}, nil},
+		{"coleção", `<?xml version="1.0" encoding="UTF-8"?>
+<collection xmlns="http://www.loc.gov/MARC21/slim">` + record + record + `</collection>`,
+			[]*domain.Record{domCasmurroRecord, second}, nil},
+		{"prefixo do namespace", `<marc:collection xmlns:marc="http://www.loc.gov/MARC21/slim">` + prefixed + `</marc:collection>`,
+			[]*domain.Record{domCasmurroRecord}, nil},
+		{"coleção vazia", `<collection/>`, nil, nil},
+		{"arquivo vazio", "", nil, domain.ErrInvalidFile},
+		{"raiz inválida", `<books><record/></books>`, nil, domain.ErrInvalidFile},
+		{"XML malformado", `<collection><record><leader>`, nil, domain.ErrInvalidFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readAll(MARCXMLFormat{}, tt.input)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("erro = %v, esperado %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("registros = %+v, esperado %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMARCRoundTrip grava um livro em cada formato MARC e confere a leitura
+func TestMARCRoundTrip(t *testing.T) {
+	book := map[string]interface{}{
+		"id":             "3f1c9a52-1d8e-4c39-9a51-7d0e2b6c4a10",
+		"title":          "Memórias Póstumas de Brás Cubas",
+		"author":         "Machado de Assis",
+		"isbn":           "9788535910667",
+		"publisher":      "Companhia das Letras",
+		"year_published": 1881,
+		"subjects":       []string{"Literatura brasileira -- Romance", "Ironia"},
+		"updated_at":     time.Date(2024, 5, 2, 13, 4, 5, 0, time.UTC),
+	}
+	want := &domain.Record{
+		Row: 1,
+		Fields: map[string]string{
+			"isbn":           "9788535910667",
+			"author":         "Machado de Assis",
+			"title":          "Memórias Póstumas de Brás Cubas",
+			"publisher":      "Companhia das Letras",
+			"year_published": "1881",
+			"subjects":       "Literatura brasileira -- Romance; Ironia",
+		},
+		Unmapped: []string{"001", "005"},
+	}
+
+	for _, format := range []domain.RecordFormat{MARCFormat{}, MARCXMLFormat{}} {
+		var out bytes.Buffer
+		writer := format.NewWriter(&out, nil)
+		if err := writer.Write(book); err != nil {
+			t.Fatalf("%s: erro ao gravar %v", format.Extension(), err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("%s: erro ao fechar %v", format.Extension(), err)
+		}
+
+		got, err := readAll(format, out.String())
+		if err != nil {
+			t.Fatalf("%s: erro ao ler %v", format.Extension(), err)
+		}
+		if len(got) != 1 || !reflect.DeepEqual(got[0], want) {
+			t.Errorf("%s: registros = %+v, esperado %+v", format.Extension(), got, want)
+		}
+	}
+}
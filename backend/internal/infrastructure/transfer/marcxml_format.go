@@ -0,0 +1,206 @@
+package transfer
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"library-management/internal/domain"
+	"strings"
+)
+
+// marcXMLNamespace é o namespace do MARCXML
+const marcXMLNamespace = "http://www.loc.gov/MARC21/slim"
+
+// MARCXMLFormat implementa domain.RecordFormat para registros MARC 21
+// bibliográficos em MARCXML: uma coleção (<collection>) de registros ou um
+// único <record>. Como o MARCFormat, só se aplica a livros e ignora o
+// mapeamento de colunas.
+type MARCXMLFormat struct{}
+
+// ContentType é o tipo MIME do MARCXML
+func (MARCXMLFormat) ContentType() string {
+	return "application/marcxml+xml"
+}
+
+// Extension é a extensão dos arquivos MARCXML
+func (MARCXMLFormat) Extension() string {
+	return "xml"
+}
+
+// xmlRecord é um <record> do MARCXML. Os elementos são reconhecidos pelo
+// nome local, com ou sem o prefixo do namespace (marc:record).
+type xmlRecord struct {
+	Leader        string `xml:"leader"`
+	ControlFields []struct {
+		Tag   string `xml:"tag,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"controlfield"`
+	DataFields []struct {
+		Tag       string `xml:"tag,attr"`
+		Ind1      string `xml:"ind1,attr"`
+		Ind2      string `xml:"ind2,attr"`
+		Subfields []struct {
+			Code  string `xml:"code,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"subfield"`
+	} `xml:"datafield"`
+}
+
+// NewReader confere o elemento raiz e cria o leitor dos registros
+func (MARCXMLFormat) NewReader(r io.Reader, mapping map[string]string) (domain.RecordReader, error) {
+	reader := &marcXMLReader{decoder: xml.NewDecoder(r)}
+	root, err := reader.nextStart()
+	if err == io.EOF {
+		return nil, invalidFile(0, errors.New("arquivo vazio"))
+	}
+	if err != nil {
+		return nil, invalidFile(0, err)
+	}
+
+	switch root.Name.Local {
+	case "collection":
+	case "record":
+		reader.pending = root
+	default:
+		return nil, invalidFile(0, errors.New("o elemento raiz deve ser collection ou record"))
+	}
+	return reader, nil
+}
+
+// marcXMLReader lê os <record> do MARCXML, um por vez
+type marcXMLReader struct {
+	decoder *xml.Decoder
+	// pending é o <record> raiz de um arquivo com um único registro
+	pending *xml.StartElement
+	row     int
+}
+
+// nextStart avança até o início do próximo elemento
+func (r *marcXMLReader) nextStart() (*xml.StartElement, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return &start, nil
+		}
+	}
+}
+
+// Read retorna o próximo registro
+func (r *marcXMLReader) Read() (*domain.Record, error) {
+	start := r.pending
+	r.pending = nil
+	for start == nil || start.Name.Local != "record" {
+		var err error
+		start, err = r.nextStart()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, invalidFile(r.row+1, err)
+		}
+	}
+
+	r.row++
+	var element xmlRecord
+	if err := r.decoder.DecodeElement(&element, start); err != nil {
+		return nil, invalidFile(r.row, err)
+	}
+
+	record := &marcRecord{Leader: element.Leader}
+	for _, cf := range element.ControlFields {
+		record.Fields = append(record.Fields, marcField{Tag: cf.Tag, Value: cf.Value})
+	}
+	for _, df := range element.DataFields {
+		field := marcField{Tag: df.Tag, Ind1: indicator(df.Ind1), Ind2: indicator(df.Ind2)}
+		for _, sf := range df.Subfields {
+			if sf.Code != "" {
+				field.Subfields = append(field.Subfields, marcSubfield{Code: sf.Code[0], Value: sf.Value})
+			}
+		}
+		record.Fields = append(record.Fields, field)
+	}
+	return record.toRecord(r.row), nil
+}
+
+// indicator converte o atributo de um indicador; vazio equivale a branco
+func indicator(value string) byte {
+	if value == "" {
+		return ' '
+	}
+	return value[0]
+}
+
+// NewWriter cria o escritor de uma coleção MARCXML; as colunas são as da
+// exportação de livros
+func (MARCXMLFormat) NewWriter(w io.Writer, columns []string) domain.RecordWriter {
+	return &marcXMLWriter{out: w}
+}
+
+// marcXMLWriter grava os livros como uma coleção MARCXML
+type marcXMLWriter struct {
+	out   io.Writer
+	count int
+}
+
+// Write grava um livro, precedido do início da coleção se for o primeiro
+func (w *marcXMLWriter) Write(values map[string]interface{}) error {
+	var b strings.Builder
+	if w.count == 0 {
+		b.WriteString(w.header())
+	}
+
+	record := bookToMARC(values)
+	b.WriteString("  <record>\n    <leader>" + xmlText(record.Leader) + "</leader>\n")
+	for _, field := range record.Fields {
+		if isControlTag(field.Tag) {
+			b.WriteString(`    <controlfield tag="` + field.Tag + `">` + xmlText(field.Value) + "</controlfield>\n")
+			continue
+		}
+		b.WriteString(`    <datafield tag="` + field.Tag + `" ind1="` + string(field.Ind1) + `" ind2="` + string(field.Ind2) + "\">\n")
+		for _, sf := range field.Subfields {
+			b.WriteString(`      <subfield code="` + string(sf.Code) + `">` + xmlText(sf.Value) + "</subfield>\n")
+		}
+		b.WriteString("    </datafield>\n")
+	}
+	b.WriteString("  </record>\n")
+
+	if _, err := io.WriteString(w.out, b.String()); err != nil {
+		return err
+	}
+
+	w.count++
+	if f, ok := w.out.(flusher); ok && w.count%flushEvery == 0 {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close fecha a coleção e envia o restante
+func (w *marcXMLWriter) Close() error {
+	end := "</collection>\n"
+	if w.count == 0 {
+		end = w.header() + end
+	}
+	if _, err := io.WriteString(w.out, end); err != nil {
+		return err
+	}
+	if f, ok := w.out.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// header é o início do documento, até a abertura da coleção
+func (w *marcXMLWriter) header() string {
+	return xml.Header + `<collection xmlns="` + marcXMLNamespace + "\">\n"
+}
+
+// xmlText escapa um texto para o conteúdo de um elemento
+func xmlText(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"library-management/internal/domain"
+	"library-management/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditHandler gerencia as requisições HTTP do log de auditoria
+type AuditHandler struct {
+	auditService *usecases.AuditService
+}
+
+// NewAuditHandler cria uma nova instância do AuditHandler
+func NewAuditHandler(auditService *usecases.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// GetAuditLog retorna uma página do log de auditoria, dos registros mais
+// recentes aos mais antigos, filtrada por entidade (entity e id), autor
+// (actor) e intervalo (from e to)
+func (h *AuditHandler) GetAuditLog(c *fiber.Ctx) error {
+	params := &queryParser{c: c}
+	query := domain.AuditQuery{
+		Entity:   params.String("entity"),
+		EntityID: params.String("id"),
+		ActorID:  params.String("actor"),
+		From:     params.Date("from"),
+		To:       params.Date("to"),
+		Page:     params.Page(),
+	}
+	if params.err != nil {
+		return params.err
+	}
+
+	entries, total, err := h.auditService.ListEntries(query)
+	if err != nil {
+		return err
+	}
+
+	setPageHeaders(c, query.Page, total)
+	return c.JSON(entries)
+}
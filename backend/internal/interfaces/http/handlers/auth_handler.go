@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"library-management/internal/interfaces/http/middleware"
+	"library-management/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuthHandler gerencia as requisições HTTP de autenticação
+type AuthHandler struct {
+	authService *usecases.AuthService
+	userService *usecases.UserService
+}
+
+// NewAuthHandler cria uma nova instância do AuthHandler
+func NewAuthHandler(authService *usecases.AuthService, userService *usecases.UserService) *AuthHandler {
+	return &AuthHandler{authService: authService, userService: userService}
+}
+
+// LoginRequest representa a estrutura da requisição de login
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RefreshRequest representa a estrutura da requisição de renovação da sessão
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ChangePasswordRequest representa a estrutura da requisição de troca da própria senha
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// SetPasswordRequest representa a estrutura da requisição para definir a senha de um usuário
+type SetPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+// Login autentica o usuário com email e senha
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var req LoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	tokens, err := h.authService.Login(req.Email, req.Password)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(tokens)
+}
+
+// Refresh troca o refresh token por um novo par de tokens
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req RefreshRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	tokens, err := h.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(tokens)
+}
+
+// Logout encerra a sessão do usuário autenticado
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	principal := middleware.Principal(c)
+	if err := h.authService.Logout(principal.SessionID.String()); err != nil {
+		return err
+	}
+
+	return c.Status(204).Send(nil)
+}
+
+// Me retorna o usuário autenticado
+func (h *AuthHandler) Me(c *fiber.Ctx) error {
+	principal := middleware.Principal(c)
+	user, err := h.userService.GetUserByID(principal.UserID.String())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(user)
+}
+
+// ChangePassword troca a senha do usuário autenticado
+func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
+	var req ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	err := h.authService.ChangePassword(c.UserContext(), middleware.Principal(c), req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(204).Send(nil)
+}
+
+// SetPassword define a senha de um usuário
+func (h *AuthHandler) SetPassword(c *fiber.Ctx) error {
+	var req SetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	if err := h.authService.SetPassword(c.UserContext(), c.Params("id"), req.Password); err != nil {
+		return err
+	}
+
+	return c.Status(204).Send(nil)
+}
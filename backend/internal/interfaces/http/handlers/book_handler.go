@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"library-management/internal/domain"
 	"library-management/internal/usecases"
 
 	"github.com/gofiber/fiber/v2"
@@ -18,59 +19,132 @@ func NewBookHandler(bookService *usecases.BookService) *BookHandler {
 
 // CreateBookRequest representa a estrutura da requisição para criar um livro
 type CreateBookRequest struct {
-	Title         string `json:"title"`
-	Author        string `json:"author"`
-	YearPublished int    `json:"year_published"`
-	ISBN          string `json:"isbn"`
+	Title         string   `json:"title"`
+	Author        string   `json:"author"`
+	YearPublished int      `json:"year_published"`
+	ISBN          string   `json:"isbn"`
+	Publisher     string   `json:"publisher"`
+	CoverURL      string   `json:"cover_url"`
+	Subjects      []string `json:"subjects"`
+	MaterialType  string   `json:"material_type"`
+	Copies        int      `json:"copies"`
 }
 
-// UpdateBookRequest representa a estrutura da requisição para atualizar um livro
+// UpdateBookRequest representa a estrutura da requisição para atualizar um livro.
+// ISBN ausente mantém o ISBN atual, e ISBN vazio o remove.
 type UpdateBookRequest struct {
-	Title         string `json:"title"`
-	Author        string `json:"author"`
-	YearPublished int    `json:"year_published"`
-	ISBN          string `json:"isbn"`
+	Title         string   `json:"title"`
+	Author        string   `json:"author"`
+	YearPublished int      `json:"year_published"`
+	ISBN          *string  `json:"isbn"`
+	Publisher     string   `json:"publisher"`
+	CoverURL      string   `json:"cover_url"`
+	Subjects      []string `json:"subjects"`
+	MaterialType  string   `json:"material_type"`
+}
+
+// ImportBookRequest representa a estrutura da requisição para cadastrar um livro pelo ISBN
+type ImportBookRequest struct {
+	ISBN         string `json:"isbn"`
+	MaterialType string `json:"material_type"`
+	Copies       int    `json:"copies"`
 }
 
 // CreateBook cria um novo livro
 func (h *BookHandler) CreateBook(c *fiber.Ctx) error {
 	var req CreateBookRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Dados inválidos",
-		})
+		return errInvalidBody
+	}
+
+	book, err := h.bookService.CreateBook(c.UserContext(), req.Title, req.Author, req.YearPublished, req.ISBN,
+		req.Publisher, req.CoverURL, req.Subjects, req.MaterialType, req.Copies)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(201).JSON(book)
+}
+
+// ImportBookByISBN cadastra um livro com os dados obtidos pelo ISBN nas
+// fontes de dados bibliográficos
+func (h *BookHandler) ImportBookByISBN(c *fiber.Ctx) error {
+	var req ImportBookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
 	}
 
-	book, err := h.bookService.CreateBook(req.Title, req.Author, req.YearPublished, req.ISBN)
+	book, err := h.bookService.ImportBookByISBN(c.UserContext(), req.ISBN, req.MaterialType, req.Copies)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(201).JSON(book)
 }
 
-// GetAllBooks retorna todos os livros
+// GetBookMetadata retorna os dados bibliográficos de um ISBN, sem cadastrar o livro
+func (h *BookHandler) GetBookMetadata(c *fiber.Ctx) error {
+	metadata, err := h.bookService.LookupMetadata(c.Params("isbn"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(metadata)
+}
+
+// GetAllBooks retorna uma página de livros, com filtros e ordenação
+// informados na query string
 func (h *BookHandler) GetAllBooks(c *fiber.Ctx) error {
-	books, err := h.bookService.GetAllBooks()
+	return h.listBooks(c, nil)
+}
+
+// SearchBooks busca livros pelo texto do parâmetro q, do mais ao menos relevante
+func (h *BookHandler) SearchBooks(c *fiber.Ctx) error {
+	params := &queryParser{c: c}
+	search := domain.BookSearch{
+		Text: params.String("q"),
+		Page: params.Page(),
+	}
+	if params.err != nil {
+		return params.err
+	}
+
+	results, total, err := h.bookService.SearchBooks(search)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Erro interno do servidor",
-		})
+		return err
 	}
 
-	return c.JSON(books)
+	setPageHeaders(c, search.Page, total)
+	return c.JSON(results)
 }
 
-// GetBookByID retorna um livro pelo ID
+// GetBookByID retorna um livro pelo ID; com include_deleted=true, também
+// os removidos
 func (h *BookHandler) GetBookByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	book, err := h.bookService.GetBookByID(id)
+	params := &queryParser{c: c}
+	includeDeleted := params.IncludeDeleted()
+	if params.err != nil {
+		return params.err
+	}
+
+	getBook := h.bookService.GetBookByID
+	if includeDeleted {
+		getBook = h.bookService.GetBookByIDWithDeleted
+	}
+	book, err := getBook(id)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{
-			"error": "Livro não encontrado",
-		})
+		return err
+	}
+
+	return c.JSON(book)
+}
+
+// GetBookByISBN retorna um livro pelo ISBN (ISBN-10 ou ISBN-13, com ou sem hífens)
+func (h *BookHandler) GetBookByISBN(c *fiber.Ctx) error {
+	book, err := h.bookService.GetBookByISBN(c.Params("isbn"))
+	if err != nil {
+		return err
 	}
 
 	return c.JSON(book)
@@ -81,42 +155,71 @@ func (h *BookHandler) UpdateBook(c *fiber.Ctx) error {
 	id := c.Params("id")
 	var req UpdateBookRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Dados inválidos",
-		})
+		return errInvalidBody
 	}
 
-	book, err := h.bookService.UpdateBook(id, req.Title, req.Author, req.YearPublished, req.ISBN)
+	book, err := h.bookService.UpdateBook(c.UserContext(), id, req.Title, req.Author, req.YearPublished, req.ISBN,
+		req.Publisher, req.CoverURL, req.Subjects, req.MaterialType)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(book)
 }
 
-// DeleteBook remove um livro
+// DeleteBook remove um livro; ele pode ser restaurado até o expurgo
 func (h *BookHandler) DeleteBook(c *fiber.Ctx) error {
 	id := c.Params("id")
-	err := h.bookService.DeleteBook(id)
+	err := h.bookService.DeleteBook(c.UserContext(), id)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(204).Send(nil)
 }
 
-// GetAvailableBooks retorna todos os livros disponíveis
+// RestoreBook desfaz a remoção de um livro
+func (h *BookHandler) RestoreBook(c *fiber.Ctx) error {
+	book, err := h.bookService.RestoreBook(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(book)
+}
+
+// GetAvailableBooks retorna uma página dos livros com exemplar disponível
 func (h *BookHandler) GetAvailableBooks(c *fiber.Ctx) error {
-	books, err := h.bookService.GetAvailableBooks()
+	available := true
+	return h.listBooks(c, &available)
+}
+
+// listBooks lê os filtros da listagem de livros; available, se informado,
+// prevalece sobre o parâmetro de mesmo nome
+func (h *BookHandler) listBooks(c *fiber.Ctx, available *bool) error {
+	params := &queryParser{c: c}
+	query := domain.BookQuery{
+		Author:         params.String("author"),
+		YearFrom:       params.Int("year_from"),
+		YearTo:         params.Int("year_to"),
+		Available:      params.Bool("available"),
+		MaterialType:   params.String("material_type"),
+		IncludeDeleted: params.IncludeDeleted(),
+		Sort:           params.Sort(domain.SortOrder{Field: "title"}, domain.BookSortFields),
+		Page:           params.Page(),
+	}
+	if params.err != nil {
+		return params.err
+	}
+	if available != nil {
+		query.Available = available
+	}
+
+	books, total, err := h.bookService.ListBooks(query)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Erro interno do servidor",
-		})
+		return err
 	}
 
+	setPageHeaders(c, query.Page, total)
 	return c.JSON(books)
 }
@@ -0,0 +1,8 @@
+package handlers
+
+import "github.com/gofiber/fiber/v2"
+
+// errInvalidBody é retornado quando o corpo da requisição não é um JSON válido
+// para o endpoint. Os demais erros dos handlers vêm dos serviços e são
+// convertidos em respostas por middleware.ErrorHandler.
+var errInvalidBody = fiber.NewError(fiber.StatusBadRequest, "Dados inválidos")
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"library-management/internal/domain"
+	"library-management/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FineHandler gerencia as requisições HTTP para multas e extrato dos usuários
+type FineHandler struct {
+	fineService *usecases.FineService
+}
+
+// NewFineHandler cria uma nova instância do FineHandler
+func NewFineHandler(fineService *usecases.FineService) *FineHandler {
+	return &FineHandler{fineService: fineService}
+}
+
+// AccountEntryRequest representa a estrutura da requisição para pagamentos, abonos e ajustes
+type AccountEntryRequest struct {
+	Amount      domain.Money `json:"amount"`
+	LoanID      string       `json:"loan_id"`
+	Description string       `json:"description"`
+}
+
+// GetAccount retorna o saldo devedor e o extrato de um usuário
+func (h *FineHandler) GetAccount(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	account, err := h.fineService.GetAccount(userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(account)
+}
+
+// RecordPayment registra um pagamento do usuário
+func (h *FineHandler) RecordPayment(c *fiber.Ctx) error {
+	var req AccountEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	entry, err := h.fineService.RecordPayment(c.UserContext(), c.Params("id"), req.Amount, req.Description)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(201).JSON(entry)
+}
+
+// WaiveFine registra um abono de multa
+func (h *FineHandler) WaiveFine(c *fiber.Ctx) error {
+	var req AccountEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	entry, err := h.fineService.WaiveFine(c.UserContext(), c.Params("id"), req.LoanID, req.Amount, req.Description)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(201).JSON(entry)
+}
+
+// AdjustBalance registra um ajuste manual no saldo
+func (h *FineHandler) AdjustBalance(c *fiber.Ctx) error {
+	var req AccountEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	entry, err := h.fineService.AdjustBalance(c.UserContext(), c.Params("id"), req.Amount, req.Description)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(201).JSON(entry)
+}
+
+// AccrueOverdueFines lança as multas acumuladas dos empréstimos em atraso
+func (h *FineHandler) AccrueOverdueFines(c *fiber.Ctx) error {
+	count, err := h.fineService.AccrueOverdueFines(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"loans_charged": count})
+}
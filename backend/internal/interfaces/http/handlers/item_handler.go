@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"library-management/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ItemHandler gerencia as requisições HTTP para exemplares
+type ItemHandler struct {
+	itemService *usecases.ItemService
+}
+
+// NewItemHandler cria uma nova instância do ItemHandler
+func NewItemHandler(itemService *usecases.ItemService) *ItemHandler {
+	return &ItemHandler{itemService: itemService}
+}
+
+// CreateItemRequest representa a estrutura da requisição para criar um exemplar
+type CreateItemRequest struct {
+	Barcode       string `json:"barcode"`
+	ShelfLocation string `json:"shelf_location"`
+}
+
+// UpdateItemRequest representa a estrutura da requisição para atualizar um exemplar
+type UpdateItemRequest struct {
+	Barcode       string `json:"barcode"`
+	ShelfLocation string `json:"shelf_location"`
+	Status        string `json:"status"`
+}
+
+// CreateItem cadastra um novo exemplar para o livro
+func (h *ItemHandler) CreateItem(c *fiber.Ctx) error {
+	bookID := c.Params("id")
+	var req CreateItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	item, err := h.itemService.CreateItem(c.UserContext(), bookID, req.Barcode, req.ShelfLocation)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(201).JSON(item)
+}
+
+// GetItemsByBook retorna todos os exemplares de um livro
+func (h *ItemHandler) GetItemsByBook(c *fiber.Ctx) error {
+	bookID := c.Params("id")
+	items, err := h.itemService.GetItemsByBook(bookID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(items)
+}
+
+// GetItemByID retorna um exemplar pelo ID
+func (h *ItemHandler) GetItemByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	item, err := h.itemService.GetItemByID(id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(item)
+}
+
+// GetItemByBarcode retorna um exemplar pelo código de barras
+func (h *ItemHandler) GetItemByBarcode(c *fiber.Ctx) error {
+	barcode := c.Params("barcode")
+	item, err := h.itemService.GetItemByBarcode(barcode)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(item)
+}
+
+// UpdateItem atualiza um exemplar existente
+func (h *ItemHandler) UpdateItem(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req UpdateItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	item, err := h.itemService.UpdateItem(c.UserContext(), id, req.Barcode, req.ShelfLocation, req.Status)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(item)
+}
+
+// DeleteItem remove um exemplar
+func (h *ItemHandler) DeleteItem(c *fiber.Ctx) error {
+	id := c.Params("id")
+	err := h.itemService.DeleteItem(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(204).Send(nil)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"library-management/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JobHandler gerencia as requisições HTTP das tarefas agendadas
+type JobHandler struct {
+	schedulerService *usecases.SchedulerService
+}
+
+// NewJobHandler cria uma nova instância do JobHandler
+func NewJobHandler(schedulerService *usecases.SchedulerService) *JobHandler {
+	return &JobHandler{schedulerService: schedulerService}
+}
+
+// GetAllJobs lista as tarefas com o agendamento, o próximo horário e a última execução
+func (h *JobHandler) GetAllJobs(c *fiber.Ctx) error {
+	jobs, err := h.schedulerService.ListJobs()
+	if err != nil {
+		return err
+	}
+	return c.JSON(jobs)
+}
+
+// GetJobRuns lista o histórico de execuções de uma tarefa (paginado)
+func (h *JobHandler) GetJobRuns(c *fiber.Ctx) error {
+	params := &queryParser{c: c}
+	page := params.Page()
+	if params.err != nil {
+		return params.err
+	}
+
+	runs, total, err := h.schedulerService.ListRuns(c.Params("name"), page)
+	if err != nil {
+		return err
+	}
+
+	setPageHeaders(c, page, total)
+	return c.JSON(runs)
+}
+
+// RunJob dispara uma execução manual da tarefa e responde 202 sem esperar o
+// fim; o resultado fica no histórico
+func (h *JobHandler) RunJob(c *fiber.Ctx) error {
+	run, err := h.schedulerService.Trigger(c.Params("name"))
+	if err != nil {
+		return err
+	}
+	return c.Status(202).JSON(run)
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"fmt"
+	"library-management/internal/domain"
+	"library-management/internal/interfaces/http/middleware"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// queryParser lê os parâmetros de listagem da query string, guardando o
+// primeiro erro para que o handler o verifique uma única vez no final
+type queryParser struct {
+	c   *fiber.Ctx
+	err error
+}
+
+// fail guarda o erro de um parâmetro, se ainda não houver outro
+func (p *queryParser) fail(name, code, message string) {
+	if p.err == nil {
+		p.err = domain.NewFieldError(name, code, message)
+	}
+}
+
+// String retorna o parâmetro sem espaços nas pontas
+func (p *queryParser) String(name string) string {
+	return strings.TrimSpace(p.c.Query(name))
+}
+
+// Int lê um parâmetro inteiro (0 se ausente)
+func (p *queryParser) Int(name string) int {
+	value := p.String(name)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		p.fail(name, "invalid_number", fmt.Sprintf("%s deve ser um número inteiro", name))
+	}
+	return n
+}
+
+// Bool lê um parâmetro booleano (nil se ausente)
+func (p *queryParser) Bool(name string) *bool {
+	value := p.String(name)
+	if value == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		p.fail(name, "invalid_boolean", fmt.Sprintf("%s deve ser true ou false", name))
+		return nil
+	}
+	return &b
+}
+
+// Date lê uma data no formato AAAA-MM-DD ou RFC 3339 (nil se ausente)
+func (p *queryParser) Date(name string) *time.Time {
+	value := p.String(name)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return &t
+		}
+	}
+	p.fail(name, "invalid_date", fmt.Sprintf("%s deve ser uma data no formato AAAA-MM-DD", name))
+	return nil
+}
+
+// Page lê os parâmetros limit e offset
+func (p *queryParser) Page() domain.Page {
+	limit, offset := p.Int("limit"), p.Int("offset")
+	page, err := domain.NewPage(limit, offset)
+	if err != nil && p.err == nil {
+		p.err = err
+	}
+	return page
+}
+
+// Sort lê o parâmetro sort ("campo" ou "-campo"), aceitando apenas os campos de allowed
+func (p *queryParser) Sort(fallback domain.SortOrder, allowed []string) domain.SortOrder {
+	sort, err := domain.ParseSort(p.String("sort"), fallback, allowed...)
+	if err != nil && p.err == nil {
+		p.err = err
+	}
+	return sort
+}
+
+// IncludeDeleted lê o parâmetro include_deleted. Só quem tem
+// domain.PermDeletedManage pode ver os registros removidos; para os demais,
+// pedir a inclusão é um acesso negado.
+func (p *queryParser) IncludeDeleted() bool {
+	include := p.Bool("include_deleted")
+	if include == nil || !*include {
+		return false
+	}
+	if !middleware.Principal(p.c).Can(domain.PermDeletedManage) {
+		if p.err == nil {
+			p.err = domain.ErrAccessDenied
+		}
+		return false
+	}
+	return true
+}
+
+// setPageHeaders informa o total de registros em X-Total-Count e os links
+// para as páginas vizinhas no cabeçalho Link (RFC 8288), mantendo os demais
+// parâmetros da requisição
+func setPageHeaders(c *fiber.Ctx, page domain.Page, total int) {
+	c.Set("X-Total-Count", strconv.Itoa(total))
+
+	link := func(offset int, rel string) string {
+		query := url.Values{}
+		c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+			query.Add(string(key), string(value))
+		})
+		query.Set("limit", strconv.Itoa(page.Limit))
+		query.Set("offset", strconv.Itoa(offset))
+		return fmt.Sprintf(`<%s%s?%s>; rel="%s"`, c.BaseURL(), c.Path(), query.Encode(), rel)
+	}
+
+	links := []string{link(0, "first")}
+	if page.Offset > 0 {
+		prev := page.Offset - page.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, link(prev, "prev"))
+	}
+	if page.Offset+page.Limit < total {
+		links = append(links, link(page.Offset+page.Limit, "next"))
+	}
+	if total > 0 {
+		links = append(links, link((total-1)/page.Limit*page.Limit, "last"))
+	}
+	c.Set(fiber.HeaderLink, strings.Join(links, ", "))
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"library-management/internal/domain"
 	"library-management/internal/usecases"
 
 	"github.com/gofiber/fiber/v2"
@@ -18,25 +19,21 @@ func NewLoanHandler(loanService *usecases.LoanService) *LoanHandler {
 
 // CreateLoanRequest representa a estrutura da requisição para criar um empréstimo
 type CreateLoanRequest struct {
-	BookID       string `json:"book_id"`
-	UserID       string `json:"user_id"`
-	DaysToReturn int    `json:"days_to_return"`
+	BookID string `json:"book_id"`
+	ItemID string `json:"item_id"`
+	UserID string `json:"user_id"`
 }
 
 // CreateLoan cria um novo empréstimo
 func (h *LoanHandler) CreateLoan(c *fiber.Ctx) error {
 	var req CreateLoanRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Dados inválidos",
-		})
+		return errInvalidBody
 	}
 
-	loan, err := h.loanService.CreateLoan(req.BookID, req.UserID, req.DaysToReturn)
+	loan, err := h.loanService.CreateLoan(c.UserContext(), req.BookID, req.ItemID, req.UserID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(201).JSON(loan)
@@ -45,74 +42,84 @@ func (h *LoanHandler) CreateLoan(c *fiber.Ctx) error {
 // ReturnLoan marca um empréstimo como devolvido
 func (h *LoanHandler) ReturnLoan(c *fiber.Ctx) error {
 	id := c.Params("id")
-	loan, err := h.loanService.ReturnLoan(id)
+	loan, err := h.loanService.ReturnLoan(c.UserContext(), id)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(loan)
 }
 
-// GetAllLoans retorna todos os empréstimos
-func (h *LoanHandler) GetAllLoans(c *fiber.Ctx) error {
-	loans, err := h.loanService.GetAllLoans()
+// RenewLoan estende o prazo de devolução de um empréstimo
+func (h *LoanHandler) RenewLoan(c *fiber.Ctx) error {
+	id := c.Params("id")
+	loan, err := h.loanService.RenewLoan(c.UserContext(), id)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Erro interno do servidor",
-		})
+		return err
 	}
 
-	return c.JSON(loans)
+	return c.JSON(loan)
 }
 
-// GetActiveLoans retorna todos os empréstimos ativos
-func (h *LoanHandler) GetActiveLoans(c *fiber.Ctx) error {
-	loans, err := h.loanService.GetActiveLoans()
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Erro interno do servidor",
-		})
-	}
+// GetAllLoans retorna uma página de empréstimos, com filtros e ordenação
+// informados na query string
+func (h *LoanHandler) GetAllLoans(c *fiber.Ctx) error {
+	return h.listLoans(c, domain.LoanQuery{})
+}
 
-	return c.JSON(loans)
+// GetActiveLoans retorna uma página dos empréstimos não devolvidos
+func (h *LoanHandler) GetActiveLoans(c *fiber.Ctx) error {
+	return h.listLoans(c, domain.LoanQuery{Status: domain.LoanStatusActive})
 }
 
-// GetOverdueLoans retorna todos os empréstimos em atraso
+// GetOverdueLoans retorna uma página dos empréstimos em atraso
 func (h *LoanHandler) GetOverdueLoans(c *fiber.Ctx) error {
-	loans, err := h.loanService.GetOverdueLoans()
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Erro interno do servidor",
-		})
-	}
-
-	return c.JSON(loans)
+	return h.listLoans(c, domain.LoanQuery{Status: domain.LoanStatusOverdue})
 }
 
-// GetLoansByUser retorna todos os empréstimos de um usuário
+// GetLoansByUser retorna uma página dos empréstimos de um usuário
 func (h *LoanHandler) GetLoansByUser(c *fiber.Ctx) error {
-	userID := c.Params("userId")
-	loans, err := h.loanService.GetLoansByUser(userID)
-	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": err.Error(),
-		})
-	}
-
-	return c.JSON(loans)
+	return h.listLoans(c, domain.LoanQuery{UserID: c.Params("userId")})
 }
 
-// GetLoansByBook retorna todos os empréstimos de um livro
+// GetLoansByBook retorna uma página dos empréstimos de um livro
 func (h *LoanHandler) GetLoansByBook(c *fiber.Ctx) error {
-	bookID := c.Params("bookId")
-	loans, err := h.loanService.GetLoansByBook(bookID)
+	return h.listLoans(c, domain.LoanQuery{BookID: c.Params("bookId")})
+}
+
+// listLoans lê os filtros da listagem de empréstimos; os campos já
+// preenchidos em preset (vindos da rota) prevalecem sobre a query string
+func (h *LoanHandler) listLoans(c *fiber.Ctx, preset domain.LoanQuery) error {
+	params := &queryParser{c: c}
+	query := domain.LoanQuery{
+		Status:     domain.LoanStatus(params.String("status")),
+		UserID:     params.String("user_id"),
+		BookID:     params.String("book_id"),
+		LoanedFrom: params.Date("loaned_from"),
+		LoanedTo:   params.Date("loaned_to"),
+		DueFrom:    params.Date("due_from"),
+		DueTo:      params.Date("due_to"),
+		Sort:       params.Sort(domain.SortOrder{Field: "loan_date", Desc: true}, domain.LoanSortFields),
+		Page:       params.Page(),
+	}
+	if params.err != nil {
+		return params.err
+	}
+	if preset.Status != "" {
+		query.Status = preset.Status
+	}
+	if preset.UserID != "" {
+		query.UserID = preset.UserID
+	}
+	if preset.BookID != "" {
+		query.BookID = preset.BookID
+	}
+
+	loans, total, err := h.loanService.ListLoans(query)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
+	setPageHeaders(c, query.Page, total)
 	return c.JSON(loans)
 }
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"library-management/internal/domain"
+	"library-management/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// NotificationHandler gerencia as requisições HTTP das preferências de aviso
+type NotificationHandler struct {
+	notificationService *usecases.NotificationService
+}
+
+// NewNotificationHandler cria uma nova instância do NotificationHandler
+func NewNotificationHandler(notificationService *usecases.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// NotificationPreferencesRequest representa a estrutura da requisição para
+// alterar as preferências de aviso. events e channels omitidos usam o padrão
+// (todos os avisos, por email); uma lista vazia de events desliga os avisos.
+type NotificationPreferencesRequest struct {
+	Events     *[]string          `json:"events"`
+	Channels   *[]string          `json:"channels"`
+	WebhookURL string             `json:"webhook_url"`
+	QuietHours *domain.QuietHours `json:"quiet_hours"`
+	Digest     bool               `json:"digest"`
+}
+
+// toPreferences converte a requisição em preferências de aviso
+func (r NotificationPreferencesRequest) toPreferences() *domain.NotificationPreferences {
+	preferences := domain.DefaultNotificationPreferences(uuid.Nil)
+	if r.Events != nil {
+		preferences.Events = make([]domain.NotificationKind, len(*r.Events))
+		for i, event := range *r.Events {
+			preferences.Events[i] = domain.NotificationKind(event)
+		}
+	}
+	if r.Channels != nil {
+		preferences.Channels = make([]domain.NotificationChannel, len(*r.Channels))
+		for i, channel := range *r.Channels {
+			preferences.Channels[i] = domain.NotificationChannel(channel)
+		}
+	}
+	preferences.WebhookURL = r.WebhookURL
+	preferences.QuietHours = r.QuietHours
+	preferences.Digest = r.Digest
+	return preferences
+}
+
+// GetPreferences retorna as preferências de aviso do usuário
+func (h *NotificationHandler) GetPreferences(c *fiber.Ctx) error {
+	preferences, err := h.notificationService.GetPreferences(c.Params("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(preferences)
+}
+
+// UpdatePreferences substitui as preferências de aviso do usuário
+func (h *NotificationHandler) UpdatePreferences(c *fiber.Ctx) error {
+	var req NotificationPreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	preferences, err := h.notificationService.UpdatePreferences(c.UserContext(), c.Params("id"), req.toPreferences())
+	if err != nil {
+		return err
+	}
+	return c.JSON(preferences)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"library-management/internal/domain"
+	"library-management/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PolicyHandler gerencia as requisições HTTP para políticas de circulação
+type PolicyHandler struct {
+	policyService *usecases.PolicyService
+}
+
+// NewPolicyHandler cria uma nova instância do PolicyHandler
+func NewPolicyHandler(policyService *usecases.PolicyService) *PolicyHandler {
+	return &PolicyHandler{policyService: policyService}
+}
+
+// PolicyRequest representa a estrutura da requisição para criar ou atualizar uma política
+type PolicyRequest struct {
+	PatronCategory   string       `json:"patron_category"`
+	MaterialType     string       `json:"material_type"`
+	LoanPeriodDays   int          `json:"loan_period_days"`
+	MaxLoans         int          `json:"max_loans"`
+	MaxRenewals      int          `json:"max_renewals"`
+	RenewalGraceDays int          `json:"renewal_grace_days"`
+	HoldPickupDays   int          `json:"hold_pickup_days"`
+	FineDailyRate    domain.Money `json:"fine_daily_rate"`
+	FineMaxPerItem   domain.Money `json:"fine_max_per_item"`
+	MaxDebt          domain.Money `json:"max_debt"`
+}
+
+// toPolicy converte a requisição em uma política de circulação
+func (r PolicyRequest) toPolicy() *domain.CirculationPolicy {
+	return &domain.CirculationPolicy{
+		PatronCategory:   domain.PatronCategory(r.PatronCategory),
+		MaterialType:     r.MaterialType,
+		LoanPeriodDays:   r.LoanPeriodDays,
+		MaxLoans:         r.MaxLoans,
+		MaxRenewals:      r.MaxRenewals,
+		RenewalGraceDays: r.RenewalGraceDays,
+		HoldPickupDays:   r.HoldPickupDays,
+		FineDailyRate:    r.FineDailyRate,
+		FineMaxPerItem:   r.FineMaxPerItem,
+		MaxDebt:          r.MaxDebt,
+	}
+}
+
+// CreatePolicy cadastra uma nova política
+func (h *PolicyHandler) CreatePolicy(c *fiber.Ctx) error {
+	var req PolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	policy, err := h.policyService.CreatePolicy(c.UserContext(), req.toPolicy())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(201).JSON(policy)
+}
+
+// GetAllPolicies retorna todas as políticas
+func (h *PolicyHandler) GetAllPolicies(c *fiber.Ctx) error {
+	policies, err := h.policyService.GetAllPolicies()
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(policies)
+}
+
+// GetPolicyByID retorna uma política pelo ID
+func (h *PolicyHandler) GetPolicyByID(c *fiber.Ctx) error {
+	policy, err := h.policyService.GetPolicyByID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(policy)
+}
+
+// UpdatePolicy atualiza uma política existente
+func (h *PolicyHandler) UpdatePolicy(c *fiber.Ctx) error {
+	var req PolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	policy, err := h.policyService.UpdatePolicy(c.UserContext(), c.Params("id"), req.toPolicy())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(policy)
+}
+
+// DeletePolicy remove uma política
+func (h *PolicyHandler) DeletePolicy(c *fiber.Ctx) error {
+	if err := h.policyService.DeletePolicy(c.UserContext(), c.Params("id")); err != nil {
+		return err
+	}
+
+	return c.Status(204).Send(nil)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"fmt"
+	"library-management/internal/domain"
+	"library-management/internal/usecases"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReportHandler gerencia as requisições HTTP dos relatórios de circulação
+type ReportHandler struct {
+	reportService *usecases.ReportService
+}
+
+// NewReportHandler cria uma nova instância do ReportHandler
+func NewReportHandler(reportService *usecases.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// LoansPerPeriod retorna o número de empréstimos por período
+func (h *ReportHandler) LoansPerPeriod(c *fiber.Ctx) error {
+	return h.report(c, h.reportService.LoansPerPeriod)
+}
+
+// TopTitles retorna os livros mais emprestados
+func (h *ReportHandler) TopTitles(c *fiber.Ctx) error {
+	return h.report(c, h.reportService.TopTitles)
+}
+
+// TopAuthors retorna os autores mais emprestados
+func (h *ReportHandler) TopAuthors(c *fiber.Ctx) error {
+	return h.report(c, h.reportService.TopAuthors)
+}
+
+// ActivePatrons retorna os leitores com mais empréstimos
+func (h *ReportHandler) ActivePatrons(c *fiber.Ctx) error {
+	return h.report(c, h.reportService.ActivePatrons)
+}
+
+// OverdueRate retorna a taxa de atraso por período
+func (h *ReportHandler) OverdueRate(c *fiber.Ctx) error {
+	return h.report(c, h.reportService.OverdueRate)
+}
+
+// LoanDuration retorna a duração dos empréstimos devolvidos por período
+func (h *ReportHandler) LoanDuration(c *fiber.Ctx) error {
+	return h.report(c, h.reportService.LoanDuration)
+}
+
+// NeverBorrowed retorna os livros sem empréstimos no intervalo
+func (h *ReportHandler) NeverBorrowed(c *fiber.Ctx) error {
+	return h.report(c, h.reportService.NeverBorrowed)
+}
+
+// report lê o filtro da query string (from, to, interval e limit) e envia o
+// relatório em JSON ou, com o parâmetro format, como arquivo (ex.: csv)
+func (h *ReportHandler) report(c *fiber.Ctx, reportFn func(domain.ReportFilter) (*domain.Report, error)) error {
+	params := &queryParser{c: c}
+	filter := domain.ReportFilter{
+		From:     params.Date("from"),
+		To:       params.Date("to"),
+		Interval: domain.ReportInterval(strings.ToLower(params.String("interval"))),
+		Limit:    params.Int("limit"),
+	}
+	format := strings.ToLower(params.String("format"))
+	if params.err != nil {
+		return params.err
+	}
+
+	var recordFormat domain.RecordFormat
+	if format != "" && format != "json" {
+		var err error
+		if recordFormat, err = h.reportService.Format(format); err != nil {
+			return err
+		}
+	}
+
+	report, err := reportFn(filter)
+	if err != nil {
+		return err
+	}
+	if recordFormat == nil {
+		return c.JSON(report)
+	}
+
+	filename := fmt.Sprintf("relatorio-%s-%s.%s", report.Name, time.Now().Format("20060102"), recordFormat.Extension())
+	c.Set(fiber.HeaderContentType, recordFormat.ContentType())
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	return h.reportService.Write(c.Response().BodyWriter(), report, format)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"library-management/internal/domain"
+	"library-management/internal/interfaces/http/middleware"
+	"library-management/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReservationHandler gerencia as requisições HTTP para reservas
+type ReservationHandler struct {
+	reservationService *usecases.ReservationService
+}
+
+// NewReservationHandler cria uma nova instância do ReservationHandler
+func NewReservationHandler(reservationService *usecases.ReservationService) *ReservationHandler {
+	return &ReservationHandler{reservationService: reservationService}
+}
+
+// PlaceHoldRequest representa a estrutura da requisição para reservar um livro
+type PlaceHoldRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// PlaceHold coloca um usuário na fila de reservas do livro
+func (h *ReservationHandler) PlaceHold(c *fiber.Ctx) error {
+	bookID := c.Params("id")
+	var req PlaceHoldRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	// O leitor só pode reservar para si; a equipe pode reservar para qualquer usuário
+	principal := middleware.Principal(c)
+	if !principal.IsSelf(req.UserID) && !principal.Can(domain.PermCirculation) {
+		return domain.ErrAccessDenied
+	}
+
+	reservation, err := h.reservationService.PlaceHold(c.UserContext(), bookID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(201).JSON(reservation)
+}
+
+// GetHoldsByBook retorna a fila de reservas de um livro
+func (h *ReservationHandler) GetHoldsByBook(c *fiber.Ctx) error {
+	bookID := c.Params("id")
+	reservations, err := h.reservationService.GetHoldsByBook(bookID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(reservations)
+}
+
+// GetHoldsByUser retorna as reservas de um usuário
+func (h *ReservationHandler) GetHoldsByUser(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	reservations, err := h.reservationService.GetHoldsByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(reservations)
+}
+
+// CancelHold cancela uma reserva
+func (h *ReservationHandler) CancelHold(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	// O leitor só pode cancelar as próprias reservas. A reserva de outro
+	// usuário responde como inexistente, para não revelar quais ids existem.
+	principal := middleware.Principal(c)
+	if !principal.Can(domain.PermCirculation) {
+		hold, err := h.reservationService.GetHoldByID(id)
+		if err != nil {
+			return err
+		}
+		if !principal.IsSelf(hold.UserID.String()) {
+			return domain.ErrHoldNotFound
+		}
+	}
+
+	reservation, err := h.reservationService.CancelHold(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(reservation)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"library-management/internal/interfaces/http/i18n"
+	"library-management/internal/interfaces/http/middleware"
+	"library-management/internal/usecases"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StandingHandler gerencia as requisições HTTP para a situação e os bloqueios dos usuários
+type StandingHandler struct {
+	standingService *usecases.StandingService
+}
+
+// NewStandingHandler cria uma nova instância do StandingHandler
+func NewStandingHandler(standingService *usecases.StandingService) *StandingHandler {
+	return &StandingHandler{standingService: standingService}
+}
+
+// BlockUserRequest representa a estrutura da requisição para bloquear um usuário
+type BlockUserRequest struct {
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// GetStanding retorna se o usuário pode pegar livros emprestados e os motivos de bloqueio
+func (h *StandingHandler) GetStanding(c *fiber.Ctx) error {
+	standing, err := h.standingService.GetStanding(c.Params("id"))
+	if err != nil {
+		return err
+	}
+	standing.Reasons = i18n.Reasons(middleware.Language(c), standing.Reasons)
+
+	return c.JSON(standing)
+}
+
+// GetBlocks retorna o histórico de bloqueios manuais do usuário
+func (h *StandingHandler) GetBlocks(c *fiber.Ctx) error {
+	blocks, err := h.standingService.GetBlocks(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(blocks)
+}
+
+// BlockUser aplica um bloqueio manual ao usuário
+func (h *StandingHandler) BlockUser(c *fiber.Ctx) error {
+	var req BlockUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	block, err := h.standingService.BlockUser(c.UserContext(), c.Params("id"), req.Reason, req.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(201).JSON(block)
+}
+
+// LiftBlock levanta um bloqueio manual do usuário
+func (h *StandingHandler) LiftBlock(c *fiber.Ctx) error {
+	block, err := h.standingService.LiftBlock(c.UserContext(), c.Params("id"), c.Params("blockId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(block)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"library-management/internal/domain"
+	"library-management/internal/interfaces/http/i18n"
+	"library-management/internal/interfaces/http/middleware"
+	"library-management/internal/usecases"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// defaultTransferFormat é o formato usado quando a requisição não informa nenhum
+const defaultTransferFormat = "csv"
+
+// TransferHandler gerencia as requisições HTTP de importação e exportação em lote
+type TransferHandler struct {
+	transferService *usecases.TransferService
+}
+
+// NewTransferHandler cria uma nova instância do TransferHandler
+func NewTransferHandler(transferService *usecases.TransferService) *TransferHandler {
+	return &TransferHandler{transferService: transferService}
+}
+
+// ImportBooks importa livros do arquivo enviado no corpo da requisição
+func (h *TransferHandler) ImportBooks(c *fiber.Ctx) error {
+	return h.importRecords(c, h.transferService.ImportBooks)
+}
+
+// ImportUsers importa usuários do arquivo enviado no corpo da requisição
+func (h *TransferHandler) ImportUsers(c *fiber.Ctx) error {
+	return h.importRecords(c, h.transferService.ImportUsers)
+}
+
+// ExportBooks envia todos os livros no formato do parâmetro format (csv,
+// json, marc ou marcxml)
+func (h *TransferHandler) ExportBooks(c *fiber.Ctx) error {
+	return h.exportRecords(c, "livros", h.transferService.BookFormat, h.transferService.ExportBooks)
+}
+
+// ExportUsers envia todos os usuários no formato do parâmetro format (csv ou json)
+func (h *TransferHandler) ExportUsers(c *fiber.Ctx) error {
+	return h.exportRecords(c, "usuarios", h.transferService.UserFormat, h.transferService.ExportUsers)
+}
+
+// importRecords lê as opções da query string (format, dry_run e map) e
+// importa o corpo da requisição sem carregá-lo inteiro na memória
+func (h *TransferHandler) importRecords(c *fiber.Ctx, importFn func(context.Context, io.Reader, domain.ImportOptions) (*domain.ImportReport, error)) error {
+	params := &queryParser{c: c}
+	options := domain.ImportOptions{
+		Format:  requestFormat(c),
+		Mapping: columnMapping(c, params),
+	}
+	if dryRun := params.Bool("dry_run"); dryRun != nil {
+		options.DryRun = *dryRun
+	}
+	if params.err != nil {
+		return params.err
+	}
+
+	var body io.Reader = c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+
+	report, err := importFn(c.UserContext(), body, options)
+	if err != nil {
+		return err
+	}
+
+	lang := middleware.Language(c)
+	for i, rowErr := range report.Errors {
+		report.Errors[i].Message = i18n.Translate(lang, rowErr.Code, rowErr.Params, rowErr.Message)
+	}
+	return c.JSON(report)
+}
+
+// exportRecords envia o arquivo aos poucos, à medida que os registros são
+// lidos do banco. Como o status já foi enviado, um erro no meio da
+// exportação só pode ser registrado no log, e o arquivo chega incompleto.
+func (h *TransferHandler) exportRecords(c *fiber.Ctx, name string, findFormat func(string) (domain.RecordFormat, error),
+	exportFn func(io.Writer, string) error) error {
+	format := strings.ToLower(strings.TrimSpace(c.Query("format", defaultTransferFormat)))
+	recordFormat, err := findFormat(format)
+	if err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102"), recordFormat.Extension())
+	c.Set(fiber.HeaderContentType, recordFormat.ContentType())
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := exportFn(w, format); err != nil {
+			log.Printf("Erro na exportação de %s: %v", name, err)
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+// requestFormat retorna o formato do parâmetro format ou, na falta dele, o
+// deduzido do Content-Type da requisição
+func requestFormat(c *fiber.Ctx) string {
+	if format := strings.TrimSpace(c.Query("format")); format != "" {
+		return strings.ToLower(format)
+	}
+	contentType := strings.ToLower(c.Get(fiber.HeaderContentType))
+	switch {
+	case strings.Contains(contentType, "json"):
+		return "json"
+	case strings.Contains(contentType, "xml"):
+		return "marcxml"
+	case strings.Contains(contentType, "marc"):
+		return "marc"
+	}
+	return defaultTransferFormat
+}
+
+// columnMapping lê o mapeamento de colunas do parâmetro map, no formato
+// campo:coluna, separado por vírgulas ou repetido (map=title:Título&map=author:Autor)
+func columnMapping(c *fiber.Ctx, params *queryParser) map[string]string {
+	mapping := make(map[string]string)
+	for _, value := range c.Context().QueryArgs().PeekMulti("map") {
+		for _, pair := range strings.Split(string(value), ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			field, column, ok := strings.Cut(pair, ":")
+			field, column = strings.TrimSpace(field), strings.TrimSpace(column)
+			if !ok || field == "" || column == "" {
+				params.fail("map", "invalid_mapping", "mapeamento de colunas inválido; use campo:coluna")
+				return nil
+			}
+			mapping[field] = column
+		}
+	}
+	return mapping
+}
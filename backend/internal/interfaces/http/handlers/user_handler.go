@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"library-management/internal/domain"
 	"library-management/internal/usecases"
 
 	"github.com/gofiber/fiber/v2"
@@ -18,57 +19,80 @@ func NewUserHandler(userService *usecases.UserService) *UserHandler {
 
 // CreateUserRequest representa a estrutura da requisição para criar um usuário
 type CreateUserRequest struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Phone string `json:"phone"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Category string `json:"category"`
+	Role     string `json:"role"`
 }
 
 // UpdateUserRequest representa a estrutura da requisição para atualizar um usuário
 type UpdateUserRequest struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Phone string `json:"phone"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Category string `json:"category"`
+	Role     string `json:"role"`
 }
 
 // CreateUser cria um novo usuário
 func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
 	var req CreateUserRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Dados inválidos",
-		})
+		return errInvalidBody
 	}
 
-	user, err := h.userService.CreateUser(req.Name, req.Email, req.Phone)
+	user, err := h.userService.CreateUser(c.UserContext(), req.Name, req.Email, req.Phone, req.Category, req.Role)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(201).JSON(user)
 }
 
-// GetAllUsers retorna todos os usuários
+// GetAllUsers retorna uma página de usuários, com filtros e ordenação
+// informados na query string
 func (h *UserHandler) GetAllUsers(c *fiber.Ctx) error {
-	users, err := h.userService.GetAllUsers()
+	params := &queryParser{c: c}
+	query := domain.UserQuery{
+		Name:           params.String("name"),
+		Email:          params.String("email"),
+		Category:       domain.PatronCategory(params.String("category")),
+		Role:           domain.Role(params.String("role")),
+		IncludeDeleted: params.IncludeDeleted(),
+		Sort:           params.Sort(domain.SortOrder{Field: "name"}, domain.UserSortFields),
+		Page:           params.Page(),
+	}
+	if params.err != nil {
+		return params.err
+	}
+
+	users, total, err := h.userService.ListUsers(query)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Erro interno do servidor",
-		})
+		return err
 	}
 
+	setPageHeaders(c, query.Page, total)
 	return c.JSON(users)
 }
 
-// GetUserByID retorna um usuário pelo ID
+// GetUserByID retorna um usuário pelo ID; com include_deleted=true, também
+// os removidos
 func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	user, err := h.userService.GetUserByID(id)
+	params := &queryParser{c: c}
+	includeDeleted := params.IncludeDeleted()
+	if params.err != nil {
+		return params.err
+	}
+
+	getUser := h.userService.GetUserByID
+	if includeDeleted {
+		getUser = h.userService.GetUserByIDWithDeleted
+	}
+	user, err := getUser(id)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{
-			"error": "Usuário não encontrado",
-		})
+		return err
 	}
 
 	return c.JSON(user)
@@ -79,30 +103,34 @@ func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	id := c.Params("id")
 	var req UpdateUserRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Dados inválidos",
-		})
+		return errInvalidBody
 	}
 
-	user, err := h.userService.UpdateUser(id, req.Name, req.Email, req.Phone)
+	user, err := h.userService.UpdateUser(c.UserContext(), id, req.Name, req.Email, req.Phone, req.Category, req.Role)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(user)
 }
 
-// DeleteUser remove um usuário
+// DeleteUser remove um usuário; ele pode ser restaurado até o expurgo
 func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	id := c.Params("id")
-	err := h.userService.DeleteUser(id)
+	err := h.userService.DeleteUser(c.UserContext(), id)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(204).Send(nil)
 }
+
+// RestoreUser desfaz a remoção de um usuário
+func (h *UserHandler) RestoreUser(c *fiber.Ctx) error {
+	user, err := h.userService.RestoreUser(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(user)
+}
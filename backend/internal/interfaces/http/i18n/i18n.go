@@ -0,0 +1,168 @@
+// Package i18n traduz as mensagens de erro da API a partir do código estável
+// de cada erro. Os catálogos ficam em locales/<idioma>.json, com mensagens
+// que podem conter valores variáveis no formato {nome}.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"library-management/internal/domain"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLanguage é o idioma usado quando o cliente não pede um idioma suportado
+const DefaultLanguage = "pt-BR"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogs guarda as mensagens de cada idioma suportado, por código
+var catalogs = loadCatalogs()
+
+// dateLayouts é o formato de data de cada idioma
+var dateLayouts = map[string]string{
+	"pt-BR": "02/01/2006",
+	"en":    "2006-01-02",
+}
+
+func loadCatalogs() map[string]map[string]string {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	catalogs := make(map[string]map[string]string)
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("catálogo %s inválido: %v", entry.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+	return catalogs
+}
+
+// Negotiate escolhe o idioma suportado de maior preferência no cabeçalho
+// Accept-Language (ex.: "en-US,en;q=0.9,pt;q=0.8"). Uma variante regional não
+// suportada usa o idioma base ("en-US" vira "en", "pt" vira "pt-BR").
+func Negotiate(acceptLanguage string) string {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+
+	var preferences []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if quality > 0 {
+			preferences = append(preferences, preference{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+
+	for _, pref := range preferences {
+		if lang := match(pref.tag); lang != "" {
+			return lang
+		}
+	}
+	return DefaultLanguage
+}
+
+// match retorna o idioma suportado correspondente à tag, ou "" se não houver
+func match(tag string) string {
+	base, _, _ := strings.Cut(tag, "-")
+	var sameBase string
+	for lang := range catalogs {
+		if strings.EqualFold(lang, tag) {
+			return lang
+		}
+		langBase, _, _ := strings.Cut(lang, "-")
+		if strings.EqualFold(langBase, base) {
+			sameBase = lang
+		}
+	}
+	return sameBase
+}
+
+// Translate retorna a mensagem de code no idioma lang, substituindo os
+// valores variáveis. Se o idioma não tiver a mensagem, retorna fallback.
+func Translate(lang, code string, params map[string]interface{}, fallback string) string {
+	message, ok := catalogs[lang][code]
+	if !ok {
+		return fallback
+	}
+
+	for name, value := range params {
+		message = strings.ReplaceAll(message, "{"+name+"}", format(lang, value))
+	}
+	return message
+}
+
+// format converte um valor variável em texto no formato do idioma
+func format(lang string, value interface{}) string {
+	if t, ok := value.(time.Time); ok {
+		return t.Format(dateLayouts[lang])
+	}
+	return fmt.Sprint(value)
+}
+
+// Error retorna a mensagem traduzida de um erro do domínio
+func Error(lang string, err *domain.Error) string {
+	return Translate(lang, err.Code, err.Params, err.Message)
+}
+
+// Fields traduz as mensagens dos campos de um erro de validação, usando os
+// valores variáveis do próprio erro
+func Fields(lang string, err *domain.Error) []domain.FieldError {
+	fields := make([]domain.FieldError, len(err.Fields))
+	for i, field := range err.Fields {
+		field.Message = Translate(lang, field.Code, err.Params, field.Message)
+		fields[i] = field
+	}
+	return fields
+}
+
+// Reasons traduz as mensagens dos motivos de bloqueio de empréstimos.
+// Bloqueios manuais com data de término usam a mensagem "manual_block_until".
+func Reasons(lang string, reasons []domain.StandingReason) []domain.StandingReason {
+	translated := make([]domain.StandingReason, len(reasons))
+	for i, reason := range reasons {
+		code := string(reason.Code)
+		if _, ok := reason.Params["until"]; ok {
+			code += "_until"
+		}
+		reason.Message = Translate(lang, code, reason.Params, reason.Message)
+		translated[i] = reason
+	}
+	return translated
+}
+
+// Blocked traduz a mensagem de um BorrowingBlockedError, juntando os motivos já traduzidos
+func Blocked(lang string, reasons []domain.StandingReason) string {
+	messages := make([]string, len(reasons))
+	for i, reason := range reasons {
+		messages[i] = reason.Message
+	}
+	blocked := &domain.BorrowingBlockedError{Reasons: reasons}
+	return Translate(lang, "borrowing_blocked", map[string]interface{}{"reasons": strings.Join(messages, "; ")}, blocked.Error())
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"library-management/internal/domain"
+	"library-management/internal/usecases"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// principalLocal é a chave em c.Locals onde fica o usuário autenticado
+const principalLocal = "principal"
+
+// errAuthRequired é retornado quando a requisição não traz o token de acesso
+var errAuthRequired = domain.NewUnauthenticated("authentication_required", "Autenticação necessária")
+
+// RequireAuth exige um token de acesso válido no cabeçalho
+// "Authorization: Bearer <token>". O usuário autenticado fica disponível
+// para os handlers via Principal(c) e no contexto de c.UserContext().
+func RequireAuth(authService *usecases.AuthService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(fiber.HeaderAuthorization)
+		scheme, token, found := strings.Cut(header, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			return errAuthRequired
+		}
+
+		principal, err := authService.Authenticate(strings.TrimSpace(token))
+		if err != nil {
+			return err
+		}
+
+		SetPrincipal(c, principal)
+		return c.Next()
+	}
+}
+
+// SetPrincipal associa o usuário autenticado à requisição, para Principal(c)
+// e para o contexto de c.UserContext()
+func SetPrincipal(c *fiber.Ctx, principal *domain.Principal) {
+	c.Locals(principalLocal, principal)
+	c.SetUserContext(domain.WithPrincipal(c.UserContext(), principal))
+}
+
+// Principal retorna o usuário autenticado da requisição, ou nil se a rota não exige autenticação
+func Principal(c *fiber.Ctx) *domain.Principal {
+	principal, _ := c.Locals(principalLocal).(*domain.Principal)
+	return principal
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"errors"
+	"library-management/internal/domain"
+	"library-management/internal/interfaces/http/i18n"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// kindStatus associa cada classificação de erro do domínio ao status HTTP
+var kindStatus = map[domain.ErrorKind]int{
+	domain.KindNotFound:        fiber.StatusNotFound,
+	domain.KindConflict:        fiber.StatusConflict,
+	domain.KindValidation:      fiber.StatusUnprocessableEntity,
+	domain.KindForbidden:       fiber.StatusForbidden,
+	domain.KindPolicyViolation: fiber.StatusUnprocessableEntity,
+	domain.KindUnauthenticated: fiber.StatusUnauthorized,
+	domain.KindUnavailable:     fiber.StatusBadGateway,
+}
+
+// ErrorHandler converte os erros retornados pelos handlers em respostas JSON
+// no formato {"error": mensagem, "code": código}, com a mensagem no idioma da
+// requisição (ver Localize). Erros do domínio recebem o status da sua
+// classificação (404, 409, 422, 502, ...) e, nos de validação, a lista "fields";
+// erros do Fiber mantêm o próprio status; os demais são registrados no log e
+// respondidos com 500 sem expor detalhes.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	lang := Language(c)
+	c.Set(fiber.HeaderContentLanguage, lang)
+
+	var blocked *domain.BorrowingBlockedError
+	if errors.As(err, &blocked) {
+		reasons := i18n.Reasons(lang, blocked.Reasons)
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":   i18n.Blocked(lang, reasons),
+			"code":    "borrowing_blocked",
+			"reasons": reasons,
+		})
+	}
+
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		status, ok := kindStatus[domainErr.Kind]
+		if !ok {
+			status = fiber.StatusBadRequest
+		}
+		if domainErr.Kind == domain.KindUnavailable {
+			// A causa da falha do serviço externo fica só no log
+			log.Printf("Serviço externo indisponível em %s %s: %v", c.Method(), c.Path(), domainErr.Err)
+		}
+		body := fiber.Map{
+			"error": i18n.Error(lang, domainErr),
+			"code":  domainErr.Code,
+		}
+		if len(domainErr.Fields) > 0 {
+			body["fields"] = i18n.Fields(lang, domainErr)
+		}
+		return c.Status(status).JSON(body)
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		code := strings.ToLower(strings.ReplaceAll(utils.StatusMessage(fiberErr.Code), " ", "_"))
+		return c.Status(fiberErr.Code).JSON(fiber.Map{
+			"error": i18n.Translate(lang, "http_"+code, nil, fiberErr.Message),
+			"code":  code,
+		})
+	}
+
+	log.Printf("Erro interno em %s %s: %v", c.Method(), c.Path(), err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": i18n.Translate(lang, "internal_error", nil, "Erro interno do servidor"),
+		"code":  "internal_error",
+	})
+}
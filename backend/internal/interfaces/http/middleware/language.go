@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"library-management/internal/interfaces/http/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// languageLocal é a chave em c.Locals onde fica o idioma da requisição
+const languageLocal = "language"
+
+// Localize escolhe o idioma da resposta a partir do cabeçalho Accept-Language
+// e o informa no cabeçalho Content-Language
+func Localize() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		lang := i18n.Negotiate(c.Get(fiber.HeaderAcceptLanguage))
+		c.Locals(languageLocal, lang)
+		c.Set(fiber.HeaderContentLanguage, lang)
+		return c.Next()
+	}
+}
+
+// Language retorna o idioma da requisição escolhido por Localize
+func Language(c *fiber.Ctx) string {
+	if lang, ok := c.Locals(languageLocal).(string); ok {
+		return lang
+	}
+	return i18n.Negotiate(c.Get(fiber.HeaderAcceptLanguage))
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"library-management/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequirePermission exige que o usuário autenticado tenha a permissão.
+// Deve ser usado depois de RequireAuth.
+func RequirePermission(permission domain.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := Principal(c)
+		if principal == nil || !principal.Can(permission) {
+			return domain.ErrAccessDenied
+		}
+		return c.Next()
+	}
+}
+
+// RequirePermissionOrSelf exige a permissão, exceto quando o parâmetro de rota
+// param é o ID do próprio usuário autenticado. Usado nas rotas em que o
+// leitor pode consultar os próprios dados.
+func RequirePermissionOrSelf(permission domain.Permission, param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := Principal(c)
+		if principal == nil || !(principal.IsSelf(c.Params(param)) || principal.Can(permission)) {
+			return domain.ErrAccessDenied
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"library-management/internal/domain"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// permissionApp protege GET /users/:id com handler e autentica como principal
+// (nil deixa a requisição sem usuário)
+func permissionApp(principal *domain.Principal, handler fiber.Handler) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		if principal != nil {
+			SetPrincipal(c, principal)
+		}
+		return c.Next()
+	})
+	app.Get("/users/:id", handler, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	return app
+}
+
+func TestRequirePermission(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		want      int
+	}{
+		{"sem usuário", nil, fiber.StatusForbidden},
+		{"leitor", &domain.Principal{UserID: userID, Role: domain.RolePatron}, fiber.StatusForbidden},
+		{"leitor nos próprios dados", &domain.Principal{UserID: userID, Role: domain.RolePatron}, fiber.StatusForbidden},
+		{"bibliotecário", &domain.Principal{UserID: uuid.New(), Role: domain.RoleLibrarian}, fiber.StatusNoContent},
+		{"administrador", &domain.Principal{UserID: uuid.New(), Role: domain.RoleAdmin}, fiber.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := permissionApp(tt.principal, RequirePermission(domain.PermCirculation))
+			resp, err := app.Test(httptest.NewRequest("GET", "/users/"+userID.String(), nil))
+			if err != nil {
+				t.Fatalf("erro inesperado %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, esperado %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequirePermissionOrSelf(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		id        string
+		want      int
+	}{
+		{"sem usuário", nil, userID.String(), fiber.StatusForbidden},
+		{"leitor nos próprios dados", &domain.Principal{UserID: userID, Role: domain.RolePatron}, userID.String(), fiber.StatusNoContent},
+		{"leitor nos dados de outro", &domain.Principal{UserID: userID, Role: domain.RolePatron}, uuid.NewString(), fiber.StatusForbidden},
+		{"leitor com id inválido", &domain.Principal{UserID: userID, Role: domain.RolePatron}, "me", fiber.StatusForbidden},
+		{"bibliotecário nos dados de outro", &domain.Principal{UserID: uuid.New(), Role: domain.RoleLibrarian}, userID.String(), fiber.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := permissionApp(tt.principal, RequirePermissionOrSelf(domain.PermCirculation, "id"))
+			resp, err := app.Test(httptest.NewRequest("GET", "/users/"+tt.id, nil))
+			if err != nil {
+				t.Fatalf("erro inesperado %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, esperado %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
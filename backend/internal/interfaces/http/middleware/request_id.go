@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"library-management/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// maxRequestIDLength é o maior identificador aceito do cliente
+const maxRequestIDLength = 128
+
+// RequestID identifica a requisição pelo cabeçalho X-Request-ID enviado pelo
+// cliente ou, se ele não enviar, por um identificador novo. O identificador
+// volta no mesmo cabeçalho da resposta e fica no contexto de c.UserContext(),
+// de onde é gravado no log de auditoria.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(fiber.HeaderXRequestID)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewString()
+		}
+		c.Set(fiber.HeaderXRequestID, id)
+		c.SetUserContext(domain.WithRequestID(c.UserContext(), id))
+		return c.Next()
+	}
+}
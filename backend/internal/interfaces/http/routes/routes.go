@@ -1,20 +1,30 @@
 package routes
 
 import (
+	"library-management/internal/domain"
 	"library-management/internal/interfaces/http/handlers"
+	"library-management/internal/interfaces/http/middleware"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
-// SetupRoutes configura todas as rotas da aplicação
-func SetupRoutes(app *fiber.App, bookHandler *handlers.BookHandler, userHandler *handlers.UserHandler, loanHandler *handlers.LoanHandler) {
+// SetupRoutes configura todas as rotas da aplicação. Exceto o health check,
+// o login e a renovação da sessão, todas as rotas exigem autenticação
+// (requireAuth). As rotas que alteram dados exigem a permissão correspondente
+// ao papel do usuário (domain.Permission); o leitor só acessa os próprios
+// empréstimos, reservas e conta. allowedOrigins lista, separadas por vírgula,
+// as origens aceitas pelo CORS.
+func SetupRoutes(app *fiber.App, allowedOrigins string, requireAuth fiber.Handler, authHandler *handlers.AuthHandler, bookHandler *handlers.BookHandler, itemHandler *handlers.ItemHandler, userHandler *handlers.UserHandler, loanHandler *handlers.LoanHandler, reservationHandler *handlers.ReservationHandler, fineHandler *handlers.FineHandler, policyHandler *handlers.PolicyHandler, standingHandler *handlers.StandingHandler, transferHandler *handlers.TransferHandler, reportHandler *handlers.ReportHandler, jobHandler *handlers.JobHandler, notificationHandler *handlers.NotificationHandler, auditHandler *handlers.AuditHandler) {
 	// CORS middleware
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept",
-		AllowMethods: "GET, POST, PUT, DELETE",
+		AllowOrigins:  allowedOrigins,
+		AllowHeaders:  "Origin, Content-Type, Accept, Accept-Language, Authorization, X-Request-ID",
+		AllowMethods:  "GET, POST, PUT, DELETE",
+		ExposeHeaders: "Content-Language, X-Total-Count, Link, X-Request-ID",
 	}))
+	app.Use(middleware.Localize())
+	app.Use(middleware.RequestID())
 
 	// Health check endpoint
 	app.Get("/health", func(c *fiber.Ctx) error {
@@ -24,30 +34,118 @@ func SetupRoutes(app *fiber.App, bookHandler *handlers.BookHandler, userHandler
 	// API prefix
 	api := app.Group("/api")
 
+	catalogWrite := middleware.RequirePermission(domain.PermCatalogWrite)
+	circulation := middleware.RequirePermission(domain.PermCirculation)
+	circulationOrSelf := middleware.RequirePermissionOrSelf(domain.PermCirculation, "id")
+	usersRead := middleware.RequirePermission(domain.PermUsersRead)
+	usersManage := middleware.RequirePermission(domain.PermUsersManage)
+	policiesManage := middleware.RequirePermission(domain.PermPoliciesManage)
+	jobsManage := middleware.RequirePermission(domain.PermJobsManage)
+	auditRead := middleware.RequirePermission(domain.PermAuditRead)
+	deletedManage := middleware.RequirePermission(domain.PermDeletedManage)
+
+	// Auth routes
+	auth := api.Group("/auth")
+	auth.Post("/login", authHandler.Login)
+	auth.Post("/refresh", authHandler.Refresh)
+	auth.Post("/logout", requireAuth, authHandler.Logout)
+	auth.Get("/me", requireAuth, authHandler.Me)
+	auth.Put("/password", requireAuth, authHandler.ChangePassword)
+
 	// Book routes
-	books := api.Group("/books")
-	books.Post("/", bookHandler.CreateBook)
+	books := api.Group("/books", requireAuth)
+	books.Post("/", catalogWrite, bookHandler.CreateBook)
+	books.Post("/import-by-isbn", catalogWrite, bookHandler.ImportBookByISBN)
+	books.Post("/import", catalogWrite, transferHandler.ImportBooks)
+	books.Get("/export", catalogWrite, transferHandler.ExportBooks)
 	books.Get("/", bookHandler.GetAllBooks)
 	books.Get("/available", bookHandler.GetAvailableBooks)
+	books.Get("/search", bookHandler.SearchBooks)
+	books.Get("/isbn/:isbn", bookHandler.GetBookByISBN)
+	books.Get("/metadata/:isbn", catalogWrite, bookHandler.GetBookMetadata)
 	books.Get("/:id", bookHandler.GetBookByID)
-	books.Put("/:id", bookHandler.UpdateBook)
-	books.Delete("/:id", bookHandler.DeleteBook)
+	books.Put("/:id", catalogWrite, bookHandler.UpdateBook)
+	books.Delete("/:id", catalogWrite, bookHandler.DeleteBook)
+	books.Post("/:id/restore", deletedManage, bookHandler.RestoreBook)
+	books.Get("/:id/items", itemHandler.GetItemsByBook)
+	books.Post("/:id/items", catalogWrite, itemHandler.CreateItem)
+	books.Get("/:id/holds", circulation, reservationHandler.GetHoldsByBook)
+	books.Post("/:id/holds", reservationHandler.PlaceHold) // dono conferido no handler
+
+	// Item (copy) routes
+	items := api.Group("/items", requireAuth)
+	items.Get("/barcode/:barcode", itemHandler.GetItemByBarcode)
+	items.Get("/:id", itemHandler.GetItemByID)
+	items.Put("/:id", catalogWrite, itemHandler.UpdateItem)
+	items.Delete("/:id", catalogWrite, itemHandler.DeleteItem)
 
 	// User routes
-	users := api.Group("/users")
-	users.Post("/", userHandler.CreateUser)
-	users.Get("/", userHandler.GetAllUsers)
-	users.Get("/:id", userHandler.GetUserByID)
-	users.Put("/:id", userHandler.UpdateUser)
-	users.Delete("/:id", userHandler.DeleteUser)
+	users := api.Group("/users", requireAuth)
+	users.Post("/", usersManage, userHandler.CreateUser)
+	users.Get("/", usersRead, userHandler.GetAllUsers)
+	users.Post("/import", usersManage, transferHandler.ImportUsers)
+	users.Get("/export", usersRead, transferHandler.ExportUsers)
+	users.Get("/:id", middleware.RequirePermissionOrSelf(domain.PermUsersRead, "id"), userHandler.GetUserByID)
+	users.Put("/:id", usersManage, userHandler.UpdateUser)
+	users.Delete("/:id", usersManage, userHandler.DeleteUser)
+	users.Post("/:id/restore", deletedManage, userHandler.RestoreUser)
+	users.Put("/:id/password", usersManage, authHandler.SetPassword)
+	users.Get("/:id/holds", circulationOrSelf, reservationHandler.GetHoldsByUser)
+	users.Get("/:id/account", circulationOrSelf, fineHandler.GetAccount)
+	users.Post("/:id/account/payments", circulation, fineHandler.RecordPayment)
+	users.Post("/:id/account/waivers", circulation, fineHandler.WaiveFine)
+	users.Post("/:id/account/adjustments", circulation, fineHandler.AdjustBalance)
+	users.Get("/:id/standing", circulationOrSelf, standingHandler.GetStanding)
+	users.Get("/:id/blocks", circulationOrSelf, standingHandler.GetBlocks)
+	users.Post("/:id/blocks", circulation, standingHandler.BlockUser)
+	users.Delete("/:id/blocks/:blockId", circulation, standingHandler.LiftBlock)
+	users.Get("/:id/notification-preferences", middleware.RequirePermissionOrSelf(domain.PermUsersRead, "id"), notificationHandler.GetPreferences)
+	users.Put("/:id/notification-preferences", middleware.RequirePermissionOrSelf(domain.PermUsersManage, "id"), notificationHandler.UpdatePreferences)
 
 	// Loan routes
-	loans := api.Group("/loans")
-	loans.Post("/", loanHandler.CreateLoan)
-	loans.Get("/", loanHandler.GetAllLoans)
-	loans.Get("/active", loanHandler.GetActiveLoans)
-	loans.Get("/overdue", loanHandler.GetOverdueLoans)
-	loans.Get("/user/:userId", loanHandler.GetLoansByUser)
-	loans.Get("/book/:bookId", loanHandler.GetLoansByBook)
-	loans.Put("/:id/return", loanHandler.ReturnLoan)
+	loans := api.Group("/loans", requireAuth)
+	loans.Post("/", circulation, loanHandler.CreateLoan)
+	loans.Get("/", circulation, loanHandler.GetAllLoans)
+	loans.Get("/active", circulation, loanHandler.GetActiveLoans)
+	loans.Get("/overdue", circulation, loanHandler.GetOverdueLoans)
+	loans.Get("/user/:userId", middleware.RequirePermissionOrSelf(domain.PermCirculation, "userId"), loanHandler.GetLoansByUser)
+	loans.Get("/book/:bookId", circulation, loanHandler.GetLoansByBook)
+	loans.Put("/:id/return", circulation, loanHandler.ReturnLoan)
+	loans.Put("/:id/renew", circulation, loanHandler.RenewLoan)
+
+	// Hold (reservation) routes; o dono da reserva é conferido no handler
+	holds := api.Group("/holds", requireAuth)
+	holds.Delete("/:id", reservationHandler.CancelHold)
+
+	// Fine routes
+	fines := api.Group("/fines", requireAuth)
+	fines.Post("/accrue", circulation, fineHandler.AccrueOverdueFines)
+
+	// Circulation policy routes
+	policies := api.Group("/policies", requireAuth)
+	policies.Post("/", policiesManage, policyHandler.CreatePolicy)
+	policies.Get("/", circulation, policyHandler.GetAllPolicies)
+	policies.Get("/:id", circulation, policyHandler.GetPolicyByID)
+	policies.Put("/:id", policiesManage, policyHandler.UpdatePolicy)
+	policies.Delete("/:id", policiesManage, policyHandler.DeletePolicy)
+
+	// Circulation report routes
+	reports := api.Group("/reports", requireAuth, circulation)
+	reports.Get("/loans-per-period", reportHandler.LoansPerPeriod)
+	reports.Get("/top-titles", reportHandler.TopTitles)
+	reports.Get("/top-authors", reportHandler.TopAuthors)
+	reports.Get("/active-patrons", reportHandler.ActivePatrons)
+	reports.Get("/overdue-rate", reportHandler.OverdueRate)
+	reports.Get("/loan-duration", reportHandler.LoanDuration)
+	reports.Get("/never-borrowed", reportHandler.NeverBorrowed)
+
+	// Scheduled job routes
+	jobs := api.Group("/jobs", requireAuth, jobsManage)
+	jobs.Get("/", jobHandler.GetAllJobs)
+	jobs.Get("/:name/runs", jobHandler.GetJobRuns)
+	jobs.Post("/:name/run", jobHandler.RunJob)
+
+	// Audit log routes
+	audit := api.Group("/audit", requireAuth, auditRead)
+	audit.Get("/", auditHandler.GetAuditLog)
 }
@@ -0,0 +1,201 @@
+package routes
+
+import (
+	"io"
+	"library-management/internal/domain"
+	"library-management/internal/interfaces/http/middleware"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/google/uuid"
+)
+
+// Usuários dos testes: o leitor autenticado e outro leitor qualquer
+var (
+	selfID  = uuid.MustParse("7c1e2d3f-4a5b-4c6d-8e7f-901a2b3c4d5e")
+	otherID = uuid.MustParse("8d2f3e4a-5b6c-4d7e-9f80-a12b3c4d5e6f")
+)
+
+// testApp monta as rotas com handlers nil: a requisição que passa pelas
+// verificações de acesso cai na validação do corpo (400) ou chega ao handler,
+// que entra em pânico e responde 500. A autenticação é trocada pelo papel informado no cabeçalho X-Test-Role.
+func testApp() *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(recover.New())
+	requireAuth := func(c *fiber.Ctx) error {
+		role := c.Get("X-Test-Role")
+		if role == "" {
+			return domain.NewUnauthenticated("authentication_required", "Autenticação necessária")
+		}
+		middleware.SetPrincipal(c, &domain.Principal{UserID: selfID, Role: domain.Role(role)})
+		return c.Next()
+	}
+	SetupRoutes(app, "*", requireAuth, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	return app
+}
+
+// route é uma rota protegida e a permissão que ela exige; sem permissão, basta
+// estar autenticado. Com self, o leitor também acessa a rota quando o :id é o
+// dele.
+type route struct {
+	method     string
+	path       string
+	permission domain.Permission
+	self       bool
+}
+
+// protectedRoutes usam :id no lugar dos parâmetros; ele é trocado pelo id do
+// usuário dos testes
+var protectedRoutes = []route{
+	{"POST", "/api/auth/logout", "", false},
+	{"GET", "/api/auth/me", "", false},
+	{"PUT", "/api/auth/password", "", false},
+
+	{"POST", "/api/books", domain.PermCatalogWrite, false},
+	{"POST", "/api/books/import-by-isbn", domain.PermCatalogWrite, false},
+	{"POST", "/api/books/import", domain.PermCatalogWrite, false},
+	{"GET", "/api/books/export", domain.PermCatalogWrite, false},
+	{"GET", "/api/books", "", false},
+	{"GET", "/api/books/available", "", false},
+	{"GET", "/api/books/search", "", false},
+	{"GET", "/api/books/isbn/9788535902778", "", false},
+	{"GET", "/api/books/metadata/9788535902778", domain.PermCatalogWrite, false},
+	{"GET", "/api/books/:id", "", false},
+	{"PUT", "/api/books/:id", domain.PermCatalogWrite, false},
+	{"DELETE", "/api/books/:id", domain.PermCatalogWrite, false},
+	{"POST", "/api/books/:id/restore", domain.PermDeletedManage, false},
+	{"GET", "/api/books/:id/items", "", false},
+	{"POST", "/api/books/:id/items", domain.PermCatalogWrite, false},
+	{"GET", "/api/books/:id/holds", domain.PermCirculation, false},
+	{"POST", "/api/books/:id/holds", "", false},
+
+	{"GET", "/api/items/barcode/DC-1", "", false},
+	{"GET", "/api/items/:id", "", false},
+	{"PUT", "/api/items/:id", domain.PermCatalogWrite, false},
+	{"DELETE", "/api/items/:id", domain.PermCatalogWrite, false},
+
+	{"POST", "/api/users", domain.PermUsersManage, false},
+	{"GET", "/api/users", domain.PermUsersRead, false},
+	{"POST", "/api/users/import", domain.PermUsersManage, false},
+	{"GET", "/api/users/export", domain.PermUsersRead, false},
+	{"GET", "/api/users/:id", domain.PermUsersRead, true},
+	{"PUT", "/api/users/:id", domain.PermUsersManage, false},
+	{"DELETE", "/api/users/:id", domain.PermUsersManage, false},
+	{"POST", "/api/users/:id/restore", domain.PermDeletedManage, false},
+	{"PUT", "/api/users/:id/password", domain.PermUsersManage, false},
+	{"GET", "/api/users/:id/holds", domain.PermCirculation, true},
+	{"GET", "/api/users/:id/account", domain.PermCirculation, true},
+	{"POST", "/api/users/:id/account/payments", domain.PermCirculation, false},
+	{"POST", "/api/users/:id/account/waivers", domain.PermCirculation, false},
+	{"POST", "/api/users/:id/account/adjustments", domain.PermCirculation, false},
+	{"GET", "/api/users/:id/standing", domain.PermCirculation, true},
+	{"GET", "/api/users/:id/blocks", domain.PermCirculation, true},
+	{"POST", "/api/users/:id/blocks", domain.PermCirculation, false},
+	{"DELETE", "/api/users/:id/blocks/:id", domain.PermCirculation, false},
+	{"GET", "/api/users/:id/notification-preferences", domain.PermUsersRead, true},
+	{"PUT", "/api/users/:id/notification-preferences", domain.PermUsersManage, true},
+
+	{"POST", "/api/loans", domain.PermCirculation, false},
+	{"GET", "/api/loans", domain.PermCirculation, false},
+	{"GET", "/api/loans/active", domain.PermCirculation, false},
+	{"GET", "/api/loans/overdue", domain.PermCirculation, false},
+	{"GET", "/api/loans/user/:id", domain.PermCirculation, true},
+	{"GET", "/api/loans/book/:id", domain.PermCirculation, false},
+	{"PUT", "/api/loans/:id/return", domain.PermCirculation, false},
+	{"PUT", "/api/loans/:id/renew", domain.PermCirculation, false},
+
+	{"DELETE", "/api/holds/:id", "", false},
+	{"POST", "/api/fines/accrue", domain.PermCirculation, false},
+
+	{"POST", "/api/policies", domain.PermPoliciesManage, false},
+	{"GET", "/api/policies", domain.PermCirculation, false},
+	{"GET", "/api/policies/:id", domain.PermCirculation, false},
+	{"PUT", "/api/policies/:id", domain.PermPoliciesManage, false},
+	{"DELETE", "/api/policies/:id", domain.PermPoliciesManage, false},
+
+	{"GET", "/api/reports/loans-per-period", domain.PermCirculation, false},
+	{"GET", "/api/reports/top-titles", domain.PermCirculation, false},
+	{"GET", "/api/reports/top-authors", domain.PermCirculation, false},
+	{"GET", "/api/reports/active-patrons", domain.PermCirculation, false},
+	{"GET", "/api/reports/overdue-rate", domain.PermCirculation, false},
+	{"GET", "/api/reports/loan-duration", domain.PermCirculation, false},
+	{"GET", "/api/reports/never-borrowed", domain.PermCirculation, false},
+
+	{"GET", "/api/jobs", domain.PermJobsManage, false},
+	{"GET", "/api/jobs/overdue/runs", domain.PermJobsManage, false},
+	{"POST", "/api/jobs/overdue/run", domain.PermJobsManage, false},
+
+	{"GET", "/api/audit", domain.PermAuditRead, false},
+}
+
+// status faz a requisição com o papel informado ("" sem autenticação)
+func status(t *testing.T, app *fiber.App, method, path string, role domain.Role) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	if role != "" {
+		req.Header.Set("X-Test-Role", string(role))
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	return resp.StatusCode
+}
+
+// reached informa se a requisição passou da autenticação e das permissões
+func reached(status int) bool {
+	return status == fiber.StatusBadRequest || status == fiber.StatusInternalServerError
+}
+
+func TestRoutePermissions(t *testing.T) {
+	// Os handlers nil entram em pânico nas requisições autorizadas
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	app := testApp()
+	roles := []domain.Role{domain.RoleAdmin, domain.RoleLibrarian, domain.RolePatron}
+	for _, r := range protectedRoutes {
+		path := strings.ReplaceAll(r.path, ":id", otherID.String())
+		t.Run(r.method+" "+r.path, func(t *testing.T) {
+			if got := status(t, app, r.method, path, ""); got != fiber.StatusUnauthorized {
+				t.Errorf("sem autenticação: status %d, esperado 401", got)
+			}
+			for _, role := range roles {
+				got := status(t, app, r.method, path, role)
+				if r.permission == "" || role.Can(r.permission) {
+					if !reached(got) {
+						t.Errorf("%s: status %d, esperado que chegasse ao handler", role, got)
+					}
+				} else if got != fiber.StatusForbidden {
+					t.Errorf("%s: status %d, esperado 403", role, got)
+				}
+			}
+			if r.self {
+				own := strings.ReplaceAll(r.path, ":id", selfID.String())
+				if got := status(t, app, r.method, own, domain.RolePatron); !reached(got) {
+					t.Errorf("leitor nos próprios dados: status %d, esperado que chegasse ao handler", got)
+				}
+			}
+		})
+	}
+}
+
+func TestPublicRoutes(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	app := testApp()
+	if got := status(t, app, "GET", "/health", ""); got != fiber.StatusOK {
+		t.Errorf("GET /health: status %d, esperado 200", got)
+	}
+	for _, path := range []string{"/api/auth/login", "/api/auth/refresh"} {
+		if got := status(t, app, "POST", path, ""); !reached(got) {
+			t.Errorf("POST %s sem autenticação: status %d, esperado que chegasse ao handler", path, got)
+		}
+	}
+}
@@ -0,0 +1,452 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"library-management/internal/domain"
+	"reflect"
+	"time"
+)
+
+// AuditService implementa a consulta ao log de auditoria. Os registros são
+// gravados por inTx, na mesma transação das alterações.
+type AuditService struct {
+	auditRepo domain.AuditRepository
+}
+
+// NewAuditService cria uma nova instância do AuditService
+func NewAuditService(auditRepo domain.AuditRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// ListEntries retorna uma página dos registros que atendem aos filtros e o total
+func (s *AuditService) ListEntries(query domain.AuditQuery) ([]*domain.AuditEntry, int, error) {
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return nil, 0, domain.ErrInvalidDateRange
+	}
+	return s.auditRepo.List(query)
+}
+
+// inTx executa fn numa transação de uow com repositórios que registram no
+// log de auditoria cada alteração feita através deles, em nome do usuário
+// autenticado de ctx (ou do sistema) e com o identificador da requisição.
+// Assim as alterações indiretas, como a reserva atendida numa devolução,
+// também ficam registradas, e o registro só existe se a alteração for gravada.
+func inTx(ctx context.Context, uow domain.UnitOfWork, fn func(repos domain.Repositories) error) error {
+	return uow.Do(func(repos domain.Repositories) error {
+		a := &auditor{ctx: ctx, repo: repos.Audit}
+		repos.Books = auditedBooks{repos.Books, a}
+		repos.Items = auditedItems{repos.Items, a}
+		repos.Users = auditedUsers{repos.Users, a}
+		repos.Loans = auditedLoans{repos.Loans, a}
+		repos.Reservations = auditedReservations{repos.Reservations, a}
+		repos.Accounts = auditedAccounts{repos.Accounts, a}
+		repos.Policies = auditedPolicies{repos.Policies, a}
+		repos.Blocks = auditedBlocks{repos.Blocks, a}
+		repos.Credentials = auditedCredentials{repos.Credentials, a}
+		repos.Preferences = auditedPreferences{repos.Preferences, a}
+		return fn(repos)
+	})
+}
+
+// auditor grava os registros de auditoria de uma transação
+type auditor struct {
+	ctx  context.Context
+	repo domain.AuditRepository
+}
+
+// record registra a alteração da entidade; before é nil na criação e after
+// é nil na remoção
+func (a *auditor) record(action domain.AuditAction, entity, entityID string, before, after interface{}) error {
+	changes, err := auditChanges(before, after)
+	if err != nil {
+		return err
+	}
+
+	entry := &domain.AuditEntry{
+		OccurredAt: time.Now(),
+		ActorName:  domain.SystemActor,
+		Action:     action,
+		Entity:     entity,
+		EntityID:   entityID,
+		Changes:    changes,
+		RequestID:  domain.RequestIDFromContext(a.ctx),
+	}
+	if principal := domain.PrincipalFromContext(a.ctx); principal != nil {
+		actorID := principal.UserID
+		entry.ActorID = &actorID
+		entry.ActorName = principal.Name
+	}
+	return a.repo.Append(entry)
+}
+
+// recordPurge registra a exclusão definitiva de cada registro removido; o
+// estado anterior já consta no registro da remoção
+func (a *auditor) recordPurge(entity string, ids []string) error {
+	for _, id := range ids {
+		if err := a.record(domain.AuditPurge, entity, id, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// softDeleteAction classifica a gravação de um registro com remoção lógica:
+// preencher DeletedAt é uma remoção, e limpá-lo, uma restauração
+func softDeleteAction(before, after *time.Time) domain.AuditAction {
+	switch {
+	case before == nil && after != nil:
+		return domain.AuditDelete
+	case before != nil && after == nil:
+		return domain.AuditRestore
+	}
+	return domain.AuditUpdate
+}
+
+// auditIgnoredFields são os campos que não entram no registro: os
+// carimbos de data, os objetos relacionados e os valores calculados
+var auditIgnoredFields = map[string]bool{
+	"id": true, "created_at": true, "updated_at": true,
+	"book": true, "item": true, "user": true,
+	"is_available": true, "total_copies": true, "available_copies": true, "position": true,
+}
+
+// auditChanges compara os campos de before e after, como aparecem na API, e
+// retorna os que mudaram
+func auditChanges(before, after interface{}) (map[string]domain.AuditChange, error) {
+	old, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	current, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]domain.AuditChange{}
+	for field, value := range current {
+		if !auditEqual(old[field], value) {
+			changes[field] = domain.AuditChange{Before: old[field], After: value}
+		}
+	}
+	for field, value := range old {
+		if _, ok := current[field]; !ok && value != nil {
+			changes[field] = domain.AuditChange{Before: value}
+		}
+	}
+	return changes, nil
+}
+
+// auditFields converte a entidade nos seus campos JSON, sem os ignorados
+func auditFields(entity interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if entity == nil || reflect.ValueOf(entity).IsNil() {
+		return fields, nil
+	}
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for field := range auditIgnoredFields {
+		delete(fields, field)
+	}
+	return fields, nil
+}
+
+// auditEqual compara dois valores JSON; datas são comparadas pelo instante,
+// já que o banco pode devolvê-las em outro fuso
+func auditEqual(a, b interface{}) bool {
+	if sa, ok := a.(string); ok {
+		if sb, ok := b.(string); ok {
+			ta, errA := time.Parse(time.RFC3339Nano, sa)
+			tb, errB := time.Parse(time.RFC3339Nano, sb)
+			if errA == nil && errB == nil {
+				return ta.Equal(tb)
+			}
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+type auditedBooks struct {
+	domain.BookRepository
+	audit *auditor
+}
+
+func (r auditedBooks) Create(book *domain.Book) error {
+	if err := r.BookRepository.Create(book); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditCreate, domain.AuditEntityBook, book.ID.String(), nil, book)
+}
+
+func (r auditedBooks) Update(book *domain.Book) error {
+	before, err := r.BookRepository.GetByIDWithDeleted(book.ID.String())
+	if err != nil {
+		return err
+	}
+	if err := r.BookRepository.Update(book); err != nil {
+		return err
+	}
+	action := softDeleteAction(before.DeletedAt, book.DeletedAt)
+	return r.audit.record(action, domain.AuditEntityBook, book.ID.String(), before, book)
+}
+
+func (r auditedBooks) Purge(deletedBefore time.Time) ([]string, error) {
+	ids, err := r.BookRepository.Purge(deletedBefore)
+	if err != nil {
+		return nil, err
+	}
+	return ids, r.audit.recordPurge(domain.AuditEntityBook, ids)
+}
+
+type auditedItems struct {
+	domain.ItemRepository
+	audit *auditor
+}
+
+func (r auditedItems) Create(item *domain.Item) error {
+	if err := r.ItemRepository.Create(item); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditCreate, domain.AuditEntityItem, item.ID.String(), nil, item)
+}
+
+func (r auditedItems) Update(item *domain.Item) error {
+	before, err := r.ItemRepository.GetByID(item.ID.String())
+	if err != nil {
+		return err
+	}
+	if err := r.ItemRepository.Update(item); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditUpdate, domain.AuditEntityItem, item.ID.String(), before, item)
+}
+
+func (r auditedItems) Delete(id string) error {
+	before, err := r.ItemRepository.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := r.ItemRepository.Delete(id); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditDelete, domain.AuditEntityItem, id, before, nil)
+}
+
+type auditedUsers struct {
+	domain.UserRepository
+	audit *auditor
+}
+
+func (r auditedUsers) Create(user *domain.User) error {
+	if err := r.UserRepository.Create(user); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditCreate, domain.AuditEntityUser, user.ID.String(), nil, user)
+}
+
+func (r auditedUsers) Update(user *domain.User) error {
+	before, err := r.UserRepository.GetByIDWithDeleted(user.ID.String())
+	if err != nil {
+		return err
+	}
+	if err := r.UserRepository.Update(user); err != nil {
+		return err
+	}
+	action := softDeleteAction(before.DeletedAt, user.DeletedAt)
+	return r.audit.record(action, domain.AuditEntityUser, user.ID.String(), before, user)
+}
+
+func (r auditedUsers) Purge(deletedBefore time.Time) ([]string, error) {
+	ids, err := r.UserRepository.Purge(deletedBefore)
+	if err != nil {
+		return nil, err
+	}
+	return ids, r.audit.recordPurge(domain.AuditEntityUser, ids)
+}
+
+type auditedLoans struct {
+	domain.LoanRepository
+	audit *auditor
+}
+
+func (r auditedLoans) Create(loan *domain.Loan) error {
+	if err := r.LoanRepository.Create(loan); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditCreate, domain.AuditEntityLoan, loan.ID.String(), nil, loan)
+}
+
+func (r auditedLoans) Update(loan *domain.Loan) error {
+	before, err := r.LoanRepository.GetByID(loan.ID.String())
+	if err != nil {
+		return err
+	}
+	if err := r.LoanRepository.Update(loan); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditUpdate, domain.AuditEntityLoan, loan.ID.String(), before, loan)
+}
+
+func (r auditedLoans) Delete(id string) error {
+	before, err := r.LoanRepository.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := r.LoanRepository.Delete(id); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditDelete, domain.AuditEntityLoan, id, before, nil)
+}
+
+func (r auditedLoans) MarkOverdue(now time.Time) ([]string, error) {
+	ids, err := r.LoanRepository.MarkOverdue(now)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		before, after := map[string]bool{"is_overdue": false}, map[string]bool{"is_overdue": true}
+		if err := r.audit.record(domain.AuditUpdate, domain.AuditEntityLoan, id, before, after); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+type auditedReservations struct {
+	domain.ReservationRepository
+	audit *auditor
+}
+
+func (r auditedReservations) Create(reservation *domain.Reservation) error {
+	if err := r.ReservationRepository.Create(reservation); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditCreate, domain.AuditEntityReservation, reservation.ID.String(), nil, reservation)
+}
+
+func (r auditedReservations) Update(reservation *domain.Reservation) error {
+	before, err := r.ReservationRepository.GetByID(reservation.ID.String())
+	if err != nil {
+		return err
+	}
+	if err := r.ReservationRepository.Update(reservation); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditUpdate, domain.AuditEntityReservation, reservation.ID.String(), before, reservation)
+}
+
+type auditedAccounts struct {
+	domain.AccountRepository
+	audit *auditor
+}
+
+func (r auditedAccounts) Create(entry *domain.AccountEntry) error {
+	if err := r.AccountRepository.Create(entry); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditCreate, domain.AuditEntityAccountEntry, entry.ID.String(), nil, entry)
+}
+
+type auditedPolicies struct {
+	domain.PolicyRepository
+	audit *auditor
+}
+
+func (r auditedPolicies) Create(policy *domain.CirculationPolicy) error {
+	if err := r.PolicyRepository.Create(policy); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditCreate, domain.AuditEntityPolicy, policy.ID.String(), nil, policy)
+}
+
+func (r auditedPolicies) Update(policy *domain.CirculationPolicy) error {
+	before, err := r.PolicyRepository.GetByID(policy.ID.String())
+	if err != nil {
+		return err
+	}
+	if err := r.PolicyRepository.Update(policy); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditUpdate, domain.AuditEntityPolicy, policy.ID.String(), before, policy)
+}
+
+func (r auditedPolicies) Delete(id string) error {
+	before, err := r.PolicyRepository.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := r.PolicyRepository.Delete(id); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditDelete, domain.AuditEntityPolicy, id, before, nil)
+}
+
+type auditedBlocks struct {
+	domain.BlockRepository
+	audit *auditor
+}
+
+func (r auditedBlocks) Create(block *domain.PatronBlock) error {
+	if err := r.BlockRepository.Create(block); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditCreate, domain.AuditEntityBlock, block.ID.String(), nil, block)
+}
+
+func (r auditedBlocks) Update(block *domain.PatronBlock) error {
+	before, err := r.BlockRepository.GetByID(block.ID.String())
+	if err != nil {
+		return err
+	}
+	if err := r.BlockRepository.Update(block); err != nil {
+		return err
+	}
+	return r.audit.record(domain.AuditUpdate, domain.AuditEntityBlock, block.ID.String(), before, block)
+}
+
+// auditedCredentials registra a troca de senha sem guardar a senha: o
+// registro tem o id do usuário e nenhum campo alterado
+type auditedCredentials struct {
+	domain.CredentialRepository
+	audit *auditor
+}
+
+func (r auditedCredentials) Save(credential *domain.Credential) error {
+	before, err := r.CredentialRepository.GetByUser(credential.UserID.String())
+	if err != nil {
+		return err
+	}
+	if err := r.CredentialRepository.Save(credential); err != nil {
+		return err
+	}
+	action := domain.AuditUpdate
+	if before == nil {
+		action = domain.AuditCreate
+	}
+	return r.audit.record(action, domain.AuditEntityCredential, credential.UserID.String(), nil, nil)
+}
+
+type auditedPreferences struct {
+	domain.NotificationPreferenceRepository
+	audit *auditor
+}
+
+func (r auditedPreferences) Save(preferences *domain.NotificationPreferences) error {
+	before, err := r.NotificationPreferenceRepository.Get(preferences.UserID.String())
+	if err != nil {
+		return err
+	}
+	if err := r.NotificationPreferenceRepository.Save(preferences); err != nil {
+		return err
+	}
+	action := domain.AuditUpdate
+	if before == nil {
+		action = domain.AuditCreate
+	}
+	return r.audit.record(action, domain.AuditEntityNotificationPreferences, preferences.UserID.String(), before, preferences)
+}
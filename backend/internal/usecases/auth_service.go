@@ -0,0 +1,308 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"library-management/internal/domain"
+	"strings"
+	"time"
+)
+
+// MinPasswordLength é o tamanho mínimo de uma senha
+const MinPasswordLength = 8
+
+// ErrInvalidCredentials é retornado quando o email ou a senha não conferem
+var ErrInvalidCredentials = domain.NewUnauthenticated("invalid_credentials", "email ou senha inválidos")
+
+// ErrUnauthenticated é retornado quando o token de acesso ou de renovação é inválido, expirou ou foi revogado
+var ErrUnauthenticated = domain.NewUnauthenticated("session_expired", "sessão inválida ou expirada")
+
+// AuthService implementa os casos de uso de autenticação: login, renovação e
+// encerramento de sessões e cadastro de senhas
+type AuthService struct {
+	userRepo    domain.UserRepository
+	sessionRepo domain.SessionRepository
+	uow         domain.UnitOfWork
+	hasher      domain.PasswordHasher
+	tokens      domain.TokenIssuer
+	refreshTTL  time.Duration
+	// dummyHash é comparado quando o email não existe, para que o tempo de
+	// resposta não revele quais emails estão cadastrados
+	dummyHash string
+}
+
+// NewAuthService cria uma nova instância do AuthService
+func NewAuthService(userRepo domain.UserRepository, sessionRepo domain.SessionRepository, uow domain.UnitOfWork, hasher domain.PasswordHasher, tokens domain.TokenIssuer, refreshTTL time.Duration) *AuthService {
+	dummyHash, _ := hasher.Hash("senha-inexistente")
+	return &AuthService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		uow:         uow,
+		hasher:      hasher,
+		tokens:      tokens,
+		refreshTTL:  refreshTTL,
+		dummyHash:   dummyHash,
+	}
+}
+
+// Login confere email e senha e abre uma nova sessão
+func (s *AuthService) Login(email, password string) (*domain.AuthTokens, error) {
+	var tokens *domain.AuthTokens
+	err := s.uow.Do(func(repos domain.Repositories) error {
+		user, _ := repos.Users.GetByEmail(strings.TrimSpace(email))
+		var credential *domain.Credential
+		if user != nil {
+			var err error
+			credential, err = repos.Credentials.GetByUser(user.ID.String())
+			if err != nil {
+				return err
+			}
+		}
+		if credential == nil {
+			s.hasher.Compare(s.dummyHash, password)
+			return ErrInvalidCredentials
+		}
+		if err := s.hasher.Compare(credential.PasswordHash, password); err != nil {
+			return ErrInvalidCredentials
+		}
+
+		now := time.Now()
+		refreshToken, err := newRefreshToken()
+		if err != nil {
+			return err
+		}
+		session := &domain.Session{
+			UserID:     user.ID,
+			TokenHash:  hashToken(refreshToken),
+			ExpiresAt:  now.Add(s.refreshTTL),
+			LastUsedAt: now,
+			CreatedAt:  now,
+		}
+		if err := repos.Sessions.Create(session); err != nil {
+			return err
+		}
+
+		tokens, err = s.issueTokens(user, session, refreshToken)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Refresh troca um refresh token válido por um novo par de tokens. O refresh
+// token usado deixa de valer, de modo que cada um só pode ser usado uma vez.
+func (s *AuthService) Refresh(refreshToken string) (*domain.AuthTokens, error) {
+	var tokens *domain.AuthTokens
+	err := s.uow.Do(func(repos domain.Repositories) error {
+		session, err := repos.Sessions.GetByTokenHash(hashToken(refreshToken))
+		if err != nil {
+			return ErrUnauthenticated
+		}
+
+		now := time.Now()
+		if !session.IsActive(now) {
+			return ErrUnauthenticated
+		}
+
+		user, err := repos.Users.GetByID(session.UserID.String())
+		if err != nil {
+			return ErrUnauthenticated
+		}
+
+		newToken, err := newRefreshToken()
+		if err != nil {
+			return err
+		}
+		session.TokenHash = hashToken(newToken)
+		session.ExpiresAt = now.Add(s.refreshTTL)
+		session.LastUsedAt = now
+		if err := repos.Sessions.Update(session); err != nil {
+			return err
+		}
+
+		tokens, err = s.issueTokens(user, session, newToken)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Logout revoga a sessão; os tokens de acesso emitidos para ela deixam de valer imediatamente
+func (s *AuthService) Logout(sessionID string) error {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return ErrUnauthenticated
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	session.RevokedAt = &now
+	return s.sessionRepo.Update(session)
+}
+
+// Authenticate valida um token de acesso e retorna o usuário autenticado.
+// Além da assinatura e da validade do token, a sessão de origem precisa
+// continuar ativa.
+func (s *AuthService) Authenticate(accessToken string) (*domain.Principal, error) {
+	principal, err := s.tokens.Parse(accessToken)
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+
+	session, err := s.sessionRepo.GetByID(principal.SessionID.String())
+	if err != nil || session.UserID != principal.UserID || !session.IsActive(time.Now()) {
+		return nil, ErrUnauthenticated
+	}
+
+	// O papel é lido do cadastro a cada requisição, para que mudanças de
+	// papel valham sem esperar o token expirar
+	user, err := s.userRepo.GetByID(principal.UserID.String())
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+	principal.Role = user.Role
+
+	return principal, nil
+}
+
+// ChangePassword troca a senha do próprio usuário, conferindo a senha atual.
+// As demais sessões do usuário são encerradas.
+func (s *AuthService) ChangePassword(ctx context.Context, principal *domain.Principal, currentPassword, newPassword string) error {
+	return inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		credential, err := repos.Credentials.GetByUser(principal.UserID.String())
+		if err != nil {
+			return err
+		}
+		if credential == nil || s.hasher.Compare(credential.PasswordHash, currentPassword) != nil {
+			return domain.NewFieldError("current_password", "wrong_password", "senha atual incorreta")
+		}
+
+		if err := s.savePassword(repos, credential, newPassword); err != nil {
+			return err
+		}
+		return repos.Sessions.RevokeByUser(principal.UserID.String(), principal.SessionID.String(), time.Now())
+	})
+}
+
+// SetPassword define a senha de um usuário (cadastro inicial ou redefinição
+// pela equipe). Todas as sessões do usuário são encerradas.
+func (s *AuthService) SetPassword(ctx context.Context, userID, password string) error {
+	return inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		user, err := repos.Users.GetByID(userID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.savePassword(repos, &domain.Credential{UserID: user.ID}, password); err != nil {
+			return err
+		}
+		return repos.Sessions.RevokeByUser(userID, "", time.Now())
+	})
+}
+
+// EnsureAdmin garante que exista um administrador com o email informado e
+// com senha cadastrada, criando-o ou promovendo-o se necessário. Usado para
+// criar o primeiro acesso à aplicação. A senha de um usuário que já tem senha
+// não é alterada.
+func (s *AuthService) EnsureAdmin(ctx context.Context, name, email, password string) (*domain.User, error) {
+	var user *domain.User
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		now := time.Now()
+		user, _ = repos.Users.GetByEmail(email)
+		if user == nil {
+			user = &domain.User{
+				Name:      name,
+				Email:     email,
+				Category:  domain.PatronCategoryStaff,
+				Role:      domain.RoleAdmin,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			if err := repos.Users.Create(user); err != nil {
+				return err
+			}
+		} else if user.Role != domain.RoleAdmin {
+			user.Role = domain.RoleAdmin
+			user.UpdatedAt = now
+			if err := repos.Users.Update(user); err != nil {
+				return err
+			}
+		}
+
+		credential, err := repos.Credentials.GetByUser(user.ID.String())
+		if err != nil || credential != nil {
+			return err
+		}
+		return s.savePassword(repos, &domain.Credential{UserID: user.ID}, password)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// savePassword valida a nova senha e grava o hash
+func (s *AuthService) savePassword(repos domain.Repositories, credential *domain.Credential, password string) error {
+	if len(password) < MinPasswordLength {
+		return domain.NewFieldError("password", "password_too_short", "a senha deve ter pelo menos 8 caracteres")
+	}
+
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	credential.PasswordHash = hash
+	credential.UpdatedAt = time.Now()
+	return repos.Credentials.Save(credential)
+}
+
+// issueTokens emite o token de acesso da sessão junto com o refresh token
+func (s *AuthService) issueTokens(user *domain.User, session *domain.Session, refreshToken string) (*domain.AuthTokens, error) {
+	accessToken, expiresAt, err := s.tokens.Issue(&domain.Principal{
+		UserID:    user.ID,
+		SessionID: session.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      user.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AuthTokens{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+		User:             user,
+	}, nil
+}
+
+// newRefreshToken gera um refresh token aleatório
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken calcula o hash guardado no banco para um refresh token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,8 +1,10 @@
 package usecases
 
 import (
+	"context"
 	"errors"
 	"library-management/internal/domain"
+	"strings"
 	"time"
 )
 
@@ -10,46 +12,102 @@ import (
 type BookService struct {
 	bookRepo domain.BookRepository
 	loanRepo domain.LoanRepository
+	uow      domain.UnitOfWork
+	metadata domain.MetadataProvider
 }
 
 // NewBookService cria uma nova instância do BookService
-func NewBookService(bookRepo domain.BookRepository, loanRepo domain.LoanRepository) *BookService {
+func NewBookService(bookRepo domain.BookRepository, loanRepo domain.LoanRepository, uow domain.UnitOfWork, metadata domain.MetadataProvider) *BookService {
 	return &BookService{
 		bookRepo: bookRepo,
 		loanRepo: loanRepo,
+		uow:      uow,
+		metadata: metadata,
 	}
 }
 
-// CreateBook cria um novo livro
-func (s *BookService) CreateBook(title, author string, yearPublished int, isbn string) (*domain.Book, error) {
-	if title == "" {
-		return nil, errors.New("título é obrigatório")
+// CreateBook cria um novo livro com a quantidade informada de exemplares (no mínimo um)
+func (s *BookService) CreateBook(ctx context.Context, title, author string, yearPublished int, isbn, publisher, coverURL string, subjects []string, materialType string, copies int) (*domain.Book, error) {
+	if err := validateBookFields(title, author); err != nil {
+		return nil, err
 	}
-	if author == "" {
-		return nil, errors.New("autor é obrigatório")
+
+	if copies <= 0 {
+		copies = 1
+	}
+	if materialType == "" {
+		materialType = domain.DefaultMaterialType
 	}
 
 	book := &domain.Book{
 		Title:         title,
 		Author:        author,
 		YearPublished: yearPublished,
-		ISBN:          isbn,
-		IsAvailable:   true,
+		Publisher:     publisher,
+		CoverURL:      coverURL,
+		Subjects:      normalizeSubjects(subjects),
+		MaterialType:  materialType,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
+	if err := setISBN(book, isbn); err != nil {
+		return nil, err
+	}
 
-	err := s.bookRepo.Create(book)
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		if err := repos.Books.Create(book); err != nil {
+			return err
+		}
+		for i := 0; i < copies; i++ {
+			if err := repos.Items.Create(newItem(book.ID, "", "")); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	book.TotalCopies = copies
+	book.AvailableCopies = copies
+	book.IsAvailable = true
+
 	return book, nil
 }
 
-// GetAllBooks retorna todos os livros
-func (s *BookService) GetAllBooks() ([]*domain.Book, error) {
-	return s.bookRepo.GetAll()
+// ListBooks retorna uma página dos livros que atendem aos filtros e o total de livros encontrados
+func (s *BookService) ListBooks(query domain.BookQuery) ([]*domain.Book, int, error) {
+	if query.YearFrom > 0 && query.YearTo > 0 && query.YearFrom > query.YearTo {
+		return nil, 0, domain.NewFieldError("year_from", "invalid_year_range", "ano inicial maior que o ano final")
+	}
+
+	books, total, err := s.bookRepo.List(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	if books == nil {
+		books = []*domain.Book{}
+	}
+	return books, total, nil
+}
+
+// SearchBooks busca livros pelo título, autor, ISBN ou assuntos, sem
+// diferenciar acentos nem maiúsculas, do mais ao menos relevante
+func (s *BookService) SearchBooks(search domain.BookSearch) ([]*domain.BookSearchResult, int, error) {
+	search.Text = strings.TrimSpace(search.Text)
+	if search.Text == "" {
+		return nil, 0, domain.NewFieldError("q", "search_text_required", "informe o texto da busca")
+	}
+
+	results, total, err := s.bookRepo.Search(search)
+	if err != nil {
+		return nil, 0, err
+	}
+	if results == nil {
+		results = []*domain.BookSearchResult{}
+	}
+	return results, total, nil
 }
 
 // GetBookByID retorna um livro pelo ID
@@ -57,8 +115,61 @@ func (s *BookService) GetBookByID(id string) (*domain.Book, error) {
 	return s.bookRepo.GetByID(id)
 }
 
-// UpdateBook atualiza um livro existente
-func (s *BookService) UpdateBook(id, title, author string, yearPublished int, isbn string) (*domain.Book, error) {
+// GetBookByIDWithDeleted retorna um livro pelo ID, mesmo que removido
+func (s *BookService) GetBookByIDWithDeleted(id string) (*domain.Book, error) {
+	return s.bookRepo.GetByIDWithDeleted(id)
+}
+
+// GetBookByISBN retorna o livro com o ISBN informado, em qualquer formato
+// aceito por domain.NormalizeISBN
+func (s *BookService) GetBookByISBN(isbn string) (*domain.Book, error) {
+	normalized, err := domain.NormalizeISBN(isbn)
+	if err != nil {
+		return nil, err
+	}
+	return s.bookRepo.GetByISBN(normalized)
+}
+
+// LookupMetadata busca nas fontes de dados bibliográficos os dados do livro
+// com o ISBN informado, sem cadastrá-lo (para preencher o formulário)
+func (s *BookService) LookupMetadata(isbn string) (*domain.BookMetadata, error) {
+	normalized, err := domain.NormalizeISBN(isbn)
+	if err != nil {
+		return nil, err
+	}
+	return s.metadata.LookupISBN(normalized)
+}
+
+// ImportBookByISBN cadastra um livro com os dados bibliográficos (título,
+// autores, ano, editora e capa) obtidos pelo ISBN
+func (s *BookService) ImportBookByISBN(ctx context.Context, isbn, materialType string, copies int) (*domain.Book, error) {
+	normalized, err := domain.NormalizeISBN(isbn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Evita consultar as fontes externas por um livro já cadastrado
+	if _, err := s.bookRepo.GetByISBN(normalized); err == nil {
+		return nil, domain.ErrISBNTaken
+	} else if !errors.Is(err, domain.ErrBookNotFound) {
+		return nil, err
+	}
+
+	metadata, err := s.metadata.LookupISBN(normalized)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.Title == "" || len(metadata.Authors) == 0 {
+		return nil, domain.NewValidation("metadata_incomplete", "a fonte de dados bibliográficos não informou o título e o autor deste ISBN")
+	}
+
+	return s.CreateBook(ctx, metadata.Title, strings.Join(metadata.Authors, ", "), metadata.YearPublished,
+		isbn, metadata.Publisher, metadata.CoverURL, nil, materialType, copies)
+}
+
+// UpdateBook atualiza um livro existente; isbn e subjects nil mantêm o ISBN e os
+// assuntos atuais, e isbn vazio remove o ISBN
+func (s *BookService) UpdateBook(ctx context.Context, id, title, author string, yearPublished int, isbn *string, publisher, coverURL string, subjects []string, materialType string) (*domain.Book, error) {
 	book, err := s.bookRepo.GetByID(id)
 	if err != nil {
 		return nil, err
@@ -73,10 +184,29 @@ func (s *BookService) UpdateBook(id, title, author string, yearPublished int, is
 	if yearPublished > 0 {
 		book.YearPublished = yearPublished
 	}
-	book.ISBN = isbn
+	if isbn != nil {
+		if err := setISBN(book, *isbn); err != nil {
+			return nil, err
+		}
+	}
+	if publisher != "" {
+		book.Publisher = publisher
+	}
+	if coverURL != "" {
+		book.CoverURL = coverURL
+	}
+	if subjects != nil {
+		book.Subjects = normalizeSubjects(subjects)
+	}
+	if materialType != "" {
+		book.MaterialType = materialType
+	}
 	book.UpdatedAt = time.Now()
 
-	err = s.bookRepo.Update(book)
+	// O livro e o índice de busca são atualizados na mesma transação
+	err = inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		return repos.Books.Update(book)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -84,21 +214,124 @@ func (s *BookService) UpdateBook(id, title, author string, yearPublished int, is
 	return book, nil
 }
 
-// DeleteBook remove um livro
-func (s *BookService) DeleteBook(id string) error {
-	// Verificar se o livro está emprestado
-	activeLoan, err := s.loanRepo.GetActiveLoanByBook(id)
+// DeleteBook remove logicamente um livro: ele sai do catálogo e da busca,
+// mas o histórico de empréstimos continua apontando para ele até o expurgo.
+// Os exemplares são mantidos e voltam com o livro se ele for restaurado.
+func (s *BookService) DeleteBook(ctx context.Context, id string) error {
+	return inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		book, err := repos.Books.GetByID(id)
+		if err != nil {
+			return err
+		}
+
+		// Verificar se algum exemplar do livro está emprestado
+		activeLoan, err := repos.Loans.GetActiveLoanByBook(id)
+		if err != nil {
+			return err
+		}
+		if activeLoan != nil {
+			return domain.NewConflict("delete_book_on_loan", "não é possível deletar um livro que está emprestado")
+		}
+
+		queue, err := repos.Reservations.GetQueueByBook(id)
+		if err != nil {
+			return err
+		}
+		if len(queue) > 0 {
+			return domain.NewConflict("delete_book_has_holds", "não é possível deletar um livro com reservas ativas")
+		}
+
+		now := time.Now()
+		book.DeletedAt = &now
+		book.UpdatedAt = now
+		return repos.Books.Update(book)
+	})
+}
+
+// RestoreBook desfaz a remoção de um livro ainda não expurgado, se nenhum
+// livro ativo tiver passado a usar o ISBN dele
+func (s *BookService) RestoreBook(ctx context.Context, id string) (*domain.Book, error) {
+	var book *domain.Book
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		var err error
+		book, err = repos.Books.GetByIDWithDeleted(id)
+		if err != nil {
+			return err
+		}
+		if book.DeletedAt == nil {
+			return domain.ErrBookNotDeleted
+		}
+		if book.ISBN != "" {
+			if other, err := repos.Books.GetByISBN(book.ISBN); err == nil {
+				return domain.ErrRestoreISBNTaken.WithParams(map[string]interface{}{"book_id": other.ID})
+			} else if !errors.Is(err, domain.ErrBookNotFound) {
+				return err
+			}
+		}
+
+		book.DeletedAt = nil
+		book.UpdatedAt = time.Now()
+		return repos.Books.Update(book)
+	})
 	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// PurgeBooks apaga de vez os livros removidos antes de deletedBefore, com os
+// exemplares deles, e retorna quantos foram apagados. Os livros que aparecem
+// no histórico de empréstimos ou reservas dos leitores são mantidos.
+func (s *BookService) PurgeBooks(ctx context.Context, deletedBefore time.Time) (int, error) {
+	var ids []string
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		var err error
+		ids, err = repos.Books.Purge(deletedBefore)
 		return err
+	})
+	return len(ids), err
+}
+
+// setISBN valida e normaliza o ISBN do livro, guardando a forma informada
+// para exibição. ISBN vazio remove o ISBN do livro.
+func setISBN(book *domain.Book, isbn string) error {
+	isbn = strings.TrimSpace(isbn)
+	if isbn == "" {
+		book.ISBN, book.ISBNDisplay = "", ""
+		return nil
 	}
-	if activeLoan != nil {
-		return errors.New("não é possível deletar um livro que está emprestado")
+
+	normalized, err := domain.NormalizeISBN(isbn)
+	if err != nil {
+		return err
 	}
+	book.ISBN, book.ISBNDisplay = normalized, isbn
+	return nil
+}
 
-	return s.bookRepo.Delete(id)
+// validateBookFields confere os campos obrigatórios de um livro novo
+func validateBookFields(title, author string) error {
+	if title == "" {
+		return domain.NewFieldError("title", "title_required", "título é obrigatório")
+	}
+	if author == "" {
+		return domain.NewFieldError("author", "author_required", "autor é obrigatório")
+	}
+	return nil
 }
 
-// GetAvailableBooks retorna todos os livros disponíveis
-func (s *BookService) GetAvailableBooks() ([]*domain.Book, error) {
-	return s.bookRepo.GetAvailable()
+// normalizeSubjects remove espaços, assuntos vazios e repetidos
+func normalizeSubjects(subjects []string) []string {
+	var normalized []string
+	seen := make(map[string]bool, len(subjects))
+	for _, subject := range subjects {
+		subject = strings.Join(strings.Fields(subject), " ")
+		key := strings.ToLower(subject)
+		if subject == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, subject)
+	}
+	return normalized
 }
@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"library-management/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestUpdateBookISBN(t *testing.T) {
+	emptyISBN := ""
+	newISBN := "978-0-306-40615-7"
+
+	tests := []struct {
+		name        string
+		isbn        *string
+		wantISBN    string
+		wantDisplay string
+	}{
+		{"ausente mantém o ISBN", nil, "9788535902778", "85-359-0277-5"},
+		{"vazio remove o ISBN", &emptyISBN, "", ""},
+		{"novo ISBN substitui o atual", &newISBN, "9780306406157", newISBN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestBookService(newTestDB(t))
+			book, err := service.CreateBook(context.Background(), "Dom Casmurro", "Machado de Assis", 1899,
+				"85-359-0277-5", "", "", nil, "", 1)
+			if err != nil {
+				t.Fatalf("erro ao criar o livro: %v", err)
+			}
+
+			if _, err := service.UpdateBook(context.Background(), book.ID.String(), "Dom Casmurro (ed. revista)", "", 0,
+				tt.isbn, "", "", nil, ""); err != nil {
+				t.Fatalf("erro ao atualizar o livro: %v", err)
+			}
+
+			got, err := service.GetBookByID(book.ID.String())
+			if err != nil {
+				t.Fatalf("erro ao buscar o livro: %v", err)
+			}
+			if got.ISBN != tt.wantISBN || got.ISBNDisplay != tt.wantDisplay {
+				t.Errorf("ISBN = %q (%q), esperado %q (%q)", got.ISBN, got.ISBNDisplay, tt.wantISBN, tt.wantDisplay)
+			}
+			if got.Title != "Dom Casmurro (ed. revista)" {
+				t.Errorf("título = %q, esperado o título atualizado", got.Title)
+			}
+		})
+	}
+}
+
+// TestDeletedBookISBN confere que o ISBN de um livro removido pode ser usado
+// por outro livro, e que então o removido não pode ser restaurado
+func TestDeletedBookISBN(t *testing.T) {
+	ctx := context.Background()
+	service := newTestBookService(newTestDB(t))
+
+	deleted, err := service.CreateBook(ctx, "Dom Casmurro", "Machado de Assis", 1899, "8535902775", "", "", nil, "", 1)
+	if err != nil {
+		t.Fatalf("erro ao criar o livro: %v", err)
+	}
+	if err := service.DeleteBook(ctx, deleted.ID.String()); err != nil {
+		t.Fatalf("erro ao remover o livro: %v", err)
+	}
+
+	active, err := service.CreateBook(ctx, "Dom Casmurro (nova edição)", "Machado de Assis", 2016, "978-85-359-0277-8", "", "", nil, "", 1)
+	if err != nil {
+		t.Fatalf("erro ao criar outro livro com o ISBN do removido: %v", err)
+	}
+	if _, err := service.CreateBook(ctx, "Dom Casmurro", "Machado de Assis", 1899, "8535902775", "", "", nil, "", 1); !errors.Is(err, domain.ErrISBNTaken) {
+		t.Errorf("ISBN de um livro ativo: erro = %v, esperado %v", err, domain.ErrISBNTaken)
+	}
+
+	if _, err := service.RestoreBook(ctx, deleted.ID.String()); !errors.Is(err, domain.ErrRestoreISBNTaken) {
+		t.Fatalf("restauração: erro = %v, esperado %v", err, domain.ErrRestoreISBNTaken)
+	}
+
+	if err := service.DeleteBook(ctx, active.ID.String()); err != nil {
+		t.Fatalf("erro ao remover o outro livro: %v", err)
+	}
+	if _, err := service.RestoreBook(ctx, deleted.ID.String()); err != nil {
+		t.Errorf("restauração com o ISBN livre: erro inesperado %v", err)
+	}
+}
+
+// TestPurgeBooks confere que o expurgo mantém os livros que aparecem no
+// histórico de empréstimos dos leitores
+func TestPurgeBooks(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	service := newTestBookService(db)
+
+	user, err := newTestUserService(db).CreateUser(ctx, "Bentinho", "bentinho@example.com", "", "", "")
+	if err != nil {
+		t.Fatalf("erro ao criar o usuário: %v", err)
+	}
+	neverLent, err := service.CreateBook(ctx, "Helena", "Machado de Assis", 1876, "", "", "", nil, "", 1)
+	if err != nil {
+		t.Fatalf("erro ao criar o livro: %v", err)
+	}
+	lent, err := service.CreateBook(ctx, "Iaiá Garcia", "Machado de Assis", 1878, "", "", "", nil, "", 1)
+	if err != nil {
+		t.Fatalf("erro ao criar o livro: %v", err)
+	}
+	now := time.Now()
+	if _, err := db.Exec(`INSERT INTO loans (id, book_id, user_id, loan_date, due_date, return_date, is_returned)
+		VALUES (?, ?, ?, ?, ?, ?, TRUE)`, uuid.NewString(), lent.ID.String(), user.ID.String(), now, now, now); err != nil {
+		t.Fatalf("erro ao registrar o empréstimo: %v", err)
+	}
+
+	for _, book := range []*domain.Book{neverLent, lent} {
+		if err := service.DeleteBook(ctx, book.ID.String()); err != nil {
+			t.Fatalf("erro ao remover o livro: %v", err)
+		}
+	}
+	purged, err := service.PurgeBooks(ctx, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("erro ao expurgar: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("livros expurgados = %d, esperado 1", purged)
+	}
+
+	if _, err := service.GetBookByIDWithDeleted(neverLent.ID.String()); !errors.Is(err, domain.ErrBookNotFound) {
+		t.Errorf("livro nunca emprestado: erro = %v, esperado %v", err, domain.ErrBookNotFound)
+	}
+	if _, err := service.GetBookByIDWithDeleted(lent.ID.String()); err != nil {
+		t.Errorf("livro emprestado: erro inesperado %v", err)
+	}
+}
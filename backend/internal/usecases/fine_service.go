@@ -0,0 +1,232 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"library-management/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FineService implementa os casos de uso de multas e do extrato financeiro dos usuários
+type FineService struct {
+	accountRepo domain.AccountRepository
+	userRepo    domain.UserRepository
+	uow         domain.UnitOfWork
+	policy      domain.CirculationPolicy
+}
+
+// NewFineService cria uma nova instância do FineService
+func NewFineService(accountRepo domain.AccountRepository, userRepo domain.UserRepository, uow domain.UnitOfWork, policy domain.CirculationPolicy) *FineService {
+	return &FineService{
+		accountRepo: accountRepo,
+		userRepo:    userRepo,
+		uow:         uow,
+		policy:      policy,
+	}
+}
+
+// GetAccount retorna o saldo devedor e o extrato de um usuário
+func (s *FineService) GetAccount(userID string) (*domain.Account, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := s.accountRepo.GetBalance(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.accountRepo.GetByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []*domain.AccountEntry{}
+	}
+
+	return &domain.Account{UserID: user.ID, Balance: balance, Entries: entries}, nil
+}
+
+// RecordPayment registra um pagamento que reduz o saldo devedor do usuário
+func (s *FineService) RecordPayment(ctx context.Context, userID string, amount domain.Money, description string) (*domain.AccountEntry, error) {
+	return s.recordCredit(ctx, userID, "", domain.AccountEntryPayment, amount, description)
+}
+
+// WaiveFine abona parte ou todo o saldo devedor do usuário, opcionalmente vinculado a um empréstimo
+func (s *FineService) WaiveFine(ctx context.Context, userID, loanID string, amount domain.Money, description string) (*domain.AccountEntry, error) {
+	if description == "" {
+		return nil, domain.NewFieldError("description", "waiver_reason_required", "motivo do abono é obrigatório")
+	}
+	return s.recordCredit(ctx, userID, loanID, domain.AccountEntryWaiver, amount, description)
+}
+
+// AdjustBalance registra um ajuste manual, positivo (débito) ou negativo (crédito)
+func (s *FineService) AdjustBalance(ctx context.Context, userID string, amount domain.Money, description string) (*domain.AccountEntry, error) {
+	if amount == 0 {
+		return nil, domain.NewFieldError("amount", "zero_amount", "valor do ajuste não pode ser zero")
+	}
+	if description == "" {
+		return nil, domain.NewFieldError("description", "adjustment_reason_required", "motivo do ajuste é obrigatório")
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	entry, err := newAccountEntry(userID, "", domain.AccountEntryAdjustment, amount, description)
+	if err != nil {
+		return nil, err
+	}
+	err = inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		return repos.Accounts.Create(entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// AccrueOverdueFines lança as multas acumuladas até agora para todos os
+// empréstimos em atraso ainda não devolvidos. A operação é idempotente:
+// só é lançada a diferença entre a multa devida e a já cobrada. Os
+// empréstimos são lidos na mesma transação que lança as multas, para que um
+// empréstimo devolvido no meio do caminho não seja multado.
+// Retorna quantos empréstimos receberam novos lançamentos.
+func (s *FineService) AccrueOverdueFines(ctx context.Context) (int, error) {
+	count := 0
+	now := time.Now()
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		loans, err := repos.Loans.GetOverdueLoans()
+		if err != nil {
+			return err
+		}
+		for _, loan := range loans {
+			entry, err := accrueLoanFine(repos, loan, now, s.policy)
+			if err != nil {
+				return err
+			}
+			if entry != nil {
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// recordCredit registra um lançamento que reduz a dívida (pagamento ou abono).
+// O valor não pode exceder o saldo devedor atual.
+func (s *FineService) recordCredit(ctx context.Context, userID, loanID string, entryType domain.AccountEntryType, amount domain.Money, description string) (*domain.AccountEntry, error) {
+	if amount <= 0 {
+		return nil, domain.NewFieldError("amount", "non_positive_amount", "valor deve ser maior que zero")
+	}
+
+	var entry *domain.AccountEntry
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		if _, err := repos.Users.GetByID(userID); err != nil {
+			return err
+		}
+
+		if loanID != "" {
+			loan, err := repos.Loans.GetByID(loanID)
+			if err != nil {
+				return err
+			}
+			if loan.UserID.String() != userID {
+				return domain.ErrLoanNotFound
+			}
+		}
+
+		balance, err := repos.Accounts.GetBalance(userID)
+		if err != nil {
+			return err
+		}
+		if amount > balance {
+			return domain.NewFieldError("amount", "amount_exceeds_balance", fmt.Sprintf("valor maior que o saldo devedor de %s", balance)).
+				WithParams(map[string]interface{}{"balance": balance})
+		}
+
+		entry, err = newAccountEntry(userID, loanID, entryType, -amount, description)
+		if err != nil {
+			return err
+		}
+		return repos.Accounts.Create(entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// accrueLoanFine lança a diferença entre a multa devida pelo empréstimo até
+// asOf e o que já foi cobrado, pelas taxas da política do usuário e do livro
+// (ou de fallback). Retorna o lançamento criado, ou nil se não havia nada a cobrar.
+func accrueLoanFine(repos domain.Repositories, loan *domain.Loan, asOf time.Time, fallback domain.CirculationPolicy) (*domain.AccountEntry, error) {
+	policy, err := policyFor(repos, fallback, loan.UserID, loan.BookID)
+	if err != nil {
+		return nil, err
+	}
+
+	days := daysOverdue(loan, asOf)
+	due := policy.FineFor(days)
+	if due == 0 {
+		return nil, nil
+	}
+
+	charged, err := repos.Accounts.GetLoanCharges(loan.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if due <= charged {
+		return nil, nil
+	}
+
+	loanID := loan.ID
+	entry := &domain.AccountEntry{
+		UserID:      loan.UserID,
+		LoanID:      &loanID,
+		Type:        domain.AccountEntryCharge,
+		Amount:      due - charged,
+		Description: fmt.Sprintf("Multa por atraso (%d dias)", days),
+		CreatedAt:   asOf,
+	}
+	if err := repos.Accounts.Create(entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// newAccountEntry cria um lançamento validando os IDs informados
+func newAccountEntry(userID, loanID string, entryType domain.AccountEntryType, amount domain.Money, description string) (*domain.AccountEntry, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	entry := &domain.AccountEntry{
+		UserID:      uid,
+		Type:        entryType,
+		Amount:      amount,
+		Description: description,
+		CreatedAt:   time.Now(),
+	}
+	if loanID != "" {
+		lid, err := uuid.Parse(loanID)
+		if err != nil {
+			return nil, domain.ErrLoanNotFound
+		}
+		entry.LoanID = &lid
+	}
+
+	return entry, nil
+}
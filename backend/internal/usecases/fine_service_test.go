@@ -0,0 +1,139 @@
+package usecases
+
+import (
+	"context"
+	"library-management/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestAccrueOverdueFines(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	service := newTestFineService(db)
+	user := createUser(t, db, "bentinho@example.com")
+
+	// Três dias de atraso a R$ 1,00 por dia, na política padrão
+	checkout(t, db, createBook(t, db, "Helena", 1).ID.String(), user.ID.String(), time.Now().Add(24*time.Hour))
+	overdue := checkout(t, db, createBook(t, db, "Dom Casmurro", 1).ID.String(), user.ID.String(), time.Now().Add(-73*time.Hour))
+
+	for run := 1; run <= 2; run++ {
+		count, err := service.AccrueOverdueFines(ctx)
+		want := 1
+		if run == 2 {
+			want = 0
+		}
+		if err != nil || count != want {
+			t.Fatalf("execução %d = %d, %v, esperado %d", run, count, err, want)
+		}
+	}
+
+	var charged int64
+	if err := db.QueryRow(`SELECT SUM(amount) FROM account_entries WHERE loan_id = ?`, overdue.ID.String()).Scan(&charged); err != nil {
+		t.Fatal(err)
+	}
+	if charged != 300 {
+		t.Errorf("multa = %d, esperado 300", charged)
+	}
+}
+
+// TestAccrueOverdueFinesReturned confere que um empréstimo devolvido entre a
+// chamada e a transação não é multado
+func TestAccrueOverdueFinesReturned(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	service := newTestFineService(db)
+	user := createUser(t, db, "bentinho@example.com")
+	loan := checkout(t, db, createBook(t, db, "Dom Casmurro", 1).ID.String(), user.ID.String(), time.Now().Add(-73*time.Hour))
+
+	service.uow = &beforeTx{UnitOfWork: service.uow, before: func() {
+		if _, err := db.Exec(`UPDATE loans SET is_returned = TRUE, return_date = due_date WHERE id = ?`, loan.ID.String()); err != nil {
+			t.Fatalf("erro ao devolver: %v", err)
+		}
+	}}
+	if count, err := service.AccrueOverdueFines(ctx); err != nil || count != 0 {
+		t.Errorf("AccrueOverdueFines = %d, %v, esperado 0", count, err)
+	}
+	if entries := count(t, db, "account_entries"); entries != 0 {
+		t.Errorf("%d lançamentos, esperado nenhum", entries)
+	}
+}
+
+// TestReturnAccruesFine confere que a devolução lança a multa do atraso,
+// limitada ao máximo por item da política
+func TestReturnAccruesFine(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	loans := newTestLoanService(db)
+	user := createUser(t, db, "bentinho@example.com")
+
+	tests := []struct {
+		title string
+		due   time.Duration
+		want  domain.Money
+	}{
+		{"Dom Casmurro", 24 * time.Hour, 0},
+		{"Helena", -49 * time.Hour, 200},
+		{"Iaiá Garcia", -30 * 24 * time.Hour, 2000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			loan := checkout(t, db, createBook(t, db, tt.title, 1).ID.String(), user.ID.String(), time.Now().Add(tt.due))
+			if _, err := loans.ReturnLoan(ctx, loan.ID.String()); err != nil {
+				t.Fatalf("erro inesperado %v", err)
+			}
+			var charged int64
+			if err := db.QueryRow(`SELECT COALESCE(SUM(amount), 0) FROM account_entries WHERE loan_id = ?`, loan.ID.String()).Scan(&charged); err != nil {
+				t.Fatal(err)
+			}
+			if domain.Money(charged) != tt.want {
+				t.Errorf("multa = %d, esperado %d", charged, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccountCredits(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	service := newTestFineService(db)
+	user := createUser(t, db, "bentinho@example.com")
+	userID := user.ID.String()
+	addAccountEntry(t, db, userID, 500)
+
+	tests := []struct {
+		name string
+		err  func() error
+		code string
+	}{
+		{"pagamento zero", func() error { _, err := service.RecordPayment(ctx, userID, 0, ""); return err }, "non_positive_amount"},
+		{"pagamento acima do saldo", func() error { _, err := service.RecordPayment(ctx, userID, 501, ""); return err }, "amount_exceeds_balance"},
+		{"abono sem motivo", func() error { _, err := service.WaiveFine(ctx, userID, "", 100, ""); return err }, "waiver_reason_required"},
+		{"ajuste zero", func() error { _, err := service.AdjustBalance(ctx, userID, 0, "correção"); return err }, "zero_amount"},
+		{"ajuste sem motivo", func() error { _, err := service.AdjustBalance(ctx, userID, 100, ""); return err }, "adjustment_reason_required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.err(); errorCode(err) != tt.code {
+				t.Errorf("erro = %v, esperado %s", err, tt.code)
+			}
+		})
+	}
+
+	if _, err := service.RecordPayment(ctx, userID, 300, "dinheiro"); err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if _, err := service.WaiveFine(ctx, userID, "", 200, "primeiro atraso"); err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	account, err := service.GetAccount(userID)
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if account.Balance != 0 || len(account.Entries) != 3 {
+		t.Errorf("saldo = %d com %d lançamentos, esperado 0 com 3", account.Balance, len(account.Entries))
+	}
+	if _, err := service.RecordPayment(ctx, userID, 1, ""); errorCode(err) != "amount_exceeds_balance" {
+		t.Errorf("pagamento sem dívida: erro = %v, esperado amount_exceeds_balance", err)
+	}
+}
@@ -0,0 +1,197 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"library-management/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ItemService implementa os casos de uso para exemplares
+type ItemService struct {
+	itemRepo domain.ItemRepository
+	bookRepo domain.BookRepository
+	uow      domain.UnitOfWork
+	policy   domain.CirculationPolicy
+}
+
+// NewItemService cria uma nova instância do ItemService
+func NewItemService(itemRepo domain.ItemRepository, bookRepo domain.BookRepository, uow domain.UnitOfWork, policy domain.CirculationPolicy) *ItemService {
+	return &ItemService{
+		itemRepo: itemRepo,
+		bookRepo: bookRepo,
+		uow:      uow,
+		policy:   policy,
+	}
+}
+
+// CreateItem cadastra um novo exemplar para um livro.
+// Se o livro tiver fila de reservas, o novo exemplar já fica separado para o primeiro da fila.
+func (s *ItemService) CreateItem(ctx context.Context, bookID, barcode, shelfLocation string) (*domain.Item, error) {
+	var item *domain.Item
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		book, err := repos.Books.GetByID(bookID)
+		if err != nil {
+			return err
+		}
+
+		item = newItem(book.ID, barcode, shelfLocation)
+		if err := checkBarcode(repos, item.Barcode); err != nil {
+			return err
+		}
+		if err := repos.Items.Create(item); err != nil {
+			return err
+		}
+		_, err = promoteNextHold(repos, item, item.CreatedAt, s.policy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// GetItemsByBook retorna todos os exemplares de um livro
+func (s *ItemService) GetItemsByBook(bookID string) ([]*domain.Item, error) {
+	if _, err := s.bookRepo.GetByID(bookID); err != nil {
+		return nil, err
+	}
+	return s.itemRepo.GetByBook(bookID)
+}
+
+// GetItemByID retorna um exemplar pelo ID
+func (s *ItemService) GetItemByID(id string) (*domain.Item, error) {
+	return s.loadBook(s.itemRepo.GetByID(id))
+}
+
+// GetItemByBarcode retorna um exemplar pelo código de barras
+func (s *ItemService) GetItemByBarcode(barcode string) (*domain.Item, error) {
+	return s.loadBook(s.itemRepo.GetByBarcode(barcode))
+}
+
+// UpdateItem atualiza código de barras, localização e status de um exemplar.
+// Os status "on_loan" e "on_hold" são controlados apenas por empréstimos e reservas.
+// O exemplar é lido e validado na mesma transação que o grava, para que um
+// empréstimo ou reserva concorrente não tenha o status sobrescrito.
+func (s *ItemService) UpdateItem(ctx context.Context, id, barcode, shelfLocation, status string) (*domain.Item, error) {
+	var item *domain.Item
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		var err error
+		item, err = repos.Items.GetByID(id)
+		if err != nil {
+			return err
+		}
+
+		if barcode != "" && barcode != item.Barcode {
+			if err := checkBarcode(repos, barcode); err != nil {
+				return err
+			}
+			item.Barcode = barcode
+		}
+		item.ShelfLocation = shelfLocation
+
+		if status != "" && domain.ItemStatus(status) != item.Status {
+			newStatus := domain.ItemStatus(status)
+			if !newStatus.IsValid() {
+				return domain.NewFieldError("status", "invalid_item_status", "status de exemplar inválido")
+			}
+			if isCirculationStatus(newStatus) || isCirculationStatus(item.Status) {
+				return domain.NewConflict("circulation_status", "o status de empréstimo ou reserva só pode ser alterado pela circulação")
+			}
+			item.Status = newStatus
+		}
+		item.UpdatedAt = time.Now()
+
+		if err := repos.Items.Update(item); err != nil {
+			return err
+		}
+		// Um exemplar que volta a ficar disponível atende a fila de reservas
+		_, err = promoteNextHold(repos, item, item.UpdatedAt, s.policy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// checkBarcode retorna ErrBarcodeTaken se o código de barras já pertencer a
+// outro exemplar
+func checkBarcode(repos domain.Repositories, barcode string) error {
+	_, err := repos.Items.GetByBarcode(barcode)
+	if err == nil {
+		return domain.ErrBarcodeTaken
+	}
+	if errors.Is(err, domain.ErrItemNotFound) {
+		return nil
+	}
+	return err
+}
+
+// isCirculationStatus indica se o status é controlado por empréstimos e reservas
+func isCirculationStatus(status domain.ItemStatus) bool {
+	return status == domain.ItemStatusOnLoan || status == domain.ItemStatusOnHold
+}
+
+// DeleteItem remove um exemplar que não esteja emprestado nem separado para
+// reserva. Um exemplar que já circulou continua no histórico de empréstimos e
+// reservas e não pode ser removido; ele deve ser marcado como retirado.
+func (s *ItemService) DeleteItem(ctx context.Context, id string) error {
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		item, err := repos.Items.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if item.Status == domain.ItemStatusOnHold {
+			return domain.NewConflict("delete_item_on_hold", "não é possível deletar um exemplar separado para reserva")
+		}
+
+		activeLoan, err := repos.Loans.GetActiveLoanByItem(id)
+		if err != nil {
+			return err
+		}
+		if activeLoan != nil {
+			return domain.NewConflict("delete_item_on_loan", "não é possível deletar um exemplar que está emprestado")
+		}
+
+		return repos.Items.Delete(id)
+	})
+	if errors.Is(err, domain.ErrReferenced) {
+		return domain.NewConflict("delete_item_has_history", "não é possível deletar um exemplar que já foi emprestado ou reservado; marque-o como retirado")
+	}
+	return err
+}
+
+// loadBook preenche o livro do exemplar retornado por uma busca
+func (s *ItemService) loadBook(item *domain.Item, err error) (*domain.Item, error) {
+	if err != nil {
+		return nil, err
+	}
+	if book, err := s.bookRepo.GetByIDWithDeleted(item.BookID.String()); err == nil {
+		item.Book = book
+	}
+	return item, nil
+}
+
+// newItem cria um exemplar disponível, gerando o código de barras se não informado
+func newItem(bookID uuid.UUID, barcode, shelfLocation string) *domain.Item {
+	now := time.Now()
+	item := &domain.Item{
+		ID:            uuid.New(),
+		BookID:        bookID,
+		Barcode:       strings.TrimSpace(barcode),
+		ShelfLocation: shelfLocation,
+		Status:        domain.ItemStatusAvailable,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if item.Barcode == "" {
+		item.Barcode = strings.ToUpper(strings.ReplaceAll(item.ID.String(), "-", "")[:12])
+	}
+	return item
+}
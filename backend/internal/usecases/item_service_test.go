@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"library-management/internal/domain"
+	"testing"
+)
+
+func TestUpdateItem(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	service := newTestItemService(db)
+	book := createBook(t, db, "Dom Casmurro", 0)
+
+	first, err := service.CreateItem(ctx, book.ID.String(), "DC-1", "A1")
+	if err != nil {
+		t.Fatalf("erro ao criar o exemplar: %v", err)
+	}
+	if _, err := service.CreateItem(ctx, book.ID.String(), "DC-1", "A1"); !errors.Is(err, domain.ErrBarcodeTaken) {
+		t.Errorf("código de barras repetido na criação: erro = %v, esperado %v", err, domain.ErrBarcodeTaken)
+	}
+	second, err := service.CreateItem(ctx, book.ID.String(), "DC-2", "A1")
+	if err != nil {
+		t.Fatalf("erro ao criar o exemplar: %v", err)
+	}
+
+	var domainErr *domain.Error
+	tests := []struct {
+		name    string
+		barcode string
+		status  string
+		code    string
+	}{
+		{"código de barras de outro exemplar", first.Barcode, "", "barcode_taken"},
+		{"status inválido", "", "missing", "invalid_item_status"},
+		{"status de empréstimo", "", string(domain.ItemStatusOnLoan), "circulation_status"},
+		{"status de reserva", "", string(domain.ItemStatusOnHold), "circulation_status"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.UpdateItem(ctx, second.ID.String(), tt.barcode, "B2", tt.status)
+			if !errors.As(err, &domainErr) || domainErr.Code != tt.code {
+				t.Errorf("erro = %v, esperado %s", err, tt.code)
+			}
+		})
+	}
+
+	updated, err := service.UpdateItem(ctx, second.ID.String(), "DC-3", "B2", string(domain.ItemStatusDamaged))
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if updated.Barcode != "DC-3" || updated.ShelfLocation != "B2" || updated.Status != domain.ItemStatusDamaged {
+		t.Errorf("exemplar = %+v, esperado DC-3 em B2 e danificado", updated)
+	}
+}
+
+// beforeTx executa before uma única vez, logo antes de abrir a próxima
+// transação, simulando uma requisição concorrente que chega entre a
+// chamada ao serviço e o início da transação
+type beforeTx struct {
+	domain.UnitOfWork
+	before func()
+}
+
+func (u *beforeTx) Do(fn func(repos domain.Repositories) error) error {
+	if before := u.before; before != nil {
+		u.before = nil
+		before()
+	}
+	return u.UnitOfWork.Do(fn)
+}
+
+// TestUpdateItemDuringCheckout confere que editar um exemplar enquanto ele é
+// emprestado não devolve o status disponível a um exemplar emprestado
+func TestUpdateItemDuringCheckout(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	book := createBook(t, db, "Dom Casmurro", 1)
+	user := createUser(t, db, "bentinho@example.com")
+
+	service := newTestItemService(db)
+	copies, err := service.GetItemsByBook(book.ID.String())
+	if err != nil || len(copies) != 1 {
+		t.Fatalf("exemplares = %v, %v, esperado 1", copies, err)
+	}
+	id := copies[0].ID.String()
+
+	service.uow = &beforeTx{UnitOfWork: service.uow, before: func() {
+		if _, err := newTestLoanService(db).CreateLoan(ctx, "", id, user.ID.String()); err != nil {
+			t.Fatalf("erro ao emprestar: %v", err)
+		}
+	}}
+	if _, err := service.UpdateItem(ctx, id, "", "B2", ""); err != nil {
+		t.Fatalf("erro ao editar o exemplar: %v", err)
+	}
+
+	got, err := service.GetItemByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != domain.ItemStatusOnLoan || got.ShelfLocation != "B2" {
+		t.Errorf("exemplar emprestado e editado = %s em %q, esperado on_loan em B2", got.Status, got.ShelfLocation)
+	}
+}
+
+func TestDeleteItem(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	items := newTestItemService(db)
+	book := createBook(t, db, "Dom Casmurro", 1)
+	user := createUser(t, db, "bentinho@example.com")
+
+	loan, err := newTestLoanService(db).CreateLoan(ctx, book.ID.String(), "", user.ID.String())
+	if err != nil {
+		t.Fatalf("erro ao emprestar: %v", err)
+	}
+
+	var domainErr *domain.Error
+	if err := items.DeleteItem(ctx, loan.ItemID.String()); !errors.As(err, &domainErr) || domainErr.Code != "delete_item_on_loan" {
+		t.Errorf("remoção do exemplar emprestado: erro = %v, esperado delete_item_on_loan", err)
+	}
+
+	fresh, err := items.CreateItem(ctx, book.ID.String(), "", "")
+	if err != nil {
+		t.Fatalf("erro ao criar o exemplar: %v", err)
+	}
+	if err := items.DeleteItem(ctx, fresh.ID.String()); err != nil {
+		t.Errorf("remoção do exemplar sem histórico: erro inesperado %v", err)
+	}
+	if _, err := items.GetItemByID(fresh.ID.String()); !errors.Is(err, domain.ErrItemNotFound) {
+		t.Errorf("exemplar removido: erro = %v, esperado %v", err, domain.ErrItemNotFound)
+	}
+}
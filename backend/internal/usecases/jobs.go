@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"library-management/internal/domain"
+	"strings"
+	"time"
+)
+
+// Nomes das tarefas agendadas
+const (
+	OverdueJobName      = "overdue"
+	HoldExpiryJobName   = "expire-holds"
+	NotificationJobName = "notifications"
+	PurgeJobName        = "purge-deleted"
+)
+
+// NewOverdueJob cria a tarefa de atrasos, normalmente noturna: marca os
+// empréstimos vencidos como atrasados, lança as multas acumuladas dos que
+// continuam abertos e coloca na fila os lembretes de vencimento e os avisos
+// de atraso. O envio fica com a tarefa de avisos; como cada aviso entra na
+// fila uma única vez, as duas tarefas podem gerá-los sem duplicá-los.
+func NewOverdueJob(schedule *domain.CronSchedule, loanService *LoanService, fineService *FineService, notificationService *NotificationService) domain.Job {
+	return domain.Job{
+		Name:        OverdueJobName,
+		Description: "Marca os empréstimos vencidos como atrasados, lança as multas acumuladas e gera os lembretes de vencimento e atraso",
+		Schedule:    schedule,
+		Run: func(ctx context.Context, now time.Time) (string, error) {
+			var summary []string
+			marked, err := loanService.MarkOverdueLoans(ctx, now)
+			if err != nil {
+				return "", fmt.Errorf("marcar atrasos: %w", err)
+			}
+			summary = append(summary, fmt.Sprintf("%d empréstimo(s) marcado(s) como atrasado(s)", marked))
+
+			fines, err := fineService.AccrueOverdueFines(ctx)
+			summary = append(summary, fmt.Sprintf("%d multa(s) lançada(s)", fines))
+			if err != nil {
+				return strings.Join(summary, ", "), fmt.Errorf("lançar multas: %w", err)
+			}
+
+			reminders, err := notificationService.EnqueueReminders(now)
+			summary = append(summary, fmt.Sprintf("%d aviso(s) na fila", reminders))
+			if err != nil {
+				return strings.Join(summary, ", "), fmt.Errorf("gerar lembretes: %w", err)
+			}
+			return strings.Join(summary, ", "), nil
+		},
+	}
+}
+
+// NewHoldExpiryJob cria a tarefa que encerra as reservas não retiradas no
+// prazo, repassando os exemplares para os próximos da fila
+func NewHoldExpiryJob(schedule *domain.CronSchedule, reservationService *ReservationService) domain.Job {
+	return domain.Job{
+		Name:        HoldExpiryJobName,
+		Description: "Expira as reservas não retiradas no prazo e repassa os exemplares ao próximo da fila",
+		Schedule:    schedule,
+		Run: func(ctx context.Context, now time.Time) (string, error) {
+			expired, err := reservationService.ExpireHolds(ctx)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d reserva(s) expirada(s)", expired), nil
+		},
+	}
+}
+
+// NewNotificationJob cria a tarefa de avisos aos leitores: coloca na fila os
+// lembretes de vencimento, os avisos de atraso, os comprovantes de empréstimo
+// e os avisos de reservas disponíveis, monta os resumos diários e envia os
+// pendentes
+func NewNotificationJob(schedule *domain.CronSchedule, notificationService *NotificationService) domain.Job {
+	return domain.Job{
+		Name:        NotificationJobName,
+		Description: "Gera os avisos de vencimento, atraso, empréstimo e reserva disponível, monta os resumos diários e envia os pendentes",
+		Schedule:    schedule,
+		Run: func(ctx context.Context, now time.Time) (string, error) {
+			var summary []string
+			reminders, err := notificationService.EnqueueReminders(now)
+			if err != nil {
+				return "", fmt.Errorf("gerar lembretes: %w", err)
+			}
+			notices, err := notificationService.EnqueueNotices(now)
+			if err != nil {
+				return "", fmt.Errorf("gerar avisos: %w", err)
+			}
+			digests, err := notificationService.EnqueueDigests(now)
+			if err != nil {
+				return "", fmt.Errorf("montar resumos: %w", err)
+			}
+			summary = append(summary, fmt.Sprintf("%d aviso(s) na fila", reminders+notices),
+				fmt.Sprintf("%d resumo(s)", digests))
+
+			sent, failed, err := notificationService.Dispatch(now)
+			summary = append(summary, fmt.Sprintf("%d enviado(s)", sent), fmt.Sprintf("%d com falha", failed))
+			if err != nil {
+				return strings.Join(summary, ", "), fmt.Errorf("enviar avisos: %w", err)
+			}
+			return strings.Join(summary, ", "), nil
+		},
+	}
+}
+
+// NewPurgeJob cria a tarefa que apaga de vez os livros e usuários removidos
+// há mais de retentionDays dias; os livros no histórico dos leitores e os
+// usuários com saldo na conta são mantidos
+func NewPurgeJob(schedule *domain.CronSchedule, retentionDays int, bookService *BookService, userService *UserService) domain.Job {
+	return domain.Job{
+		Name:        PurgeJobName,
+		Description: fmt.Sprintf("Apaga de vez os livros e usuários removidos há mais de %d dia(s)", retentionDays),
+		Schedule:    schedule,
+		Run: func(ctx context.Context, now time.Time) (string, error) {
+			cutoff := now.AddDate(0, 0, -retentionDays)
+			books, err := bookService.PurgeBooks(ctx, cutoff)
+			if err != nil {
+				return "", fmt.Errorf("expurgar livros: %w", err)
+			}
+			users, err := userService.PurgeUsers(ctx, cutoff)
+			summary := fmt.Sprintf("%d livro(s) e %d usuário(s) expurgado(s)", books, users)
+			if err != nil {
+				return summary, fmt.Errorf("expurgar usuários: %w", err)
+			}
+			return summary, nil
+		},
+	}
+}
@@ -1,210 +1,458 @@
 package usecases
 
 import (
-	"errors"
+	"context"
+	"fmt"
 	"library-management/internal/domain"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // LoanService implementa os casos de uso para empréstimos
 type LoanService struct {
 	loanRepo domain.LoanRepository
 	bookRepo domain.BookRepository
+	itemRepo domain.ItemRepository
 	userRepo domain.UserRepository
+	uow      domain.UnitOfWork
+	policy   domain.CirculationPolicy
 }
 
 // NewLoanService cria uma nova instância do LoanService
-func NewLoanService(loanRepo domain.LoanRepository, bookRepo domain.BookRepository, userRepo domain.UserRepository) *LoanService {
+func NewLoanService(loanRepo domain.LoanRepository, bookRepo domain.BookRepository, itemRepo domain.ItemRepository, userRepo domain.UserRepository, uow domain.UnitOfWork, policy domain.CirculationPolicy) *LoanService {
 	return &LoanService{
 		loanRepo: loanRepo,
 		bookRepo: bookRepo,
+		itemRepo: itemRepo,
 		userRepo: userRepo,
+		uow:      uow,
+		policy:   policy,
 	}
 }
 
-// CreateLoan cria um novo empréstimo
-func (s *LoanService) CreateLoan(bookID, userID string, daysToReturn int) (*domain.Loan, error) {
-	// Verificar se o livro existe
-	book, err := s.bookRepo.GetByID(bookID)
-	if err != nil {
-		return nil, errors.New("livro não encontrado")
-	}
+// CreateLoan cria um novo empréstimo de um exemplar.
+// Se itemID for informado (ex.: leitura do código de barras), esse exemplar é
+// emprestado; caso contrário, é escolhido um exemplar disponível de bookID.
+// O prazo e o limite de empréstimos simultâneos vêm da política de circulação
+// da categoria do usuário e do tipo de material do livro, e o usuário precisa
+// estar em dia (ver patronStanding); caso contrário é retornado um
+// *domain.BorrowingBlockedError com os motivos.
+// A verificação de disponibilidade, a criação do empréstimo e a atualização
+// do exemplar acontecem na mesma transação, de modo que duas requisições
+// concorrentes para o mesmo exemplar não podem ser aceitas ao mesmo tempo.
+func (s *LoanService) CreateLoan(ctx context.Context, bookID, itemID, userID string) (*domain.Loan, error) {
+	var loan *domain.Loan
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		// Verificar se o usuário existe
+		user, err := repos.Users.GetByID(userID)
+		if err != nil {
+			return err
+		}
 
-	// Verificar se o usuário existe
-	user, err := s.userRepo.GetByID(userID)
-	if err != nil {
-		return nil, errors.New("usuário não encontrado")
-	}
+		// Usuários com atrasos, dívidas acima do limite ou bloqueados não podem pegar livros
+		now := time.Now()
+		standing, err := patronStanding(repos, s.policy, user, now)
+		if err != nil {
+			return err
+		}
+		if !standing.CanBorrow {
+			return &domain.BorrowingBlockedError{Reasons: standing.Reasons}
+		}
 
-	// Verificar se o livro está disponível
-	if !book.IsAvailable {
-		return nil, errors.New("livro não está disponível")
-	}
+		item, hold, err := s.resolveItem(repos, bookID, itemID, user.ID.String(), now)
+		if err != nil {
+			return err
+		}
 
-	// Verificar se já existe um empréstimo ativo para este livro
-	activeLoan, _ := s.loanRepo.GetActiveLoanByBook(bookID)
-	if activeLoan != nil {
-		return nil, errors.New("livro já está emprestado")
-	}
+		// Verificar se já existe um empréstimo ativo para este exemplar
+		activeLoan, err := repos.Loans.GetActiveLoanByItem(item.ID.String())
+		if err != nil {
+			return err
+		}
+		if activeLoan != nil {
+			return domain.ErrItemOnLoan
+		}
 
-	// Definir dias padrão se não especificado
-	if daysToReturn <= 0 {
-		daysToReturn = 14 // 14 dias padrão
-	}
+		policy, err := policyFor(repos, s.policy, user.ID, item.BookID)
+		if err != nil {
+			return err
+		}
 
-	now := time.Now()
-	loan := &domain.Loan{
-		BookID:     book.ID,
-		UserID:     user.ID,
-		LoanDate:   now,
-		DueDate:    now.AddDate(0, 0, daysToReturn),
-		IsReturned: false,
-		CreatedAt:  now,
-		UpdatedAt:  now,
-	}
+		if policy.MaxLoans > 0 {
+			active, err := countActiveLoans(repos, user.ID.String())
+			if err != nil {
+				return err
+			}
+			if active >= policy.MaxLoans {
+				return domain.NewPolicyViolation("loan_limit_reached", fmt.Sprintf("limite de %d empréstimos simultâneos atingido", policy.MaxLoans)).
+					WithParams(map[string]interface{}{"max": policy.MaxLoans})
+			}
+		}
+
+		loan = &domain.Loan{
+			ItemID:     item.ID,
+			BookID:     item.BookID,
+			UserID:     user.ID,
+			LoanDate:   now,
+			DueDate:    now.AddDate(0, 0, policy.LoanPeriodDays),
+			IsReturned: false,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+
+		if err := repos.Loans.Create(loan); err != nil {
+			return err
+		}
+
+		// Atualizar disponibilidade do exemplar
+		item.Status = domain.ItemStatusOnLoan
+		item.UpdatedAt = now
+		if err := repos.Items.Update(item); err != nil {
+			return err
+		}
+
+		// A reserva do usuário (se havia) foi atendida
+		if hold != nil {
+			hold.Status = domain.ReservationStatusFulfilled
+			hold.UpdatedAt = now
+			if err := repos.Reservations.Update(hold); err != nil {
+				return err
+			}
+		}
 
-	err = s.loanRepo.Create(loan)
+		// Carregar dados relacionados, já com a contagem de exemplares atualizada
+		book, err := repos.Books.GetByID(item.BookID.String())
+		if err != nil {
+			return err
+		}
+		loan.Item = item
+		loan.Book = book
+		loan.User = user
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Atualizar disponibilidade do livro
-	book.IsAvailable = false
-	book.UpdatedAt = time.Now()
-	s.bookRepo.Update(book)
-
-	// Carregar dados relacionados
-	loan.Book = book
-	loan.User = user
-
 	return loan, nil
 }
 
-// ReturnLoan marca um empréstimo como devolvido
-func (s *LoanService) ReturnLoan(loanID string) (*domain.Loan, error) {
-	loan, err := s.loanRepo.GetByID(loanID)
-	if err != nil {
-		return nil, errors.New("empréstimo não encontrado")
+// resolveItem determina o exemplar a ser emprestado a partir de itemID ou bookID.
+// Os exemplares disponíveis atendem primeiro as reservas em espera, na ordem
+// da fila: quem não está entre elas só pega emprestado se sobrar exemplar. Quem
+// tem reserva separada leva o exemplar separado. A reserva atendida pelo
+// empréstimo, se houver, também é retornada.
+func (s *LoanService) resolveItem(repos domain.Repositories, bookID, itemID, userID string, now time.Time) (*domain.Item, *domain.Reservation, error) {
+	if itemID != "" {
+		item, err := repos.Items.GetByID(itemID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if bookID != "" && item.BookID.String() != bookID {
+			return nil, nil, domain.NewFieldError("item_id", "item_book_mismatch", "exemplar não pertence ao livro informado")
+		}
+		bookID = item.BookID.String()
+	}
+	// Verificar se o livro existe e não foi removido
+	if _, err := repos.Books.GetByID(bookID); err != nil {
+		return nil, nil, err
 	}
 
-	if loan.IsReturned {
-		return nil, errors.New("livro já foi devolvido")
+	// Reservas com prazo de retirada vencido liberam o exemplar antes da verificação
+	if err := expireBookHolds(repos, bookID, now, s.policy); err != nil {
+		return nil, nil, err
 	}
 
-	now := time.Now()
-	loan.ReturnDate = &now
-	loan.IsReturned = true
-	loan.UpdatedAt = now
+	queue, err := repos.Reservations.GetQueueByBook(bookID)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	err = s.loanRepo.Update(loan)
+	hold, err := s.claimHold(repos, bookID, userID, queue)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	// Atualizar disponibilidade do livro
-	book, err := s.bookRepo.GetByID(loan.BookID.String())
-	if err == nil {
-		book.IsAvailable = true
-		book.UpdatedAt = time.Now()
-		s.bookRepo.Update(book)
+	// O exemplar separado para a reserva é o que deve ser emprestado
+	if hold != nil && hold.Status == domain.ReservationStatusReady && hold.ItemID != nil {
+		if itemID != "" && hold.ItemID.String() != itemID {
+			return nil, nil, domain.NewPolicyViolation("wrong_hold_item", "empreste o exemplar separado para a reserva do usuário")
+		}
+		item, err := repos.Items.GetByID(hold.ItemID.String())
+		if err != nil {
+			return nil, nil, err
+		}
+		return item, hold, nil
 	}
 
-	return loan, nil
-}
+	if itemID != "" {
+		item, err := repos.Items.GetByID(itemID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if item.Status != domain.ItemStatusAvailable {
+			return nil, nil, domain.NewConflict("item_unavailable", "exemplar não está disponível")
+		}
+		return item, hold, nil
+	}
 
-// GetAllLoans retorna todos os empréstimos
-func (s *LoanService) GetAllLoans() ([]*domain.Loan, error) {
-	loans, err := s.loanRepo.GetAll()
+	// Verificar se o livro tem algum exemplar disponível
+	item, err := repos.Items.GetAvailableByBook(bookID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	// Carregar dados relacionados e atualizar status de atraso
-	for _, loan := range loans {
-		s.loadLoanRelations(loan)
-		s.updateOverdueStatus(loan)
+	if item == nil {
+		return nil, nil, domain.NewConflict("book_unavailable", "livro não está disponível")
 	}
 
-	return loans, nil
+	return item, hold, nil
 }
 
-// GetActiveLoans retorna todos os empréstimos ativos
-func (s *LoanService) GetActiveLoans() ([]*domain.Loan, error) {
-	loans, err := s.loanRepo.GetActiveLoans()
+// claimHold verifica se o usuário pode levar um exemplar do livro diante da
+// fila de reservas e retorna a reserva dele que o empréstimo atende, se houver.
+// As reservas separadas já têm exemplar próprio; os exemplares disponíveis
+// ficam para as primeiras reservas em espera, e só o que sobrar pode ser
+// emprestado a quem não está entre elas.
+func (s *LoanService) claimHold(repos domain.Repositories, bookID, userID string, queue []*domain.Reservation) (*domain.Reservation, error) {
+	if len(queue) == 0 {
+		return nil, nil
+	}
+
+	items, err := repos.Items.GetByBook(bookID)
 	if err != nil {
 		return nil, err
 	}
+	available := 0
+	for _, item := range items {
+		if item.Status == domain.ItemStatusAvailable {
+			available++
+		}
+	}
 
-	for _, loan := range loans {
-		s.loadLoanRelations(loan)
-		s.updateOverdueStatus(loan)
+	// waiting conta as reservas em espera à frente do usuário na fila
+	waiting := 0
+	for _, reservation := range queue {
+		if reservation.UserID.String() == userID {
+			if reservation.Status == domain.ReservationStatusReady || waiting < available {
+				return reservation, nil
+			}
+			break
+		}
+		if reservation.Status == domain.ReservationStatusWaiting {
+			waiting++
+		}
 	}
 
-	return loans, nil
+	if available <= waiting {
+		return nil, domain.NewPolicyViolation("reserved_for_other", "livro está reservado para outro usuário")
+	}
+	return nil, nil
 }
 
-// GetOverdueLoans retorna todos os empréstimos em atraso
-func (s *LoanService) GetOverdueLoans() ([]*domain.Loan, error) {
-	loans, err := s.loanRepo.GetOverdueLoans()
+// ReturnLoan marca um empréstimo como devolvido e libera o exemplar na mesma transação
+func (s *LoanService) ReturnLoan(ctx context.Context, loanID string) (*domain.Loan, error) {
+	var loan *domain.Loan
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		var err error
+		loan, err = repos.Loans.GetByID(loanID)
+		if err != nil {
+			return err
+		}
+
+		if loan.IsReturned {
+			return domain.ErrLoanReturned
+		}
+
+		now := time.Now()
+		loan.ReturnDate = &now
+		loan.IsReturned = true
+		loan.UpdatedAt = now
+
+		if err := repos.Loans.Update(loan); err != nil {
+			return err
+		}
+
+		// Lançar a multa por atraso, se houver, junto com a devolução
+		if _, err := accrueLoanFine(repos, loan, now, s.policy); err != nil {
+			return err
+		}
+
+		// Empréstimos antigos de livros já removidos podem não ter exemplar
+		if loan.ItemID == uuid.Nil {
+			return nil
+		}
+
+		// Atualizar disponibilidade do exemplar
+		item, err := repos.Items.GetByID(loan.ItemID.String())
+		if err != nil {
+			return err
+		}
+		if item.Status == domain.ItemStatusOnLoan {
+			item.Status = domain.ItemStatusAvailable
+			item.UpdatedAt = now
+			if err := repos.Items.Update(item); err != nil {
+				return err
+			}
+
+			// Se houver fila, o exemplar fica separado para o próximo da fila
+			if _, err := promoteNextHold(repos, item, now, s.policy); err != nil {
+				return err
+			}
+		}
+		loan.Item = item
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	for _, loan := range loans {
-		s.loadLoanRelations(loan)
-		s.updateOverdueStatus(loan)
-	}
-
-	return loans, nil
+	return loan, nil
 }
 
-// GetLoansByUser retorna todos os empréstimos de um usuário
-func (s *LoanService) GetLoansByUser(userID string) ([]*domain.Loan, error) {
-	loans, err := s.loanRepo.GetLoansByUser(userID)
+// RenewLoan estende o prazo de devolução de um empréstimo pelo período da
+// política de circulação do usuário e do livro.
+// A renovação é recusada se o limite de renovações foi atingido, se o
+// empréstimo está atrasado além da tolerância configurada ou se há reservas
+// para o livro.
+func (s *LoanService) RenewLoan(ctx context.Context, loanID string) (*domain.Loan, error) {
+	var loan *domain.Loan
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		var err error
+		loan, err = repos.Loans.GetByID(loanID)
+		if err != nil {
+			return err
+		}
+
+		if loan.IsReturned {
+			return domain.ErrLoanReturned
+		}
+
+		policy, err := policyFor(repos, s.policy, loan.UserID, loan.BookID)
+		if err != nil {
+			return err
+		}
+
+		if loan.RenewalCount >= policy.MaxRenewals {
+			return domain.NewPolicyViolation("renewal_limit_reached", fmt.Sprintf("limite de %d renovações atingido", policy.MaxRenewals)).
+				WithParams(map[string]interface{}{"max": policy.MaxRenewals})
+		}
+
+		now := time.Now()
+		if daysOverdue(loan, now) > policy.RenewalGraceDays {
+			return domain.NewPolicyViolation("renewal_overdue", "empréstimo está atrasado e não pode ser renovado")
+		}
+
+		queue, err := repos.Reservations.GetQueueByBook(loan.BookID.String())
+		if err != nil {
+			return err
+		}
+		if len(queue) > 0 {
+			return domain.NewPolicyViolation("renewal_has_holds", "há reservas para este livro e o empréstimo não pode ser renovado")
+		}
+
+		// O novo prazo conta a partir do prazo atual, ou de hoje se ele já passou
+		base := loan.DueDate
+		if now.After(base) {
+			base = now
+		}
+		loan.DueDate = base.AddDate(0, 0, policy.LoanPeriodDays)
+		loan.RenewalCount++
+		loan.IsOverdue = false
+		loan.UpdatedAt = now
+
+		return repos.Loans.Update(loan)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	for _, loan := range loans {
-		s.loadLoanRelations(loan)
-		s.updateOverdueStatus(loan)
-	}
-
-	return loans, nil
+	s.loadLoanRelations(loan)
+	return loan, nil
 }
 
-// GetLoansByBook retorna todos os empréstimos de um livro
-func (s *LoanService) GetLoansByBook(bookID string) ([]*domain.Loan, error) {
-	loans, err := s.loanRepo.GetLoansByBook(bookID)
+// ListLoans retorna uma página dos empréstimos que atendem aos filtros e o
+// total de empréstimos encontrados, com livro, exemplar e usuário carregados
+func (s *LoanService) ListLoans(query domain.LoanQuery) ([]*domain.Loan, int, error) {
+	if query.Status != "" && !query.Status.IsValid() {
+		return nil, 0, domain.NewFieldError("status", "invalid_loan_status", "status de empréstimo inválido")
+	}
+	if query.Now.IsZero() {
+		query.Now = time.Now()
+	}
+
+	loans, total, err := s.loanRepo.List(query)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	if loans == nil {
+		loans = []*domain.Loan{}
 	}
 
+	// Carregar dados relacionados e calcular o status de atraso
 	for _, loan := range loans {
 		s.loadLoanRelations(loan)
-		s.updateOverdueStatus(loan)
+		setOverdueStatus(loan, query.Now)
 	}
 
-	return loans, nil
+	return loans, total, nil
 }
 
 // loadLoanRelations carrega os dados relacionados do empréstimo
 func (s *LoanService) loadLoanRelations(loan *domain.Loan) {
-	if book, err := s.bookRepo.GetByID(loan.BookID.String()); err == nil {
+	if item, err := s.itemRepo.GetByID(loan.ItemID.String()); err == nil {
+		loan.Item = item
+	}
+	if book, err := s.bookRepo.GetByIDWithDeleted(loan.BookID.String()); err == nil {
 		loan.Book = book
 	}
-	if user, err := s.userRepo.GetByID(loan.UserID.String()); err == nil {
+	if user, err := s.userRepo.GetByIDWithDeleted(loan.UserID.String()); err == nil {
 		loan.User = user
 	}
 }
 
-// updateOverdueStatus atualiza o status de atraso do empréstimo
-func (s *LoanService) updateOverdueStatus(loan *domain.Loan) {
-	if !loan.IsReturned && time.Now().After(loan.DueDate) {
-		if !loan.IsOverdue {
-			loan.IsOverdue = true
-			loan.UpdatedAt = time.Now()
-			s.loanRepo.Update(loan)
+// setOverdueStatus marca como atrasado, só na resposta, o empréstimo vencido
+// em now que ainda não foi marcado no banco pela tarefa de atrasos (ver
+// MarkOverdueLoans); as consultas não gravam nada
+func setOverdueStatus(loan *domain.Loan, now time.Time) {
+	if !loan.IsReturned && now.After(loan.DueDate) {
+		loan.IsOverdue = true
+	}
+}
+
+// MarkOverdueLoans grava como atrasados os empréstimos vencidos ainda não
+// devolvidos e retorna quantos foram marcados. É executado pela tarefa
+// noturna de atrasos.
+func (s *LoanService) MarkOverdueLoans(ctx context.Context, now time.Time) (int, error) {
+	var marked []string
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		var err error
+		marked, err = repos.Loans.MarkOverdue(now)
+		return err
+	})
+	return len(marked), err
+}
+
+// countActiveLoans conta os empréstimos ainda não devolvidos de um usuário
+func countActiveLoans(repos domain.Repositories, userID string) (int, error) {
+	loans, err := repos.Loans.GetLoansByUser(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, loan := range loans {
+		if !loan.IsReturned {
+			count++
 		}
 	}
+	return count, nil
+}
+
+// daysOverdue retorna quantos dias completos o empréstimo está atrasado em relação a now
+func daysOverdue(loan *domain.Loan, now time.Time) int {
+	if !now.After(loan.DueDate) {
+		return 0
+	}
+	return int(now.Sub(loan.DueDate).Hours() / 24)
 }
@@ -0,0 +1,57 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"library-management/internal/domain"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentCheckout confere que, com vários pedidos simultâneos para o
+// único exemplar de um livro, só um empréstimo é aceito
+func TestConcurrentCheckout(t *testing.T) {
+	db := newTestDB(t)
+	service := newTestLoanService(db)
+	book := createBook(t, db, "Dom Casmurro", 1)
+
+	const patrons = 8
+	users := make([]*domain.User, patrons)
+	for i := range users {
+		users[i] = createUser(t, db, fmt.Sprintf("leitor%d@example.com", i))
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, patrons)
+	for i, user := range users {
+		wg.Add(1)
+		go func(i int, userID string) {
+			defer wg.Done()
+			_, errs[i] = service.CreateLoan(context.Background(), book.ID.String(), "", userID)
+		}(i, user.ID.String())
+	}
+	wg.Wait()
+
+	accepted := 0
+	for _, err := range errs {
+		var domainErr *domain.Error
+		switch {
+		case err == nil:
+			accepted++
+		case !errors.As(err, &domainErr) || domainErr.Kind != domain.KindConflict:
+			t.Errorf("erro = %v, esperado um conflito de exemplar indisponível", err)
+		}
+	}
+	if accepted != 1 {
+		t.Errorf("%d empréstimos aceitos, esperado 1", accepted)
+	}
+
+	var active int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM loans WHERE book_id = ? AND is_returned = FALSE`, book.ID.String()).Scan(&active); err != nil {
+		t.Fatal(err)
+	}
+	if active != 1 {
+		t.Errorf("%d empréstimos ativos no banco, esperado 1", active)
+	}
+}
@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"library-management/internal/domain"
+	"library-management/internal/infrastructure/database"
+	"library-management/internal/infrastructure/transfer"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newTestDB abre um banco novo, com todas as migrações, num diretório
+// temporário. Sem o FTS5 (tag sqlite_fts5) as migrações não rodam, e o teste
+// é ignorado com o motivo.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := database.InitDB(database.DefaultConfig(filepath.Join(t.TempDir(), "library.db")))
+	if errors.Is(err, database.ErrFTS5Unavailable) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("erro ao abrir o banco: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// newTestBookService cria um BookService sobre o banco db
+func newTestBookService(db *sql.DB) *BookService {
+	return NewBookService(database.NewBookRepository(db), database.NewLoanRepository(db), database.NewUnitOfWork(db), nil)
+}
+
+// newTestUserService cria um UserService sobre o banco db
+func newTestUserService(db *sql.DB) *UserService {
+	return NewUserService(database.NewUserRepository(db), database.NewUnitOfWork(db))
+}
+
+// addAccountEntry lança um valor na conta do usuário diretamente no banco
+func addAccountEntry(t *testing.T, db *sql.DB, userID string, amount domain.Money) {
+	t.Helper()
+	_, err := db.Exec(`INSERT INTO account_entries (id, user_id, type, amount) VALUES (?, ?, ?, ?)`,
+		uuid.NewString(), userID, domain.AccountEntryAdjustment, int64(amount))
+	if err != nil {
+		t.Fatalf("erro ao lançar na conta: %v", err)
+	}
+}
+
+// newTestLoanService cria um LoanService sobre o banco db com a política padrão
+func newTestLoanService(db *sql.DB) *LoanService {
+	return NewLoanService(database.NewLoanRepository(db), database.NewBookRepository(db), database.NewItemRepository(db),
+		database.NewUserRepository(db), database.NewUnitOfWork(db), domain.DefaultCirculationPolicy())
+}
+
+// createBook cadastra um livro com copies exemplares
+func createBook(t *testing.T, db *sql.DB, title string, copies int) *domain.Book {
+	t.Helper()
+	book, err := newTestBookService(db).CreateBook(context.Background(), title, "Machado de Assis", 1899, "", "", "", nil, "", copies)
+	if err != nil {
+		t.Fatalf("erro ao criar o livro: %v", err)
+	}
+	return book
+}
+
+// createUser cadastra um leitor com o email informado
+func createUser(t *testing.T, db *sql.DB, email string) *domain.User {
+	t.Helper()
+	user, err := newTestUserService(db).CreateUser(context.Background(), "Leitor", email, "", "", "")
+	if err != nil {
+		t.Fatalf("erro ao criar o usuário: %v", err)
+	}
+	return user
+}
+
+// newTestTransferService cria um TransferService sobre o banco db com os
+// formatos usados pelo servidor
+func newTestTransferService(db *sql.DB) *TransferService {
+	return NewTransferService(database.NewBookRepository(db), database.NewUserRepository(db), database.NewUnitOfWork(db),
+		transfer.CatalogFormats(), transfer.Formats())
+}
+
+// count retorna o número de linhas da tabela
+func count(t *testing.T, db *sql.DB, table string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&n); err != nil {
+		t.Fatalf("erro ao contar %s: %v", table, err)
+	}
+	return n
+}
+
+// newTestItemService cria um ItemService sobre o banco db com a política padrão
+func newTestItemService(db *sql.DB) *ItemService {
+	return NewItemService(database.NewItemRepository(db), database.NewBookRepository(db), database.NewUnitOfWork(db),
+		domain.DefaultCirculationPolicy())
+}
+
+// newTestFineService cria um FineService sobre o banco db com a política padrão
+func newTestFineService(db *sql.DB) *FineService {
+	return NewFineService(database.NewAccountRepository(db), database.NewUserRepository(db), database.NewUnitOfWork(db),
+		domain.DefaultCirculationPolicy())
+}
+
+// newTestStandingService cria um StandingService sobre o banco db com a política padrão
+func newTestStandingService(db *sql.DB) *StandingService {
+	return NewStandingService(database.NewBlockRepository(db), database.NewUserRepository(db), database.NewLoanRepository(db),
+		database.NewAccountRepository(db), database.NewPolicyRepository(db), database.NewUnitOfWork(db), domain.DefaultCirculationPolicy())
+}
+
+// checkout empresta um exemplar do livro ao usuário com o vencimento em due
+func checkout(t *testing.T, db *sql.DB, bookID, userID string, due time.Time) *domain.Loan {
+	t.Helper()
+	loan, err := newTestLoanService(db).CreateLoan(context.Background(), bookID, "", userID)
+	if err != nil {
+		t.Fatalf("erro ao emprestar: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE loans SET due_date = ? WHERE id = ?`, due, loan.ID.String()); err != nil {
+		t.Fatalf("erro ao mudar o vencimento: %v", err)
+	}
+	loan.DueDate = due
+	return loan
+}
+
+// newTestReservationService cria um ReservationService sobre o banco db com a política padrão
+func newTestReservationService(db *sql.DB) *ReservationService {
+	return NewReservationService(database.NewReservationRepository(db), database.NewBookRepository(db), database.NewUserRepository(db),
+		database.NewUnitOfWork(db), domain.DefaultCirculationPolicy())
+}
+
+// errorCode retorna o código do *domain.Error em err, ou "" se não houver
+func errorCode(err error) string {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+	return ""
+}
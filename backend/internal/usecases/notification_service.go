@@ -0,0 +1,492 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"library-management/internal/domain"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// dispatchBatch é quantos avisos Dispatch reserva de cada vez
+const dispatchBatch = 50
+
+// receiptWindow é até quanto tempo depois do empréstimo o comprovante ainda
+// entra na fila (ex.: se o servidor estava parado)
+const receiptWindow = 24 * time.Hour
+
+// NotificationConfig reúne as configurações dos avisos aos leitores
+type NotificationConfig struct {
+	// Language é o idioma das mensagens ("pt-BR" ou "en")
+	Language string
+	// DueSoonDays é com quantos dias de antecedência o leitor é lembrado do vencimento
+	DueSoonDays int
+	// MaxAttempts é quantas vezes o envio é tentado antes de o aviso falhar
+	MaxAttempts int
+	// RetryDelay é a espera antes da segunda tentativa; ela dobra a cada nova falha
+	RetryDelay time.Duration
+	// DigestHour é a hora do dia, no fuso do servidor, a partir da qual os
+	// resumos diários são montados
+	DigestHour int
+}
+
+// NotificationService gera os avisos aos leitores a partir dos empréstimos e
+// das reservas e os envia pela fila (outbox), respeitando as preferências de
+// cada leitor. Cada aviso é gravado na fila uma única vez por canal, e o envio
+// acontece depois, de modo que os avisos sobrevivem a um reinício e não são
+// enviados em dobro.
+type NotificationService struct {
+	loanRepo         domain.LoanRepository
+	reservationRepo  domain.ReservationRepository
+	userRepo         domain.UserRepository
+	bookRepo         domain.BookRepository
+	notificationRepo domain.NotificationRepository
+	preferenceRepo   domain.NotificationPreferenceRepository
+	uow              domain.UnitOfWork
+	renderer         domain.MessageRenderer
+	notifiers        map[domain.NotificationChannel]domain.Notifier
+	config           NotificationConfig
+}
+
+// NewNotificationService cria uma nova instância do NotificationService.
+// notifiers tem o envio de cada canal; avisos de um canal sem envio falham.
+func NewNotificationService(loanRepo domain.LoanRepository, reservationRepo domain.ReservationRepository, userRepo domain.UserRepository, bookRepo domain.BookRepository, notificationRepo domain.NotificationRepository, preferenceRepo domain.NotificationPreferenceRepository, uow domain.UnitOfWork, renderer domain.MessageRenderer, notifiers map[domain.NotificationChannel]domain.Notifier, config NotificationConfig) *NotificationService {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	return &NotificationService{
+		loanRepo:         loanRepo,
+		reservationRepo:  reservationRepo,
+		userRepo:         userRepo,
+		bookRepo:         bookRepo,
+		notificationRepo: notificationRepo,
+		preferenceRepo:   preferenceRepo,
+		uow:              uow,
+		renderer:         renderer,
+		notifiers:        notifiers,
+		config:           config,
+	}
+}
+
+// GetPreferences retorna as preferências de aviso do usuário; quem nunca as
+// alterou recebe as preferências padrão
+func (s *NotificationService) GetPreferences(userID string) (*domain.NotificationPreferences, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.preferencesFor(user.ID)
+}
+
+// UpdatePreferences substitui as preferências de aviso do usuário. O canal
+// sms exige que o usuário tenha telefone cadastrado.
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID string, preferences *domain.NotificationPreferences) (*domain.NotificationPreferences, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := preferences.Validate(); err != nil {
+		return nil, err
+	}
+	for _, channel := range preferences.Channels {
+		if channel == domain.ChannelSMS && user.Phone == "" {
+			return nil, domain.NewFieldError("channels", "phone_required", "cadastre um telefone para receber avisos por SMS")
+		}
+	}
+
+	now := time.Now()
+	preferences.UserID = user.ID
+	preferences.UpdatedAt = &now
+	err = inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		return repos.Preferences.Save(preferences)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// preferencesFor retorna as preferências gravadas do usuário ou as padrão
+func (s *NotificationService) preferencesFor(userID uuid.UUID) (*domain.NotificationPreferences, error) {
+	preferences, err := s.preferenceRepo.Get(userID.String())
+	if err != nil {
+		return nil, err
+	}
+	if preferences == nil {
+		return domain.DefaultNotificationPreferences(userID), nil
+	}
+	return preferences, nil
+}
+
+// EnqueueReminders coloca na fila os lembretes de vencimento dos empréstimos
+// que vencem nos próximos DueSoonDays dias e os avisos de atraso dos
+// vencidos. Cada empréstimo recebe um lembrete e um aviso de atraso por data
+// de vencimento (uma renovação gera novos avisos). Retorna quantos avisos
+// entraram na fila.
+func (s *NotificationService) EnqueueReminders(now time.Time) (int, error) {
+	count := 0
+	if s.config.DueSoonDays > 0 {
+		dueTo := now.AddDate(0, 0, s.config.DueSoonDays)
+		query := domain.LoanQuery{Status: domain.LoanStatusActive, DueFrom: &now, DueTo: &dueTo, Now: now}
+		err := s.eachLoan(query, func(loan *domain.Loan) error {
+			key := fmt.Sprintf("%s:%s:%s", domain.NotificationDueSoon, loan.ID, loan.DueDate.UTC().Format(time.RFC3339))
+			added, err := s.enqueueLoanNotice(domain.NotificationDueSoon, key, loan, daysUntil(loan.DueDate, now), now)
+			count += added
+			return err
+		})
+		if err != nil {
+			return count, err
+		}
+	}
+
+	query := domain.LoanQuery{Status: domain.LoanStatusOverdue, Now: now}
+	err := s.eachLoan(query, func(loan *domain.Loan) error {
+		key := fmt.Sprintf("%s:%s:%s", domain.NotificationOverdue, loan.ID, loan.DueDate.UTC().Format(time.RFC3339))
+		added, err := s.enqueueLoanNotice(domain.NotificationOverdue, key, loan, daysOverdue(loan, now), now)
+		count += added
+		return err
+	})
+	return count, err
+}
+
+// EnqueueNotices coloca na fila os comprovantes dos empréstimos feitos nas
+// últimas 24 horas e os avisos das reservas separadas para retirada.
+// Retorna quantos avisos entraram na fila.
+func (s *NotificationService) EnqueueNotices(now time.Time) (int, error) {
+	count := 0
+	loanedFrom := now.Add(-receiptWindow)
+	query := domain.LoanQuery{Status: domain.LoanStatusActive, LoanedFrom: &loanedFrom, Now: now}
+	err := s.eachLoan(query, func(loan *domain.Loan) error {
+		key := fmt.Sprintf("%s:%s", domain.NotificationLoanReceipt, loan.ID)
+		added, err := s.enqueueLoanNotice(domain.NotificationLoanReceipt, key, loan, 0, now)
+		count += added
+		return err
+	})
+	if err != nil {
+		return count, err
+	}
+
+	reservations, err := s.reservationRepo.GetReady()
+	if err != nil {
+		return count, err
+	}
+	for _, reservation := range reservations {
+		book, err := s.bookRepo.GetByID(reservation.BookID.String())
+		if err != nil {
+			return count, err
+		}
+		data := domain.NoticeData{Title: book.Title, Author: book.Author}
+		if reservation.ExpiresAt != nil {
+			data.PickupBy = *reservation.ExpiresAt
+		}
+		key := fmt.Sprintf("%s:%s", domain.NotificationHoldReady, reservation.ID)
+		added, err := s.enqueue(domain.NotificationHoldReady, key, reservation.UserID.String(), data, now)
+		count += added
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// eachLoan chama fn para cada empréstimo da consulta, uma página por vez
+func (s *NotificationService) eachLoan(query domain.LoanQuery, fn func(loan *domain.Loan) error) error {
+	query.Page = domain.Page{Limit: domain.MaxPageLimit}
+	for {
+		loans, total, err := s.loanRepo.List(query)
+		if err != nil {
+			return err
+		}
+		for _, loan := range loans {
+			if err := fn(loan); err != nil {
+				return err
+			}
+		}
+		query.Page.Offset += len(loans)
+		if len(loans) == 0 || query.Page.Offset >= total {
+			return nil
+		}
+	}
+}
+
+// enqueueLoanNotice coloca na fila um aviso sobre o empréstimo
+func (s *NotificationService) enqueueLoanNotice(kind domain.NotificationKind, key string, loan *domain.Loan, days int, now time.Time) (int, error) {
+	book, err := s.bookRepo.GetByID(loan.BookID.String())
+	if err != nil {
+		return 0, err
+	}
+	data := domain.NoticeData{
+		Title:    book.Title,
+		Author:   book.Author,
+		LoanDate: loan.LoanDate,
+		DueDate:  loan.DueDate,
+		Days:     days,
+	}
+	return s.enqueue(kind, key, loan.UserID.String(), data, now)
+}
+
+// enqueue gera o texto do aviso e o coloca na fila em cada canal escolhido
+// pelo usuário, a menos que ele não queira esse aviso, que o mesmo aviso
+// (key) já esteja na fila do canal ou que falte o destinatário do canal.
+// Quem optou pelo resumo diário tem o aviso guardado para o resumo. Retorna
+// quantos avisos entraram na fila.
+func (s *NotificationService) enqueue(kind domain.NotificationKind, key, userID string, data domain.NoticeData, now time.Time) (int, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return 0, err
+	}
+	preferences, err := s.preferencesFor(user.ID)
+	if err != nil {
+		return 0, err
+	}
+	if !preferences.Wants(kind) {
+		return 0, nil
+	}
+
+	data.Name = user.Name
+	notice, err := s.renderer.Render(kind, s.config.Language, data)
+	if err != nil {
+		return 0, err
+	}
+
+	status := domain.NotificationPending
+	if preferences.Digest {
+		status = domain.NotificationHeld
+	}
+
+	count := 0
+	for _, channel := range preferences.Channels {
+		recipient := recipientFor(channel, user, preferences)
+		if recipient == "" {
+			continue
+		}
+		notification := &domain.Notification{
+			UserID:        user.ID,
+			Kind:          kind,
+			Channel:       channel,
+			DedupKey:      key,
+			Recipient:     recipient,
+			Subject:       notice.Subject,
+			Body:          notice.Body,
+			Summary:       notice.Summary,
+			Status:        status,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+		err := s.notificationRepo.Enqueue(notification)
+		if errors.Is(err, domain.ErrDuplicate) {
+			continue
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// recipientFor retorna o destinatário do usuário no canal, ou "" se ele não tiver
+func recipientFor(channel domain.NotificationChannel, user *domain.User, preferences *domain.NotificationPreferences) string {
+	switch channel {
+	case domain.ChannelEmail:
+		return user.Email
+	case domain.ChannelSMS:
+		return user.Phone
+	case domain.ChannelWebhook:
+		return preferences.WebhookURL
+	}
+	return ""
+}
+
+// EnqueueDigests monta os resumos diários: a partir de DigestHour, os avisos
+// guardados de cada usuário são reunidos numa única mensagem por canal. Cada
+// usuário recebe no máximo um resumo por dia em cada canal; o que chegar
+// depois do resumo do dia fica para o dia seguinte. Retorna quantos resumos
+// entraram na fila.
+func (s *NotificationService) EnqueueDigests(now time.Time) (int, error) {
+	if now.Hour() < s.config.DigestHour {
+		return 0, nil
+	}
+
+	held, err := s.notificationRepo.GetHeld()
+	if err != nil {
+		return 0, err
+	}
+
+	type digestKey struct {
+		userID  uuid.UUID
+		channel domain.NotificationChannel
+	}
+	var keys []digestKey
+	groups := make(map[digestKey][]*domain.Notification)
+	for _, notification := range held {
+		key := digestKey{notification.UserID, notification.Channel}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], notification)
+	}
+
+	count := 0
+	for _, key := range keys {
+		added, err := s.enqueueDigest(groups[key], now)
+		count += added
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// enqueueDigest coloca na fila o resumo dos avisos guardados de um usuário
+// num canal e os marca como resumidos, tudo na mesma transação
+func (s *NotificationService) enqueueDigest(items []*domain.Notification, now time.Time) (int, error) {
+	first, last := items[0], items[len(items)-1]
+	user, err := s.userRepo.GetByID(first.UserID.String())
+	if err != nil {
+		return 0, err
+	}
+
+	data := domain.NoticeData{Name: user.Name}
+	for _, item := range items {
+		data.Items = append(data.Items, item.Summary)
+	}
+	notice, err := s.renderer.Render(domain.NotificationDigest, s.config.Language, data)
+	if err != nil {
+		return 0, err
+	}
+
+	digest := &domain.Notification{
+		UserID:        user.ID,
+		Kind:          domain.NotificationDigest,
+		Channel:       first.Channel,
+		DedupKey:      fmt.Sprintf("%s:%s:%s", domain.NotificationDigest, user.ID, now.Format("2006-01-02")),
+		Recipient:     last.Recipient,
+		Subject:       notice.Subject,
+		Body:          notice.Body,
+		Summary:       notice.Summary,
+		Status:        domain.NotificationPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	err = s.uow.Do(func(repos domain.Repositories) error {
+		if err := repos.Notifications.Enqueue(digest); err != nil {
+			return err
+		}
+		for _, item := range items {
+			item.Status = domain.NotificationDigested
+			if err := repos.Notifications.Update(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, domain.ErrDuplicate) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// Dispatch envia os avisos pendentes cujo horário chegou e retorna quantos
+// foram enviados e quantos falharam de vez. Um envio que falha é tentado de
+// novo mais tarde, até MaxAttempts vezes, e um aviso que cai no horário de
+// silêncio do usuário é adiado para o fim dele.
+//
+// Antes de enviar, os avisos que ficaram em envio numa execução
+// interrompida são dados como falha, sem reenvio: não há como saber se a
+// mensagem chegou a sair. Por isso Dispatch não deve rodar em dois
+// processos ao mesmo tempo (a tarefa de avisos garante isso).
+func (s *NotificationService) Dispatch(now time.Time) (sent, failed int, err error) {
+	interrupted, err := s.notificationRepo.FailSending(now)
+	if err != nil {
+		return 0, 0, err
+	}
+	if interrupted > 0 {
+		log.Printf("%d aviso(s) interrompido(s) durante o envio marcado(s) como falha", interrupted)
+	}
+
+	quietHours := make(map[uuid.UUID]*domain.QuietHours)
+	for {
+		notifications, err := s.notificationRepo.ClaimPending(now, dispatchBatch)
+		if err != nil {
+			return sent, failed, err
+		}
+		for _, notification := range notifications {
+			quiet, ok := quietHours[notification.UserID]
+			if !ok {
+				preferences, err := s.preferencesFor(notification.UserID)
+				if err != nil {
+					return sent, failed, err
+				}
+				quiet = preferences.QuietHours
+				quietHours[notification.UserID] = quiet
+			}
+
+			if quiet != nil && quiet.Contains(now) {
+				notification.Status = domain.NotificationPending
+				notification.NextAttemptAt = quiet.EndAfter(now)
+			} else {
+				s.send(notification, now)
+			}
+			if err := s.notificationRepo.Update(notification); err != nil {
+				return sent, failed, err
+			}
+			switch notification.Status {
+			case domain.NotificationSent:
+				sent++
+			case domain.NotificationFailed:
+				failed++
+			}
+		}
+		if len(notifications) < dispatchBatch {
+			return sent, failed, nil
+		}
+	}
+}
+
+// send envia o aviso e atualiza a sua situação: enviado, pendente para
+// nova tentativa ou falha depois de MaxAttempts tentativas
+func (s *NotificationService) send(notification *domain.Notification, now time.Time) {
+	notification.Attempts++
+	err := fmt.Errorf("canal %q não configurado", notification.Channel)
+	if notifier, ok := s.notifiers[notification.Channel]; ok {
+		err = notifier.Send(domain.Message{
+			ID:      notification.ID.String(),
+			Kind:    notification.Kind,
+			To:      notification.Recipient,
+			Subject: notification.Subject,
+			Body:    notification.Body,
+			Summary: notification.Summary,
+		})
+	}
+	if err == nil {
+		sentAt := time.Now()
+		notification.Status = domain.NotificationSent
+		notification.SentAt = &sentAt
+		notification.LastError = ""
+		return
+	}
+
+	notification.LastError = err.Error()
+	if notification.Attempts >= s.config.MaxAttempts {
+		notification.Status = domain.NotificationFailed
+		return
+	}
+	notification.Status = domain.NotificationPending
+	notification.NextAttemptAt = now.Add(s.config.RetryDelay << (notification.Attempts - 1))
+}
+
+// daysUntil retorna quantos dias de calendário faltam de now até t
+func daysUntil(t, now time.Time) int {
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	y, m, d = t.In(now.Location()).Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	return int(day.Sub(today).Hours()+12) / 24
+}
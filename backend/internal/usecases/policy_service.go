@@ -0,0 +1,143 @@
+package usecases
+
+import (
+	"context"
+	"library-management/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PolicyService implementa os casos de uso para as políticas de circulação
+type PolicyService struct {
+	policyRepo domain.PolicyRepository
+	uow        domain.UnitOfWork
+}
+
+// NewPolicyService cria uma nova instância do PolicyService
+func NewPolicyService(policyRepo domain.PolicyRepository, uow domain.UnitOfWork) *PolicyService {
+	return &PolicyService{policyRepo: policyRepo, uow: uow}
+}
+
+// CreatePolicy cadastra uma política para uma categoria de usuário e, opcionalmente, um tipo de material
+func (s *PolicyService) CreatePolicy(ctx context.Context, policy *domain.CirculationPolicy) (*domain.CirculationPolicy, error) {
+	if err := s.validate(policy, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	policy.CreatedAt = time.Now()
+	policy.UpdatedAt = policy.CreatedAt
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		return repos.Policies.Create(policy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// GetAllPolicies retorna todas as políticas cadastradas
+func (s *PolicyService) GetAllPolicies() ([]*domain.CirculationPolicy, error) {
+	policies, err := s.policyRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	if policies == nil {
+		policies = []*domain.CirculationPolicy{}
+	}
+	return policies, nil
+}
+
+// GetPolicyByID retorna uma política pelo ID
+func (s *PolicyService) GetPolicyByID(id string) (*domain.CirculationPolicy, error) {
+	return s.policyRepo.GetByID(id)
+}
+
+// UpdatePolicy substitui as regras de uma política existente
+func (s *PolicyService) UpdatePolicy(ctx context.Context, id string, changes *domain.CirculationPolicy) (*domain.CirculationPolicy, error) {
+	policy, err := s.policyRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validate(changes, policy.ID); err != nil {
+		return nil, err
+	}
+
+	changes.ID = policy.ID
+	changes.CreatedAt = policy.CreatedAt
+	changes.UpdatedAt = time.Now()
+	err = inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		return repos.Policies.Update(changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// DeletePolicy remove uma política; os usuários da categoria passam a usar a política padrão
+func (s *PolicyService) DeletePolicy(ctx context.Context, id string) error {
+	return inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		return repos.Policies.Delete(id)
+	})
+}
+
+// validate verifica as regras da política e se já existe outra para a mesma
+// categoria e tipo de material (ignorando a própria política, currentID)
+func (s *PolicyService) validate(policy *domain.CirculationPolicy, currentID uuid.UUID) error {
+	if !policy.PatronCategory.IsValid() {
+		return domain.NewFieldError("category", "invalid_category", "categoria de usuário inválida")
+	}
+	if policy.LoanPeriodDays <= 0 {
+		return domain.NewFieldError("loan_period_days", "invalid_loan_period", "prazo de empréstimo deve ser maior que zero")
+	}
+	if policy.MaxLoans < 0 || policy.MaxRenewals < 0 || policy.RenewalGraceDays < 0 || policy.HoldPickupDays < 0 {
+		return domain.NewValidation("negative_policy_limits", "limites da política não podem ser negativos")
+	}
+	if policy.FineDailyRate < 0 || policy.FineMaxPerItem < 0 || policy.MaxDebt < 0 {
+		return domain.NewValidation("negative_policy_amounts", "valores de multa e de dívida máxima não podem ser negativos")
+	}
+
+	policies, err := s.policyRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, existing := range policies {
+		if existing.ID != currentID && existing.PatronCategory == policy.PatronCategory && existing.MaterialType == policy.MaterialType {
+			return domain.ErrPolicyExists
+		}
+	}
+
+	return nil
+}
+
+// policyFor retorna a política que se aplica ao usuário e ao livro, conforme a
+// categoria do usuário e o tipo de material do livro (bookID pode ser uuid.Nil
+// para considerar apenas a categoria). Usuários e livros removidos mantêm a
+// política da categoria e do material. Se nenhuma política cadastrada se
+// aplicar, retorna fallback.
+func policyFor(repos domain.Repositories, fallback domain.CirculationPolicy, userID, bookID uuid.UUID) (domain.CirculationPolicy, error) {
+	category := domain.DefaultPatronCategory
+	if user, err := repos.Users.GetByIDWithDeleted(userID.String()); err == nil {
+		category = user.Category
+	}
+
+	materialType := domain.DefaultMaterialType
+	if bookID != uuid.Nil {
+		if book, err := repos.Books.GetByIDWithDeleted(bookID.String()); err == nil {
+			materialType = book.MaterialType
+		}
+	}
+
+	policy, err := repos.Policies.Find(category, materialType)
+	if err != nil {
+		return fallback, err
+	}
+	if policy == nil {
+		return fallback, nil
+	}
+	return *policy, nil
+}
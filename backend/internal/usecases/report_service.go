@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"io"
+	"library-management/internal/domain"
+	"time"
+)
+
+// reportKind descreve como o filtro de um relatório é completado
+type reportKind struct {
+	name string
+	// periodic indica que o relatório é agrupado por período
+	periodic bool
+	// defaultLimit é o número de linhas quando o cliente não informa limit
+	// (zero = todas)
+	defaultLimit int
+}
+
+// Relatórios disponíveis
+var (
+	reportLoansPerPeriod = reportKind{name: "loans-per-period", periodic: true}
+	reportTopTitles      = reportKind{name: "top-titles", defaultLimit: domain.DefaultReportLimit}
+	reportTopAuthors     = reportKind{name: "top-authors", defaultLimit: domain.DefaultReportLimit}
+	reportActivePatrons  = reportKind{name: "active-patrons", defaultLimit: domain.DefaultReportLimit}
+	reportOverdueRate    = reportKind{name: "overdue-rate", periodic: true}
+	reportLoanDuration   = reportKind{name: "loan-duration", periodic: true}
+	reportNeverBorrowed  = reportKind{name: "never-borrowed"}
+)
+
+// ReportService implementa os relatórios e estatísticas de circulação,
+// calculados pelo banco. Além do JSON, os relatórios podem ser gravados nos
+// formatos de arquivo informados (como o CSV).
+type ReportService struct {
+	reportRepo domain.ReportRepository
+	formats    map[string]domain.RecordFormat
+}
+
+// NewReportService cria uma nova instância do ReportService
+func NewReportService(reportRepo domain.ReportRepository, formats map[string]domain.RecordFormat) *ReportService {
+	return &ReportService{
+		reportRepo: reportRepo,
+		formats:    formats,
+	}
+}
+
+// Format retorna o formato de arquivo com o nome informado, ou ErrUnknownFormat
+func (s *ReportService) Format(name string) (domain.RecordFormat, error) {
+	return findFormat(s.formats, name)
+}
+
+// LoansPerPeriod conta os empréstimos, os leitores distintos e as devoluções por período
+func (s *ReportService) LoansPerPeriod(filter domain.ReportFilter) (*domain.Report, error) {
+	return s.run(reportLoansPerPeriod, filter, s.reportRepo.LoansPerPeriod)
+}
+
+// TopTitles retorna os livros mais emprestados
+func (s *ReportService) TopTitles(filter domain.ReportFilter) (*domain.Report, error) {
+	return s.run(reportTopTitles, filter, s.reportRepo.TopTitles)
+}
+
+// TopAuthors retorna os autores mais emprestados
+func (s *ReportService) TopAuthors(filter domain.ReportFilter) (*domain.Report, error) {
+	return s.run(reportTopAuthors, filter, s.reportRepo.TopAuthors)
+}
+
+// ActivePatrons retorna os leitores com mais empréstimos
+func (s *ReportService) ActivePatrons(filter domain.ReportFilter) (*domain.Report, error) {
+	return s.run(reportActivePatrons, filter, s.reportRepo.ActivePatrons)
+}
+
+// OverdueRate calcula, por período, a proporção de empréstimos que atrasaram
+func (s *ReportService) OverdueRate(filter domain.ReportFilter) (*domain.Report, error) {
+	return s.run(reportOverdueRate, filter, s.reportRepo.OverdueRate)
+}
+
+// LoanDuration calcula, por período, a duração em dias dos empréstimos devolvidos
+func (s *ReportService) LoanDuration(filter domain.ReportFilter) (*domain.Report, error) {
+	return s.run(reportLoanDuration, filter, s.reportRepo.LoanDuration)
+}
+
+// NeverBorrowed lista os livros sem empréstimos no intervalo (candidatos ao
+// descarte); sem limit, lista todos
+func (s *ReportService) NeverBorrowed(filter domain.ReportFilter) (*domain.Report, error) {
+	return s.run(reportNeverBorrowed, filter, s.reportRepo.NeverBorrowed)
+}
+
+// Write grava as linhas do relatório no formato informado
+func (s *ReportService) Write(w io.Writer, report *domain.Report, format string) error {
+	recordFormat, err := s.Format(format)
+	if err != nil {
+		return err
+	}
+
+	writer := recordFormat.NewWriter(w, report.Columns)
+	for _, row := range report.Rows {
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// run valida e completa o filtro e gera o relatório com reportFn. Sem
+// interval, os relatórios por período são agrupados por mês.
+func (s *ReportService) run(kind reportKind, filter domain.ReportFilter, reportFn func(domain.ReportFilter) (*domain.Report, error)) (*domain.Report, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, domain.ErrInvalidDateRange
+	}
+	if filter.Limit < 0 {
+		return nil, domain.NewFieldError("limit", "invalid_limit", "limit não pode ser negativo")
+	}
+	if filter.Limit == 0 {
+		filter.Limit = kind.defaultLimit
+	}
+	if filter.Limit > domain.MaxReportLimit {
+		filter.Limit = domain.MaxReportLimit
+	}
+
+	if !kind.periodic {
+		filter.Interval = ""
+	} else if filter.Interval == "" {
+		filter.Interval = domain.IntervalMonth
+	} else if !filter.Interval.IsValid() {
+		return nil, domain.ErrInvalidInterval
+	}
+	filter.Now = time.Now()
+
+	report, err := reportFn(filter)
+	if err != nil {
+		return nil, err
+	}
+	report.Name = kind.name
+	report.From = filter.From
+	report.To = filter.To
+	report.Interval = filter.Interval
+	return report, nil
+}
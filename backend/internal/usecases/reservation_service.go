@@ -0,0 +1,306 @@
+package usecases
+
+import (
+	"context"
+	"library-management/internal/domain"
+	"time"
+)
+
+// ReservationService implementa os casos de uso para reservas (fila de espera)
+type ReservationService struct {
+	reservationRepo domain.ReservationRepository
+	bookRepo        domain.BookRepository
+	userRepo        domain.UserRepository
+	uow             domain.UnitOfWork
+	policy          domain.CirculationPolicy
+}
+
+// NewReservationService cria uma nova instância do ReservationService
+func NewReservationService(reservationRepo domain.ReservationRepository, bookRepo domain.BookRepository, userRepo domain.UserRepository, uow domain.UnitOfWork, policy domain.CirculationPolicy) *ReservationService {
+	return &ReservationService{
+		reservationRepo: reservationRepo,
+		bookRepo:        bookRepo,
+		userRepo:        userRepo,
+		uow:             uow,
+		policy:          policy,
+	}
+}
+
+// PlaceHold coloca o usuário no fim da fila de reservas do livro.
+// Só é possível reservar um livro que não tenha exemplares disponíveis
+// (ou que já tenha fila).
+func (s *ReservationService) PlaceHold(ctx context.Context, bookID, userID string) (*domain.Reservation, error) {
+	var reservation *domain.Reservation
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		book, err := repos.Books.GetByID(bookID)
+		if err != nil {
+			return err
+		}
+
+		user, err := repos.Users.GetByID(userID)
+		if err != nil {
+			return err
+		}
+
+		existing, err := repos.Reservations.GetActiveByUserAndBook(userID, bookID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return domain.ErrHoldExists
+		}
+
+		loans, err := repos.Loans.GetLoansByUser(userID)
+		if err != nil {
+			return err
+		}
+		for _, loan := range loans {
+			if !loan.IsReturned && loan.BookID == book.ID {
+				return domain.NewConflict("book_already_on_loan", "usuário já está com este livro emprestado")
+			}
+		}
+
+		now := time.Now()
+		if err := expireBookHolds(repos, bookID, now, s.policy); err != nil {
+			return err
+		}
+
+		queue, err := repos.Reservations.GetQueueByBook(bookID)
+		if err != nil {
+			return err
+		}
+		if len(queue) == 0 && book.AvailableCopies > 0 {
+			return domain.NewPolicyViolation("book_available", "livro está disponível para empréstimo")
+		}
+
+		reservation = &domain.Reservation{
+			BookID:    book.ID,
+			UserID:    user.ID,
+			Status:    domain.ReservationStatusWaiting,
+			Position:  len(queue) + 1,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := repos.Reservations.Create(reservation); err != nil {
+			return err
+		}
+
+		reservation.Book = book
+		reservation.User = user
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// CancelHold cancela uma reserva ativa. Se ela já tinha um exemplar
+// separado, o exemplar passa para o próximo da fila.
+func (s *ReservationService) CancelHold(ctx context.Context, id string) (*domain.Reservation, error) {
+	var reservation *domain.Reservation
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		var err error
+		reservation, err = repos.Reservations.GetByID(id)
+		if err != nil {
+			return err
+		}
+
+		if !reservation.IsActive() {
+			return domain.NewConflict("hold_not_active", "reserva não está ativa")
+		}
+
+		now := time.Now()
+		return closeHold(repos, reservation, domain.ReservationStatusCancelled, now, s.policy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// GetHoldByID retorna uma reserva pelo ID
+func (s *ReservationService) GetHoldByID(id string) (*domain.Reservation, error) {
+	reservation, err := s.reservationRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// GetHoldsByBook retorna a fila de reservas ativas de um livro
+func (s *ReservationService) GetHoldsByBook(bookID string) ([]*domain.Reservation, error) {
+	if _, err := s.bookRepo.GetByID(bookID); err != nil {
+		return nil, err
+	}
+
+	queue, err := s.reservationRepo.GetQueueByBook(bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, reservation := range queue {
+		reservation.Position = i + 1
+		if user, err := s.userRepo.GetByID(reservation.UserID.String()); err == nil {
+			reservation.User = user
+		}
+	}
+
+	return queue, nil
+}
+
+// GetHoldsByUser retorna as reservas de um usuário, com a posição na fila das ativas
+func (s *ReservationService) GetHoldsByUser(userID string) ([]*domain.Reservation, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	reservations, err := s.reservationRepo.GetByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reservation := range reservations {
+		if book, err := s.bookRepo.GetByIDWithDeleted(reservation.BookID.String()); err == nil {
+			reservation.Book = book
+		}
+		if !reservation.IsActive() {
+			continue
+		}
+		queue, err := s.reservationRepo.GetQueueByBook(reservation.BookID.String())
+		if err != nil {
+			return nil, err
+		}
+		for i, queued := range queue {
+			if queued.ID == reservation.ID {
+				reservation.Position = i + 1
+				break
+			}
+		}
+	}
+
+	return reservations, nil
+}
+
+// ExpireHolds encerra todas as reservas cujo prazo de retirada terminou,
+// repassando os exemplares separados para os próximos da fila.
+// Retorna quantas reservas foram expiradas.
+func (s *ReservationService) ExpireHolds(ctx context.Context) (int, error) {
+	count := 0
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		now := time.Now()
+		expired, err := repos.Reservations.GetExpiredReady(now)
+		if err != nil {
+			return err
+		}
+		for _, reservation := range expired {
+			if err := closeHold(repos, reservation, domain.ReservationStatusExpired, now, s.policy); err != nil {
+				return err
+			}
+		}
+		count = len(expired)
+		return nil
+	})
+	return count, err
+}
+
+// expireBookHolds expira as reservas prontas de um livro cujo prazo de retirada terminou
+func expireBookHolds(repos domain.Repositories, bookID string, now time.Time, policy domain.CirculationPolicy) error {
+	queue, err := repos.Reservations.GetQueueByBook(bookID)
+	if err != nil {
+		return err
+	}
+
+	for _, reservation := range queue {
+		if reservation.Status == domain.ReservationStatusReady && reservation.ExpiresAt != nil && now.After(*reservation.ExpiresAt) {
+			if err := closeHold(repos, reservation, domain.ReservationStatusExpired, now, policy); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// closeHold encerra uma reserva com o status informado e, se ela tinha um
+// exemplar separado, libera o exemplar para o próximo da fila
+func closeHold(repos domain.Repositories, reservation *domain.Reservation, status domain.ReservationStatus, now time.Time, policy domain.CirculationPolicy) error {
+	heldItemID := reservation.ItemID
+	wasReady := reservation.Status == domain.ReservationStatusReady
+
+	reservation.Status = status
+	reservation.UpdatedAt = now
+	if err := repos.Reservations.Update(reservation); err != nil {
+		return err
+	}
+
+	if !wasReady || heldItemID == nil {
+		return nil
+	}
+
+	item, err := repos.Items.GetByID(heldItemID.String())
+	if err != nil {
+		return err
+	}
+	if item.Status != domain.ItemStatusOnHold {
+		return nil
+	}
+
+	item.Status = domain.ItemStatusAvailable
+	item.UpdatedAt = now
+	if err := repos.Items.Update(item); err != nil {
+		return err
+	}
+
+	_, err = promoteNextHold(repos, item, now, policy)
+	return err
+}
+
+// promoteNextHold separa um exemplar recém-liberado para a primeira reserva
+// aguardando na fila do livro, com o prazo de retirada da política do usuário
+// (ou de fallback). Retorna a reserva promovida, ou nil se a fila estiver
+// vazia (e o exemplar continua disponível).
+func promoteNextHold(repos domain.Repositories, item *domain.Item, now time.Time, fallback domain.CirculationPolicy) (*domain.Reservation, error) {
+	if item.Status != domain.ItemStatusAvailable {
+		return nil, nil
+	}
+
+	queue, err := repos.Reservations.GetQueueByBook(item.BookID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reservation := range queue {
+		if reservation.Status != domain.ReservationStatusWaiting {
+			continue
+		}
+
+		policy, err := policyFor(repos, fallback, reservation.UserID, item.BookID)
+		if err != nil {
+			return nil, err
+		}
+
+		expiresAt := now.AddDate(0, 0, policy.HoldPickupDays)
+		itemID := item.ID
+		reservation.Status = domain.ReservationStatusReady
+		reservation.ItemID = &itemID
+		reservation.ReadyAt = &now
+		reservation.ExpiresAt = &expiresAt
+		reservation.UpdatedAt = now
+		if err := repos.Reservations.Update(reservation); err != nil {
+			return nil, err
+		}
+
+		item.Status = domain.ItemStatusOnHold
+		item.UpdatedAt = now
+		if err := repos.Items.Update(item); err != nil {
+			return nil, err
+		}
+
+		return reservation, nil
+	}
+
+	return nil, nil
+}
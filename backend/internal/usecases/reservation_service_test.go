@@ -0,0 +1,234 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"library-management/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestPlaceHold(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	service := newTestReservationService(db)
+	book := createBook(t, db, "Dom Casmurro", 1)
+	borrower := createUser(t, db, "bentinho@example.com")
+	first := createUser(t, db, "capitu@example.com")
+	second := createUser(t, db, "escobar@example.com")
+
+	if _, err := service.PlaceHold(ctx, book.ID.String(), first.ID.String()); errorCode(err) != "book_available" {
+		t.Errorf("reserva de livro disponível: erro = %v, esperado book_available", err)
+	}
+
+	checkout(t, db, book.ID.String(), borrower.ID.String(), time.Now().Add(24*time.Hour))
+	if _, err := service.PlaceHold(ctx, book.ID.String(), borrower.ID.String()); errorCode(err) != "book_already_on_loan" {
+		t.Errorf("reserva de quem está com o livro: erro = %v, esperado book_already_on_loan", err)
+	}
+
+	for i, user := range []*domain.User{first, second} {
+		hold, err := service.PlaceHold(ctx, book.ID.String(), user.ID.String())
+		if err != nil {
+			t.Fatalf("erro inesperado %v", err)
+		}
+		if hold.Status != domain.ReservationStatusWaiting || hold.Position != i+1 {
+			t.Errorf("reserva = %s na posição %d, esperado waiting na posição %d", hold.Status, hold.Position, i+1)
+		}
+	}
+	if _, err := service.PlaceHold(ctx, book.ID.String(), first.ID.String()); !errors.Is(err, domain.ErrHoldExists) {
+		t.Errorf("reserva repetida: erro = %v, esperado %v", err, domain.ErrHoldExists)
+	}
+}
+
+// TestHoldQueue acompanha a fila de um livro com um exemplar: a devolução
+// separa o exemplar para o primeiro da fila, que é o único que pode levá-lo
+func TestHoldQueue(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	service := newTestReservationService(db)
+	loans := newTestLoanService(db)
+	book := createBook(t, db, "Dom Casmurro", 1)
+	borrower := createUser(t, db, "bentinho@example.com")
+	first := createUser(t, db, "capitu@example.com")
+	second := createUser(t, db, "escobar@example.com")
+
+	loan := checkout(t, db, book.ID.String(), borrower.ID.String(), time.Now().Add(24*time.Hour))
+	firstHold, err := service.PlaceHold(ctx, book.ID.String(), first.ID.String())
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if _, err := service.PlaceHold(ctx, book.ID.String(), second.ID.String()); err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+
+	returned, err := loans.ReturnLoan(ctx, loan.ID.String())
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if returned.Item.Status != domain.ItemStatusOnHold {
+		t.Errorf("exemplar devolvido = %s, esperado on_hold", returned.Item.Status)
+	}
+
+	ready, err := service.GetHoldByID(firstHold.ID.String())
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if ready.Status != domain.ReservationStatusReady || ready.ItemID == nil || *ready.ItemID != loan.ItemID {
+		t.Fatalf("reserva = %s, exemplar %v, esperado ready com o exemplar devolvido", ready.Status, ready.ItemID)
+	}
+	wantExpiry := ready.ReadyAt.AddDate(0, 0, domain.DefaultCirculationPolicy().HoldPickupDays)
+	if ready.ExpiresAt == nil || !ready.ExpiresAt.Equal(wantExpiry) {
+		t.Errorf("prazo de retirada = %v, esperado %v", ready.ExpiresAt, wantExpiry)
+	}
+
+	for _, user := range []*domain.User{second, borrower} {
+		if _, err := loans.CreateLoan(ctx, book.ID.String(), "", user.ID.String()); errorCode(err) != "reserved_for_other" {
+			t.Errorf("empréstimo de %s: erro = %v, esperado reserved_for_other", user.Email, err)
+		}
+	}
+
+	got, err := loans.CreateLoan(ctx, book.ID.String(), "", first.ID.String())
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if got.ItemID != loan.ItemID {
+		t.Errorf("exemplar emprestado = %s, esperado o separado %s", got.ItemID, loan.ItemID)
+	}
+	fulfilled, err := service.GetHoldByID(firstHold.ID.String())
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if fulfilled.Status != domain.ReservationStatusFulfilled {
+		t.Errorf("reserva = %s, esperado fulfilled", fulfilled.Status)
+	}
+}
+
+// TestHoldHandOver confere que o exemplar separado passa para o próximo da
+// fila quando a reserva é cancelada ou o prazo de retirada termina
+func TestHoldHandOver(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	service := newTestReservationService(db)
+	book := createBook(t, db, "Dom Casmurro", 1)
+	borrower := createUser(t, db, "bentinho@example.com")
+	users := []*domain.User{
+		createUser(t, db, "capitu@example.com"),
+		createUser(t, db, "escobar@example.com"),
+		createUser(t, db, "sancha@example.com"),
+	}
+
+	loan := checkout(t, db, book.ID.String(), borrower.ID.String(), time.Now().Add(24*time.Hour))
+	holds := make([]*domain.Reservation, len(users))
+	for i, user := range users {
+		hold, err := service.PlaceHold(ctx, book.ID.String(), user.ID.String())
+		if err != nil {
+			t.Fatalf("erro inesperado %v", err)
+		}
+		holds[i] = hold
+	}
+	if _, err := newTestLoanService(db).ReturnLoan(ctx, loan.ID.String()); err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+
+	status := func(hold *domain.Reservation) domain.ReservationStatus {
+		t.Helper()
+		got, err := service.GetHoldByID(hold.ID.String())
+		if err != nil {
+			t.Fatalf("erro inesperado %v", err)
+		}
+		return got.Status
+	}
+
+	if _, err := service.CancelHold(ctx, holds[0].ID.String()); err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if got := status(holds[1]); got != domain.ReservationStatusReady {
+		t.Errorf("reserva seguinte ao cancelamento = %s, esperado ready", got)
+	}
+	if _, err := service.CancelHold(ctx, holds[0].ID.String()); errorCode(err) != "hold_not_active" {
+		t.Errorf("cancelamento repetido: erro = %v, esperado hold_not_active", err)
+	}
+
+	if _, err := db.Exec(`UPDATE reservations SET expires_at = ? WHERE id = ?`, time.Now().Add(-time.Minute), holds[1].ID.String()); err != nil {
+		t.Fatalf("erro ao vencer a reserva: %v", err)
+	}
+	if expired, err := service.ExpireHolds(ctx); err != nil || expired != 1 {
+		t.Fatalf("ExpireHolds = %d, %v, esperado 1", expired, err)
+	}
+	if got := status(holds[1]); got != domain.ReservationStatusExpired {
+		t.Errorf("reserva vencida = %s, esperado expired", got)
+	}
+	if got := status(holds[2]); got != domain.ReservationStatusReady {
+		t.Errorf("reserva seguinte à expiração = %s, esperado ready", got)
+	}
+}
+
+func TestRenewLoan(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	service := newTestLoanService(db)
+	policy := domain.DefaultCirculationPolicy()
+
+	t.Run("limite de renovações", func(t *testing.T) {
+		user := createUser(t, db, "bentinho@example.com")
+		due := time.Now().Add(24 * time.Hour)
+		loan := checkout(t, db, createBook(t, db, "Dom Casmurro", 1).ID.String(), user.ID.String(), due)
+		for i := 1; i <= policy.MaxRenewals; i++ {
+			renewed, err := service.RenewLoan(ctx, loan.ID.String())
+			if err != nil {
+				t.Fatalf("renovação %d: erro inesperado %v", i, err)
+			}
+			due = due.AddDate(0, 0, policy.LoanPeriodDays)
+			if renewed.RenewalCount != i || !renewed.DueDate.Equal(due) {
+				t.Errorf("renovação %d = %d, vencimento %v, esperado %d e %v", i, renewed.RenewalCount, renewed.DueDate, i, due)
+			}
+		}
+		if _, err := service.RenewLoan(ctx, loan.ID.String()); errorCode(err) != "renewal_limit_reached" {
+			t.Errorf("erro = %v, esperado renewal_limit_reached", err)
+		}
+	})
+
+	t.Run("atrasado", func(t *testing.T) {
+		user := createUser(t, db, "capitu@example.com")
+		loan := checkout(t, db, createBook(t, db, "Helena", 1).ID.String(), user.ID.String(), time.Now().Add(-25*time.Hour))
+		if _, err := service.RenewLoan(ctx, loan.ID.String()); errorCode(err) != "renewal_overdue" {
+			t.Errorf("erro = %v, esperado renewal_overdue", err)
+		}
+	})
+
+	t.Run("vencido dentro da tolerância", func(t *testing.T) {
+		user := createUser(t, db, "escobar@example.com")
+		loan := checkout(t, db, createBook(t, db, "Iaiá Garcia", 1).ID.String(), user.ID.String(), time.Now().Add(-time.Hour))
+		renewed, err := service.RenewLoan(ctx, loan.ID.String())
+		if err != nil {
+			t.Fatalf("erro inesperado %v", err)
+		}
+		// O novo prazo conta a partir de hoje
+		if min := time.Now().AddDate(0, 0, policy.LoanPeriodDays).Add(-time.Minute); renewed.DueDate.Before(min) {
+			t.Errorf("vencimento = %v, esperado a partir de %v", renewed.DueDate, min)
+		}
+	})
+
+	t.Run("com reservas", func(t *testing.T) {
+		user := createUser(t, db, "sancha@example.com")
+		book := createBook(t, db, "Memorial de Aires", 1)
+		loan := checkout(t, db, book.ID.String(), user.ID.String(), time.Now().Add(24*time.Hour))
+		if _, err := newTestReservationService(db).PlaceHold(ctx, book.ID.String(), createUser(t, db, "prima.justina@example.com").ID.String()); err != nil {
+			t.Fatalf("erro inesperado %v", err)
+		}
+		if _, err := service.RenewLoan(ctx, loan.ID.String()); errorCode(err) != "renewal_has_holds" {
+			t.Errorf("erro = %v, esperado renewal_has_holds", err)
+		}
+	})
+
+	t.Run("devolvido", func(t *testing.T) {
+		user := createUser(t, db, "jose.dias@example.com")
+		loan := checkout(t, db, createBook(t, db, "Ressurreição", 1).ID.String(), user.ID.String(), time.Now().Add(24*time.Hour))
+		if _, err := service.ReturnLoan(ctx, loan.ID.String()); err != nil {
+			t.Fatalf("erro inesperado %v", err)
+		}
+		if _, err := service.RenewLoan(ctx, loan.ID.String()); !errors.Is(err, domain.ErrLoanReturned) {
+			t.Errorf("erro = %v, esperado %v", err, domain.ErrLoanReturned)
+		}
+	})
+}
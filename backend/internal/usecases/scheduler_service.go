@@ -0,0 +1,270 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"library-management/internal/domain"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SchedulerService executa as tarefas em segundo plano nos horários
+// agendados ou sob demanda, guardando cada execução no histórico. Uma tarefa
+// só roda em um processo por vez: antes de executar, o processo a reserva no
+// banco por lockTimeout, prorrogando a reserva enquanto a tarefa roda, e cada horário agendado é atendido uma única vez,
+// mesmo com vários processos apontando para o mesmo banco.
+type SchedulerService struct {
+	jobRepo     domain.JobRepository
+	jobs        map[string]domain.Job
+	lockTimeout time.Duration
+	// owner identifica este processo nas reservas
+	owner string
+
+	// next guarda o próximo horário de cada tarefa enquanto o agendador roda
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// NewSchedulerService cria uma nova instância do SchedulerService com as tarefas informadas
+func NewSchedulerService(jobRepo domain.JobRepository, lockTimeout time.Duration, jobs ...domain.Job) *SchedulerService {
+	byName := make(map[string]domain.Job, len(jobs))
+	for _, job := range jobs {
+		byName[job.Name] = job
+	}
+	return &SchedulerService{
+		jobRepo:     jobRepo,
+		jobs:        byName,
+		lockTimeout: lockTimeout,
+		owner:       uuid.NewString(),
+	}
+}
+
+// Start executa as tarefas nos horários agendados até ctx ser cancelado.
+// Horários perdidos enquanto o servidor estava parado não são recuperados.
+func (s *SchedulerService) Start(ctx context.Context) {
+	if count, err := s.jobRepo.FailInterrupted(time.Now()); err != nil {
+		log.Printf("Erro ao encerrar execuções interrompidas: %v", err)
+	} else if count > 0 {
+		log.Printf("%d execução(ões) interrompida(s) marcada(s) como falha", count)
+	}
+
+	next := make(map[string]time.Time, len(s.jobs))
+	now := time.Now()
+	for name, job := range s.jobs {
+		next[name] = job.Schedule.Next(now)
+	}
+
+	for {
+		s.publish(next)
+		timer := time.NewTimer(time.Until(earliest(next)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		for name, at := range next {
+			if at.IsZero() || at.After(now) {
+				continue
+			}
+			go s.runScheduled(s.jobs[name], at)
+			next[name] = s.jobs[name].Schedule.Next(now)
+		}
+	}
+}
+
+// publish guarda uma cópia dos próximos horários para ListJobs
+func (s *SchedulerService) publish(next map[string]time.Time) {
+	snapshot := make(map[string]time.Time, len(next))
+	for name, at := range next {
+		snapshot[name] = at
+	}
+	s.mu.Lock()
+	s.next = snapshot
+	s.mu.Unlock()
+}
+
+// earliest retorna o menor horário agendado; sem nenhum, um dia depois de
+// agora, só para que o laço acorde de vez em quando
+func earliest(next map[string]time.Time) time.Time {
+	first := time.Now().Add(24 * time.Hour)
+	for _, at := range next {
+		if !at.IsZero() && at.Before(first) {
+			first = at
+		}
+	}
+	return first
+}
+
+// ListJobs retorna as tarefas cadastradas, com o próximo horário (se o
+// agendador estiver rodando) e a última execução
+func (s *SchedulerService) ListJobs() ([]*domain.JobInfo, error) {
+	next := s.nextRuns()
+	jobs := make([]*domain.JobInfo, 0, len(s.jobs))
+	for name, job := range s.jobs {
+		lastRun, err := s.jobRepo.LastRun(name)
+		if err != nil {
+			return nil, err
+		}
+		info := &domain.JobInfo{
+			Name:        name,
+			Description: job.Description,
+			Schedule:    job.Schedule.String(),
+			LastRun:     lastRun,
+		}
+		if at, ok := next[name]; ok && !at.IsZero() {
+			info.NextRun = &at
+		}
+		jobs = append(jobs, info)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs, nil
+}
+
+// nextRuns retorna os últimos horários publicados pelo agendador; nil se
+// ele não estiver rodando
+func (s *SchedulerService) nextRuns() map[string]time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next
+}
+
+// ListRuns retorna uma página do histórico de execuções de uma tarefa e o total
+func (s *SchedulerService) ListRuns(name string, page domain.Page) ([]*domain.JobRun, int, error) {
+	if _, ok := s.jobs[name]; !ok {
+		return nil, 0, domain.ErrJobNotFound
+	}
+	return s.jobRepo.ListRuns(name, page)
+}
+
+// Trigger inicia uma execução manual da tarefa e retorna sem esperar o fim;
+// o resultado fica no histórico. Se a tarefa já estiver em execução, em
+// qualquer processo, retorna ErrJobRunning.
+func (s *SchedulerService) Trigger(name string) (*domain.JobRun, error) {
+	job, ok := s.jobs[name]
+	if !ok {
+		return nil, domain.ErrJobNotFound
+	}
+
+	run, err := s.begin(job, domain.JobTriggerManual, nil)
+	if err != nil {
+		return nil, err
+	}
+	started := *run
+	go s.execute(job, run)
+	return &started, nil
+}
+
+// runScheduled executa a tarefa no horário agendado at, a menos que outro
+// processo já a esteja executando ou já tenha atendido esse horário
+func (s *SchedulerService) runScheduled(job domain.Job, at time.Time) {
+	run, err := s.begin(job, domain.JobTriggerSchedule, &at)
+	if errors.Is(err, domain.ErrJobRunning) || errors.Is(err, domain.ErrDuplicate) {
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao iniciar a tarefa %s: %v", job.Name, err)
+		return
+	}
+	s.execute(job, run)
+}
+
+// begin reserva a tarefa e registra o início da execução
+func (s *SchedulerService) begin(job domain.Job, trigger domain.JobTrigger, scheduledFor *time.Time) (*domain.JobRun, error) {
+	now := time.Now()
+	acquired, err := s.jobRepo.Acquire(job.Name, s.owner, now, now.Add(s.lockTimeout))
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, domain.ErrJobRunning
+	}
+
+	run := &domain.JobRun{
+		Job:          job.Name,
+		Trigger:      trigger,
+		ScheduledFor: scheduledFor,
+		Status:       domain.JobStatusRunning,
+		StartedAt:    now,
+	}
+	if err := s.jobRepo.CreateRun(run); err != nil {
+		s.release(job.Name)
+		return nil, err
+	}
+	return run, nil
+}
+
+// execute roda a tarefa, grava o resultado no histórico e libera a reserva.
+// Um pânico na tarefa é registrado como falha. As alterações da tarefa são
+// registradas na auditoria em nome do sistema, com o identificador
+// "job:<id da execução>" no lugar do identificador da requisição.
+func (s *SchedulerService) execute(job domain.Job, run *domain.JobRun) {
+	defer s.release(job.Name)
+	done := make(chan struct{})
+	defer close(done)
+	go s.keepLocked(job.Name, done)
+
+	ctx := domain.WithRequestID(context.Background(), "job:"+run.ID.String())
+	result, err := func() (result string, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("pânico: %v", r)
+			}
+		}()
+		return job.Run(ctx, run.StartedAt)
+	}()
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Result = result
+	run.Status = domain.JobStatusSucceeded
+	if err != nil {
+		run.Status = domain.JobStatusFailed
+		run.Error = err.Error()
+		log.Printf("Tarefa %s falhou: %v", job.Name, err)
+	}
+	if err := s.jobRepo.UpdateRun(run); err != nil {
+		log.Printf("Erro ao registrar a execução da tarefa %s: %v", job.Name, err)
+	}
+}
+
+// keepLocked prorroga a reserva da tarefa a cada terço de lockTimeout até done
+// ser fechado, para que uma execução mais longa que lockTimeout não perca a
+// reserva enquanto este processo estiver vivo. Se o processo parar, a reserva
+// expira em até lockTimeout.
+func (s *SchedulerService) keepLocked(name string, done <-chan struct{}) {
+	interval := s.lockTimeout / 3
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			held, err := s.jobRepo.Extend(name, s.owner, now.Add(s.lockTimeout))
+			if err != nil {
+				log.Printf("Erro ao prorrogar a reserva da tarefa %s: %v", name, err)
+			} else if !held {
+				log.Printf("A reserva da tarefa %s foi perdida", name)
+				return
+			}
+		}
+	}
+}
+
+// release libera a reserva da tarefa, registrando eventuais erros
+func (s *SchedulerService) release(name string) {
+	if err := s.jobRepo.Release(name, s.owner); err != nil {
+		log.Printf("Erro ao liberar a tarefa %s: %v", name, err)
+	}
+}
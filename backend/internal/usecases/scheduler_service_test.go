@@ -0,0 +1,171 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"library-management/internal/domain"
+	"library-management/internal/infrastructure/database"
+	"testing"
+	"time"
+)
+
+// blockingJob cria uma tarefa que avisa em started ao começar e só termina
+// quando release for fechado
+func blockingJob(t *testing.T, started chan<- struct{}, release <-chan struct{}) domain.Job {
+	schedule, err := domain.ParseSchedule("@daily")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return domain.Job{
+		Name:     "test",
+		Schedule: schedule,
+		Run: func(ctx context.Context, now time.Time) (string, error) {
+			started <- struct{}{}
+			<-release
+			return "ok", nil
+		},
+	}
+}
+
+// waitRun espera a execução terminar
+func waitRun(t *testing.T, repo domain.JobRepository, id string) *domain.JobRun {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		run, err := repo.LastRun("test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if run != nil && run.ID.String() == id && run.Status != domain.JobStatusRunning {
+			return run
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("a execução %s não terminou", id)
+	return nil
+}
+
+// triggerWhenReleased dispara a tarefa assim que a reserva anterior for
+// liberada, o que acontece logo depois de a execução ser gravada
+func triggerWhenReleased(t *testing.T, scheduler *SchedulerService, name string) *domain.JobRun {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		run, err := scheduler.Trigger(name)
+		if err == nil {
+			return run
+		}
+		if !errors.Is(err, domain.ErrJobRunning) {
+			t.Fatalf("erro ao disparar a tarefa: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("a reserva da tarefa %s não foi liberada", name)
+	return nil
+}
+
+// TestSchedulerLocking confere que dois processos sobre o mesmo banco não
+// executam a mesma tarefa ao mesmo tempo
+func TestSchedulerLocking(t *testing.T) {
+	repo := database.NewJobRepository(newTestDB(t))
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	job := blockingJob(t, started, release)
+	first := NewSchedulerService(repo, time.Minute, job)
+	second := NewSchedulerService(repo, time.Minute, job)
+
+	run, err := first.Trigger(job.Name)
+	if err != nil {
+		t.Fatalf("erro ao disparar a tarefa: %v", err)
+	}
+	<-started
+
+	for name, scheduler := range map[string]*SchedulerService{"mesmo processo": first, "outro processo": second} {
+		if _, err := scheduler.Trigger(job.Name); !errors.Is(err, domain.ErrJobRunning) {
+			t.Errorf("%s: erro = %v, esperado %v", name, err, domain.ErrJobRunning)
+		}
+	}
+
+	close(release)
+	if finished := waitRun(t, repo, run.ID.String()); finished.Status != domain.JobStatusSucceeded || finished.Result != "ok" {
+		t.Errorf("execução = %s (%q), esperado succeeded (\"ok\")", finished.Status, finished.Result)
+	}
+
+	// Com a reserva liberada, o outro processo consegue executar
+	run = triggerWhenReleased(t, second, job.Name)
+	<-started
+	waitRun(t, repo, run.ID.String())
+}
+
+// TestSchedulerLockExpiry confere que a reserva de um processo que parou
+// expira depois de lockTimeout
+func TestSchedulerLockExpiry(t *testing.T) {
+	repo := database.NewJobRepository(newTestDB(t))
+	now := time.Now()
+
+	tests := []struct {
+		owner string
+		at    time.Time
+		want  bool
+	}{
+		{"a", now, true},
+		{"b", now.Add(30 * time.Second), false},
+		{"b", now.Add(2 * time.Minute), true},
+		{"a", now.Add(2 * time.Minute), false},
+	}
+	for _, tt := range tests {
+		acquired, err := repo.Acquire("test", tt.owner, tt.at, tt.at.Add(time.Minute))
+		if err != nil {
+			t.Fatalf("erro ao reservar: %v", err)
+		}
+		if acquired != tt.want {
+			t.Errorf("Acquire(%s, %s) = %v, esperado %v", tt.owner, tt.at.Sub(now), acquired, tt.want)
+		}
+	}
+}
+
+// TestSchedulerLockRenewal confere que a reserva é prorrogada enquanto a
+// tarefa roda além de lockTimeout
+func TestSchedulerLockRenewal(t *testing.T) {
+	repo := database.NewJobRepository(newTestDB(t))
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	job := blockingJob(t, started, release)
+	const lockTimeout = 150 * time.Millisecond
+	first := NewSchedulerService(repo, lockTimeout, job)
+	second := NewSchedulerService(repo, lockTimeout, job)
+
+	run, err := first.Trigger(job.Name)
+	if err != nil {
+		t.Fatalf("erro ao disparar a tarefa: %v", err)
+	}
+	<-started
+
+	time.Sleep(3 * lockTimeout)
+	if _, err := second.Trigger(job.Name); !errors.Is(err, domain.ErrJobRunning) {
+		t.Errorf("depois de lockTimeout: erro = %v, esperado %v", err, domain.ErrJobRunning)
+	}
+
+	close(release)
+	waitRun(t, repo, run.ID.String())
+}
+
+// TestSchedulerRunsScheduleOnce confere que cada horário agendado é atendido
+// uma única vez, mesmo por processos diferentes em fusos diferentes
+func TestSchedulerRunsScheduleOnce(t *testing.T) {
+	repo := database.NewJobRepository(newTestDB(t))
+	started, release := make(chan struct{}, 2), make(chan struct{})
+	close(release)
+	job := blockingJob(t, started, release)
+	at := time.Date(2026, 3, 10, 0, 0, 0, 0, time.FixedZone("BRT", -3*60*60))
+
+	NewSchedulerService(repo, time.Minute, job).runScheduled(job, at)
+	NewSchedulerService(repo, time.Minute, job).runScheduled(job, at.In(time.FixedZone("CET", 60*60)))
+
+	if len(started) != 1 {
+		t.Errorf("execuções = %d, esperado 1", len(started))
+	}
+	runs, total, err := repo.ListRuns(job.Name, domain.Page{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || runs[0].Trigger != domain.JobTriggerSchedule {
+		t.Errorf("histórico = %d execução(ões), esperado 1 agendada", total)
+	}
+}
@@ -0,0 +1,200 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"library-management/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StandingService implementa os casos de uso da situação do usuário e dos bloqueios manuais
+type StandingService struct {
+	blockRepo   domain.BlockRepository
+	userRepo    domain.UserRepository
+	loanRepo    domain.LoanRepository
+	accountRepo domain.AccountRepository
+	policyRepo  domain.PolicyRepository
+	uow         domain.UnitOfWork
+	policy      domain.CirculationPolicy
+}
+
+// NewStandingService cria uma nova instância do StandingService
+func NewStandingService(blockRepo domain.BlockRepository, userRepo domain.UserRepository, loanRepo domain.LoanRepository, accountRepo domain.AccountRepository,
+	policyRepo domain.PolicyRepository, uow domain.UnitOfWork, policy domain.CirculationPolicy) *StandingService {
+	return &StandingService{
+		blockRepo:   blockRepo,
+		userRepo:    userRepo,
+		loanRepo:    loanRepo,
+		accountRepo: accountRepo,
+		policyRepo:  policyRepo,
+		uow:         uow,
+		policy:      policy,
+	}
+}
+
+// GetStanding retorna a situação do usuário e, se ele não puder pegar livros
+// emprestados, os motivos. A consulta só lê, então usa os repositórios fora
+// de transação, sem disputar a trava de escrita do banco.
+func (s *StandingService) GetStanding(userID string) (*domain.PatronStanding, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	repos := domain.Repositories{
+		Users:    s.userRepo,
+		Loans:    s.loanRepo,
+		Accounts: s.accountRepo,
+		Policies: s.policyRepo,
+		Blocks:   s.blockRepo,
+	}
+	return patronStanding(repos, s.policy, user, time.Now())
+}
+
+// GetBlocks retorna o histórico de bloqueios manuais do usuário
+func (s *StandingService) GetBlocks(userID string) ([]*domain.PatronBlock, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	blocks, err := s.blockRepo.GetByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if blocks == nil {
+		blocks = []*domain.PatronBlock{}
+	}
+	return blocks, nil
+}
+
+// BlockUser impede o usuário de pegar livros emprestados até expiresAt
+// (ou até o bloqueio ser levantado, se expiresAt for nil)
+func (s *StandingService) BlockUser(ctx context.Context, userID, reason string, expiresAt *time.Time) (*domain.PatronBlock, error) {
+	if reason == "" {
+		return nil, domain.NewFieldError("reason", "reason_required", "motivo do bloqueio é obrigatório")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, domain.NewFieldError("expires_at", "expiry_in_past", "data de expiração do bloqueio deve ser futura")
+	}
+
+	block := &domain.PatronBlock{
+		UserID:    user.ID,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}
+	err = inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		return repos.Blocks.Create(block)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// LiftBlock levanta um bloqueio manual antes do prazo
+func (s *StandingService) LiftBlock(ctx context.Context, userID, blockID string) (*domain.PatronBlock, error) {
+	block, err := s.blockRepo.GetByID(blockID)
+	if err != nil {
+		return nil, err
+	}
+	if block.UserID.String() != userID {
+		return nil, domain.ErrBlockNotFound
+	}
+
+	now := time.Now()
+	if !block.IsActive(now) {
+		return nil, domain.NewConflict("block_not_active", "bloqueio não está ativo")
+	}
+
+	block.LiftedAt = &now
+	err = inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		return repos.Blocks.Update(block)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// patronStanding avalia se o usuário pode pegar livros emprestados em now:
+// ele não pode ter empréstimos em atraso, saldo devedor acima do limite da
+// política da sua categoria nem bloqueios manuais ativos
+func patronStanding(repos domain.Repositories, fallback domain.CirculationPolicy, user *domain.User, now time.Time) (*domain.PatronStanding, error) {
+	policy, err := policyFor(repos, fallback, user.ID, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	standing := &domain.PatronStanding{
+		UserID:  user.ID,
+		Reasons: []domain.StandingReason{},
+		MaxDebt: policy.MaxDebt,
+	}
+
+	loans, err := repos.Loans.GetLoansByUser(user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	for _, loan := range loans {
+		if !loan.IsReturned && now.After(loan.DueDate) {
+			standing.OverdueLoans++
+		}
+	}
+	if standing.OverdueLoans > 0 {
+		standing.Reasons = append(standing.Reasons, domain.StandingReason{
+			Code:    domain.StandingReasonOverdueLoans,
+			Message: fmt.Sprintf("%d empréstimo(s) em atraso", standing.OverdueLoans),
+			Params:  map[string]interface{}{"count": standing.OverdueLoans},
+		})
+	}
+
+	standing.Balance, err = repos.Accounts.GetBalance(user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if policy.MaxDebt > 0 && standing.Balance > policy.MaxDebt {
+		standing.Reasons = append(standing.Reasons, domain.StandingReason{
+			Code:    domain.StandingReasonDebtLimit,
+			Message: fmt.Sprintf("saldo devedor de %s acima do limite de %s", standing.Balance, policy.MaxDebt),
+			Params:  map[string]interface{}{"balance": standing.Balance, "max_debt": policy.MaxDebt},
+		})
+	}
+
+	standing.Blocks, err = repos.Blocks.GetActiveByUser(user.ID.String(), now)
+	if err != nil {
+		return nil, err
+	}
+	if standing.Blocks == nil {
+		standing.Blocks = []*domain.PatronBlock{}
+	}
+	for _, block := range standing.Blocks {
+		blockID := block.ID
+		message := "bloqueio manual: " + block.Reason
+		params := map[string]interface{}{"reason": block.Reason}
+		if block.ExpiresAt != nil {
+			message += fmt.Sprintf(" (até %s)", block.ExpiresAt.Format("02/01/2006"))
+			params["until"] = *block.ExpiresAt
+		}
+		standing.Reasons = append(standing.Reasons, domain.StandingReason{
+			Code:    domain.StandingReasonManualBlock,
+			Message: message,
+			Params:  params,
+			BlockID: &blockID,
+		})
+	}
+
+	standing.CanBorrow = len(standing.Reasons) == 0
+	return standing, nil
+}
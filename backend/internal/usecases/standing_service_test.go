@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"library-management/internal/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetStanding(t *testing.T) {
+	db := newTestDB(t)
+	service := newTestStandingService(db)
+	user := createUser(t, db, "bentinho@example.com")
+	id := user.ID.String()
+
+	standing, err := service.GetStanding(id)
+	if err != nil || !standing.CanBorrow {
+		t.Fatalf("situação sem pendências = %+v, %v, esperado liberado", standing, err)
+	}
+
+	checkout(t, db, createBook(t, db, "Dom Casmurro", 1).ID.String(), id, time.Now().Add(-time.Hour))
+	addAccountEntry(t, db, id, domain.DefaultCirculationPolicy().MaxDebt+1)
+	if _, err := service.BlockUser(context.Background(), id, "livro danificado", nil); err != nil {
+		t.Fatalf("erro ao bloquear: %v", err)
+	}
+
+	standing, err = service.GetStanding(id)
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	var codes []string
+	for _, reason := range standing.Reasons {
+		codes = append(codes, string(reason.Code))
+	}
+	want := []string{string(domain.StandingReasonOverdueLoans), string(domain.StandingReasonDebtLimit), string(domain.StandingReasonManualBlock)}
+	if standing.CanBorrow || !reflect.DeepEqual(codes, want) {
+		t.Errorf("motivos = %v, esperado %v", codes, want)
+	}
+}
+
+// TestGetStandingDuringWrite confere que a consulta da situação não espera
+// por uma transação de escrita aberta
+func TestGetStandingDuringWrite(t *testing.T) {
+	db := newTestDB(t)
+	service := newTestStandingService(db)
+	user := createUser(t, db, "bentinho@example.com")
+
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- service.uow.Do(func(repos domain.Repositories) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+	defer func() {
+		close(release)
+		<-done
+	}()
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := service.GetStanding(user.ID.String())
+		result <- err
+	}()
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Errorf("erro inesperado %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("GetStanding esperou pela transação de escrita")
+	}
+}
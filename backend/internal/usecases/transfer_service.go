@@ -0,0 +1,438 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"library-management/internal/domain"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Campos aceitos na importação de cada tipo de registro
+var (
+	bookImportFields = []string{"title", "author", "year_published", "isbn", "publisher", "cover_url", "subjects", "material_type", "copies"}
+	userImportFields = []string{"name", "email", "phone", "category", "role"}
+)
+
+// Colunas da exportação de cada tipo de registro, na ordem do arquivo
+var (
+	bookExportColumns = []string{"id", "title", "author", "year_published", "isbn", "isbn_display", "publisher",
+		"cover_url", "subjects", "material_type", "total_copies", "available_copies", "created_at", "updated_at"}
+	userExportColumns = []string{"id", "name", "email", "phone", "category", "role", "created_at"}
+)
+
+// errDryRun desfaz a transação de uma importação simulada
+var errDryRun = errors.New("importação simulada")
+
+// TransferService implementa a importação e a exportação em lote do acervo e
+// dos usuários. Os formatos de arquivo aceitos para livros e para usuários
+// são informados separadamente, pois os bibliográficos (MARC) só se aplicam
+// ao acervo.
+type TransferService struct {
+	bookRepo    domain.BookRepository
+	userRepo    domain.UserRepository
+	uow         domain.UnitOfWork
+	bookFormats map[string]domain.RecordFormat
+	userFormats map[string]domain.RecordFormat
+}
+
+// NewTransferService cria uma nova instância do TransferService
+func NewTransferService(bookRepo domain.BookRepository, userRepo domain.UserRepository, uow domain.UnitOfWork, bookFormats, userFormats map[string]domain.RecordFormat) *TransferService {
+	return &TransferService{
+		bookRepo:    bookRepo,
+		userRepo:    userRepo,
+		uow:         uow,
+		bookFormats: bookFormats,
+		userFormats: userFormats,
+	}
+}
+
+// BookFormat retorna o formato de arquivo de livros com o nome informado, ou ErrUnknownFormat
+func (s *TransferService) BookFormat(name string) (domain.RecordFormat, error) {
+	return findFormat(s.bookFormats, name)
+}
+
+// UserFormat retorna o formato de arquivo de usuários com o nome informado, ou ErrUnknownFormat
+func (s *TransferService) UserFormat(name string) (domain.RecordFormat, error) {
+	return findFormat(s.userFormats, name)
+}
+
+// ImportBooks importa livros de um arquivo. Livros com ISBN já cadastrado
+// são atualizados com os campos preenchidos no arquivo; os demais são
+// criados com a quantidade de exemplares da coluna copies (no mínimo um).
+func (s *TransferService) ImportBooks(ctx context.Context, r io.Reader, options domain.ImportOptions) (*domain.ImportReport, error) {
+	return s.importRecords(ctx, r, s.bookFormats, options, bookImportFields, importBook)
+}
+
+// ImportUsers importa usuários de um arquivo. Usuários com email já
+// cadastrado são atualizados com os campos preenchidos no arquivo.
+func (s *TransferService) ImportUsers(ctx context.Context, r io.Reader, options domain.ImportOptions) (*domain.ImportReport, error) {
+	return s.importRecords(ctx, r, s.userFormats, options, userImportFields, importUser)
+}
+
+// ExportBooks grava todos os livros no formato informado
+func (s *TransferService) ExportBooks(w io.Writer, format string) error {
+	recordFormat, err := s.BookFormat(format)
+	if err != nil {
+		return err
+	}
+
+	writer := recordFormat.NewWriter(w, bookExportColumns)
+	err = s.bookRepo.Each(func(book *domain.Book) error {
+		return writer.Write(map[string]interface{}{
+			"id":               book.ID,
+			"title":            book.Title,
+			"author":           book.Author,
+			"year_published":   book.YearPublished,
+			"isbn":             book.ISBN,
+			"isbn_display":     book.ISBNDisplay,
+			"publisher":        book.Publisher,
+			"cover_url":        book.CoverURL,
+			"subjects":         book.Subjects,
+			"material_type":    book.MaterialType,
+			"total_copies":     book.TotalCopies,
+			"available_copies": book.AvailableCopies,
+			"created_at":       book.CreatedAt,
+			"updated_at":       book.UpdatedAt,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// ExportUsers grava todos os usuários no formato informado
+func (s *TransferService) ExportUsers(w io.Writer, format string) error {
+	recordFormat, err := s.UserFormat(format)
+	if err != nil {
+		return err
+	}
+
+	writer := recordFormat.NewWriter(w, userExportColumns)
+	err = s.userRepo.Each(func(user *domain.User) error {
+		return writer.Write(map[string]interface{}{
+			"id":         user.ID,
+			"name":       user.Name,
+			"email":      user.Email,
+			"phone":      user.Phone,
+			"category":   user.Category,
+			"role":       user.Role,
+			"created_at": user.CreatedAt,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// findFormat retorna o formato com o nome informado; o erro lista os nomes aceitos
+func findFormat(formats map[string]domain.RecordFormat, name string) (domain.RecordFormat, error) {
+	recordFormat, ok := formats[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(formats))
+		for name := range formats {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, domain.ErrUnknownFormat.WithParams(map[string]interface{}{"formats": strings.Join(names, ", ")})
+	}
+	return recordFormat, nil
+}
+
+// importRecords lê o arquivo registro a registro e importa cada um com
+// importFn, tudo na mesma transação. O arquivo é copiado para o disco antes
+// de a transação começar, para que um envio lento não segure a trava de
+// escrita do banco. Cada registro roda em um savepoint: erros do domínio
+// desfazem só o que o registro já tinha gravado e entram no relatório;
+// qualquer outro erro (arquivo malformado, falha do banco) interrompe a
+// importação e desfaz tudo.
+func (s *TransferService) importRecords(ctx context.Context, r io.Reader, formats map[string]domain.RecordFormat, options domain.ImportOptions,
+	fields []string, importFn func(repos domain.Repositories, record *domain.Record) (bool, error)) (*domain.ImportReport, error) {
+	recordFormat, err := findFormat(formats, options.Format)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMapping(options.Mapping, fields); err != nil {
+		return nil, err
+	}
+
+	file, err := spool(r)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	report := &domain.ImportReport{DryRun: options.DryRun, Errors: []domain.ImportRowError{}, Unmapped: map[string]int{}}
+	err = inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		reader, err := recordFormat.NewReader(file, options.Mapping)
+		if err != nil {
+			return err
+		}
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			report.Total++
+			for _, name := range unmappedFields(record, fields) {
+				report.Unmapped[name]++
+			}
+			var created bool
+			err = repos.Savepoints.Do(func() error {
+				var err error
+				created, err = importFn(repos, record)
+				return err
+			})
+			var domainErr *domain.Error
+			switch {
+			case err == nil && created:
+				report.Created++
+			case err == nil:
+				report.Updated++
+			case errors.As(err, &domainErr):
+				report.Failed++
+				report.Errors = append(report.Errors, rowError(record.Row, domainErr))
+			default:
+				return err
+			}
+		}
+
+		if options.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return report, nil
+}
+
+// spool copia o arquivo enviado para um arquivo temporário, já removido do
+// diretório, e o retorna posicionado no início. O espaço é liberado quando o
+// arquivo é fechado.
+func spool(r io.Reader) (*os.File, error) {
+	file, err := os.CreateTemp("", "import-*")
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(file.Name())
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("erro ao receber o arquivo: %v", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// importBook cria ou atualiza (pelo ISBN) o livro do registro; retorna true
+// se o livro foi criado
+func importBook(repos domain.Repositories, record *domain.Record) (bool, error) {
+	fields := record.Fields
+	year, err := parseInt(fields, "year_published")
+	if err != nil {
+		return false, err
+	}
+	copies, err := parseInt(fields, "copies")
+	if err != nil {
+		return false, err
+	}
+
+	book := &domain.Book{}
+	if err := setISBN(book, fields["isbn"]); err != nil {
+		return false, err
+	}
+
+	if book.ISBN != "" {
+		existing, err := repos.Books.GetByISBN(book.ISBN)
+		if err == nil {
+			mergeString(&existing.Title, fields["title"])
+			mergeString(&existing.Author, fields["author"])
+			mergeString(&existing.Publisher, fields["publisher"])
+			mergeString(&existing.CoverURL, fields["cover_url"])
+			mergeString(&existing.MaterialType, fields["material_type"])
+			if subjects := splitSubjects(fields["subjects"]); len(subjects) > 0 {
+				existing.Subjects = subjects
+			}
+			if year > 0 {
+				existing.YearPublished = year
+			}
+			existing.ISBNDisplay = book.ISBNDisplay
+			existing.UpdatedAt = time.Now()
+			return false, repos.Books.Update(existing)
+		}
+		if !errors.Is(err, domain.ErrBookNotFound) {
+			return false, err
+		}
+	}
+
+	book.Title = strings.TrimSpace(fields["title"])
+	book.Author = strings.TrimSpace(fields["author"])
+	if err := validateBookFields(book.Title, book.Author); err != nil {
+		return false, err
+	}
+	book.YearPublished = year
+	book.Publisher = strings.TrimSpace(fields["publisher"])
+	book.CoverURL = strings.TrimSpace(fields["cover_url"])
+	book.Subjects = splitSubjects(fields["subjects"])
+	book.MaterialType = strings.TrimSpace(fields["material_type"])
+	if book.MaterialType == "" {
+		book.MaterialType = domain.DefaultMaterialType
+	}
+	book.CreatedAt = time.Now()
+	book.UpdatedAt = time.Now()
+	if copies <= 0 {
+		copies = 1
+	}
+
+	if err := repos.Books.Create(book); err != nil {
+		return false, err
+	}
+	for i := 0; i < copies; i++ {
+		if err := repos.Items.Create(newItem(book.ID, "", "")); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// importUser cria ou atualiza (pelo email) o usuário do registro; retorna
+// true se o usuário foi criado
+func importUser(repos domain.Repositories, record *domain.Record) (bool, error) {
+	fields := record.Fields
+	email := strings.TrimSpace(fields["email"])
+	if email == "" {
+		return false, domain.NewFieldError("email", "email_required", "email é obrigatório")
+	}
+	if err := validateEmail(email); err != nil {
+		return false, err
+	}
+
+	category := domain.PatronCategory(strings.TrimSpace(fields["category"]))
+	if category != "" && !category.IsValid() {
+		return false, errInvalidCategory
+	}
+	role := domain.Role(strings.TrimSpace(fields["role"]))
+	if role != "" && !role.IsValid() {
+		return false, errInvalidRole
+	}
+
+	user, err := repos.Users.GetByEmail(email)
+	if err == nil {
+		mergeString(&user.Name, fields["name"])
+		mergeString(&user.Phone, fields["phone"])
+		if category != "" {
+			user.Category = category
+		}
+		if role != "" {
+			user.Role = role
+		}
+		user.UpdatedAt = time.Now()
+		return false, repos.Users.Update(user)
+	}
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return false, err
+	}
+
+	user = &domain.User{
+		Name:      strings.TrimSpace(fields["name"]),
+		Email:     email,
+		Phone:     strings.TrimSpace(fields["phone"]),
+		Category:  domain.DefaultPatronCategory,
+		Role:      domain.DefaultRole,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if user.Name == "" {
+		return false, domain.NewFieldError("name", "name_required", "nome é obrigatório")
+	}
+	if category != "" {
+		user.Category = category
+	}
+	if role != "" {
+		user.Role = role
+	}
+	return true, repos.Users.Create(user)
+}
+
+// validateMapping confere se o mapeamento de colunas só usa campos conhecidos
+func validateMapping(mapping map[string]string, fields []string) error {
+	for field := range mapping {
+		if !hasField(fields, field) {
+			return domain.NewFieldError("map", "unknown_mapping_field",
+				fmt.Sprintf("campo desconhecido no mapeamento de colunas: %s (use %s)", field, strings.Join(fields, ", "))).
+				WithParams(map[string]interface{}{"field": field, "fields": strings.Join(fields, ", ")})
+		}
+	}
+	return nil
+}
+
+// unmappedFields retorna os campos do registro que não correspondem a
+// nenhum campo importável, incluindo os que o formato não soube converter
+func unmappedFields(record *domain.Record, fields []string) []string {
+	unmapped := record.Unmapped
+	for name := range record.Fields {
+		if !hasField(fields, name) {
+			unmapped = append(unmapped, name)
+		}
+	}
+	return unmapped
+}
+
+// hasField indica se name está na lista de campos
+func hasField(fields []string, name string) bool {
+	for _, field := range fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// splitSubjects separa os assuntos de um campo do arquivo
+func splitSubjects(value string) []string {
+	return normalizeSubjects(strings.Split(value, domain.ValueSeparator))
+}
+
+// rowError converte o erro de um registro em um item do relatório
+func rowError(row int, err *domain.Error) domain.ImportRowError {
+	rowErr := domain.ImportRowError{Row: row, Code: err.Code, Message: err.Message, Params: err.Params}
+	if len(err.Fields) > 0 {
+		rowErr.Field = err.Fields[0].Field
+	}
+	return rowErr
+}
+
+// parseInt lê um campo numérico opcional do registro (vazio é zero)
+func parseInt(fields map[string]string, name string) (int, error) {
+	value := strings.TrimSpace(fields[name])
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, domain.NewFieldError(name, "invalid_number", "valor deve ser um número inteiro")
+	}
+	return n, nil
+}
+
+// mergeString substitui o valor atual pelo do arquivo, se preenchido
+func mergeString(target *string, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		*target = value
+	}
+}
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"library-management/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// booksCSV mistura linhas válidas e inválidas; a última atualiza o livro da
+// terceira pelo ISBN
+const booksCSV = `title,author,isbn,copies
+Dom Casmurro,Machado de Assis,,2
+,Autor sem título,,1
+Helena,Machado de Assis,9788535902778,1
+Iracema,José de Alencar,123,1
+Helena (2ª edição),,978-85-359-0277-8,
+`
+
+func TestImportBooks(t *testing.T) {
+	db := newTestDB(t)
+	service := newTestTransferService(db)
+
+	report, err := service.ImportBooks(context.Background(), strings.NewReader(booksCSV), domain.ImportOptions{Format: "csv"})
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if report.Total != 5 || report.Created != 2 || report.Updated != 1 || report.Failed != 2 {
+		t.Errorf("relatório = %+v, esperado 5 registros: 2 criados, 1 atualizado e 2 recusados", report)
+	}
+	var rows []int
+	for _, rowErr := range report.Errors {
+		rows = append(rows, rowErr.Row)
+	}
+	if !reflect.DeepEqual(rows, []int{3, 5}) {
+		t.Errorf("linhas recusadas = %v, esperado [3 5]", rows)
+	}
+
+	if books, items := count(t, db, "books"), count(t, db, "items"); books != 2 || items != 3 {
+		t.Errorf("%d livros e %d exemplares gravados, esperado 2 e 3", books, items)
+	}
+	var title string
+	if err := db.QueryRow(`SELECT title FROM books WHERE isbn = '9788535902778'`).Scan(&title); err != nil || title != "Helena (2ª edição)" {
+		t.Errorf("título do livro atualizado = %q, %v, esperado Helena (2ª edição)", title, err)
+	}
+}
+
+func TestImportBooksDryRun(t *testing.T) {
+	db := newTestDB(t)
+	service := newTestTransferService(db)
+
+	report, err := service.ImportBooks(context.Background(), strings.NewReader(booksCSV), domain.ImportOptions{Format: "csv", DryRun: true})
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if !report.DryRun || report.Created != 2 || report.Failed != 2 {
+		t.Errorf("relatório = %+v, esperado a simulação com 2 criados e 2 recusados", report)
+	}
+	if books := count(t, db, "books"); books != 0 {
+		t.Errorf("%d livros gravados na simulação, esperado 0", books)
+	}
+}
+
+// TestImportRowRollback confere que um registro recusado depois de gravar
+// parte dos dados não deixa essa parte na importação
+func TestImportRowRollback(t *testing.T) {
+	db := newTestDB(t)
+	service := newTestTransferService(db)
+
+	// Grava o livro e recusa o registro se o título pedir, como uma falha
+	// na criação dos exemplares
+	importFn := func(repos domain.Repositories, record *domain.Record) (bool, error) {
+		book := &domain.Book{Title: record.Fields["title"], Author: "Machado de Assis", MaterialType: domain.DefaultMaterialType}
+		if err := repos.Books.Create(book); err != nil {
+			return false, err
+		}
+		if strings.HasPrefix(book.Title, "falha") {
+			return false, domain.NewValidation("item_failed", "erro ao criar os exemplares")
+		}
+		return true, nil
+	}
+
+	input := "title\nDom Casmurro\nfalha 1\nHelena\nfalha 2\n"
+	report, err := service.importRecords(context.Background(), strings.NewReader(input), service.bookFormats,
+		domain.ImportOptions{Format: "csv"}, bookImportFields, importFn)
+	if err != nil {
+		t.Fatalf("erro inesperado %v", err)
+	}
+	if report.Created != 2 || report.Failed != 2 {
+		t.Errorf("relatório = %+v, esperado 2 criados e 2 recusados", report)
+	}
+
+	var titles []string
+	rows, err := db.Query(`SELECT title FROM books ORDER BY title`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var title string
+		if err := rows.Scan(&title); err != nil {
+			t.Fatal(err)
+		}
+		titles = append(titles, title)
+	}
+	if !reflect.DeepEqual(titles, []string{"Dom Casmurro", "Helena"}) {
+		t.Errorf("livros gravados = %v, esperado só os dos registros aceitos", titles)
+	}
+	if audits := count(t, db, "audit_log"); audits != 2 {
+		t.Errorf("%d registros de auditoria, esperado 2", audits)
+	}
+}
@@ -1,57 +1,83 @@
 package usecases
 
 import (
+	"context"
 	"errors"
 	"library-management/internal/domain"
 	"regexp"
 	"time"
 )
 
+// emailRegex é o formato aceito para o email dos usuários
+var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
+// Erros de validação dos campos de usuário
+var (
+	errInvalidCategory = domain.NewFieldError("category", "invalid_category", "categoria de usuário inválida")
+	errInvalidRole     = domain.NewFieldError("role", "invalid_role", "papel de usuário inválido")
+)
+
 // UserService implementa os casos de uso para usuários
 type UserService struct {
 	userRepo domain.UserRepository
-	loanRepo domain.LoanRepository
+	uow      domain.UnitOfWork
 }
 
 // NewUserService cria uma nova instância do UserService
-func NewUserService(userRepo domain.UserRepository, loanRepo domain.LoanRepository) *UserService {
+func NewUserService(userRepo domain.UserRepository, uow domain.UnitOfWork) *UserService {
 	return &UserService{
 		userRepo: userRepo,
-		loanRepo: loanRepo,
+		uow:      uow,
 	}
 }
 
 // CreateUser cria um novo usuário
-func (s *UserService) CreateUser(name, email, phone string) (*domain.User, error) {
+func (s *UserService) CreateUser(ctx context.Context, name, email, phone, category, role string) (*domain.User, error) {
 	if name == "" {
-		return nil, errors.New("nome é obrigatório")
+		return nil, domain.NewFieldError("name", "name_required", "nome é obrigatório")
 	}
 	if email == "" {
-		return nil, errors.New("email é obrigatório")
+		return nil, domain.NewFieldError("email", "email_required", "email é obrigatório")
 	}
-
-	// Validar formato do email
-	emailRegex := `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
-	match, _ := regexp.MatchString(emailRegex, email)
-	if !match {
-		return nil, errors.New("formato de email inválido")
+	if err := validateEmail(email); err != nil {
+		return nil, err
 	}
 
 	// Verificar se email já existe
 	existingUser, _ := s.userRepo.GetByEmail(email)
 	if existingUser != nil {
-		return nil, errors.New("email já está em uso")
+		return nil, domain.ErrEmailTaken
+	}
+
+	patronCategory := domain.DefaultPatronCategory
+	if category != "" {
+		patronCategory = domain.PatronCategory(category)
+		if !patronCategory.IsValid() {
+			return nil, errInvalidCategory
+		}
+	}
+
+	userRole := domain.DefaultRole
+	if role != "" {
+		userRole = domain.Role(role)
+		if !userRole.IsValid() {
+			return nil, errInvalidRole
+		}
 	}
 
 	user := &domain.User{
 		Name:      name,
 		Email:     email,
 		Phone:     phone,
+		Category:  patronCategory,
+		Role:      userRole,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	err := s.userRepo.Create(user)
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		return repos.Users.Create(user)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -59,9 +85,23 @@ func (s *UserService) CreateUser(name, email, phone string) (*domain.User, error
 	return user, nil
 }
 
-// GetAllUsers retorna todos os usuários
-func (s *UserService) GetAllUsers() ([]*domain.User, error) {
-	return s.userRepo.GetAll()
+// ListUsers retorna uma página dos usuários que atendem aos filtros e o total de usuários encontrados
+func (s *UserService) ListUsers(query domain.UserQuery) ([]*domain.User, int, error) {
+	if query.Category != "" && !query.Category.IsValid() {
+		return nil, 0, errInvalidCategory
+	}
+	if query.Role != "" && !query.Role.IsValid() {
+		return nil, 0, errInvalidRole
+	}
+
+	users, total, err := s.userRepo.List(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	if users == nil {
+		users = []*domain.User{}
+	}
+	return users, total, nil
 }
 
 // GetUserByID retorna um usuário pelo ID
@@ -69,8 +109,13 @@ func (s *UserService) GetUserByID(id string) (*domain.User, error) {
 	return s.userRepo.GetByID(id)
 }
 
+// GetUserByIDWithDeleted retorna um usuário pelo ID, mesmo que removido
+func (s *UserService) GetUserByIDWithDeleted(id string) (*domain.User, error) {
+	return s.userRepo.GetByIDWithDeleted(id)
+}
+
 // UpdateUser atualiza um usuário existente
-func (s *UserService) UpdateUser(id, name, email, phone string) (*domain.User, error) {
+func (s *UserService) UpdateUser(ctx context.Context, id, name, email, phone, category, role string) (*domain.User, error) {
 	user, err := s.userRepo.GetByID(id)
 	if err != nil {
 		return nil, err
@@ -80,24 +125,37 @@ func (s *UserService) UpdateUser(id, name, email, phone string) (*domain.User, e
 		user.Name = name
 	}
 	if email != "" {
-		// Validar formato do email
-		emailRegex := `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
-		match, _ := regexp.MatchString(emailRegex, email)
-		if !match {
-			return nil, errors.New("formato de email inválido")
+		if err := validateEmail(email); err != nil {
+			return nil, err
 		}
 
 		// Verificar se email já existe (diferente do usuário atual)
 		existingUser, _ := s.userRepo.GetByEmail(email)
 		if existingUser != nil && existingUser.ID != user.ID {
-			return nil, errors.New("email já está em uso")
+			return nil, domain.ErrEmailTaken
 		}
 		user.Email = email
 	}
 	user.Phone = phone
+	if category != "" {
+		patronCategory := domain.PatronCategory(category)
+		if !patronCategory.IsValid() {
+			return nil, errInvalidCategory
+		}
+		user.Category = patronCategory
+	}
+	if role != "" {
+		userRole := domain.Role(role)
+		if !userRole.IsValid() {
+			return nil, errInvalidRole
+		}
+		user.Role = userRole
+	}
 	user.UpdatedAt = time.Now()
 
-	err = s.userRepo.Update(user)
+	err = inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		return repos.Users.Update(user)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -105,19 +163,107 @@ func (s *UserService) UpdateUser(id, name, email, phone string) (*domain.User, e
 	return user, nil
 }
 
-// DeleteUser remove um usuário
-func (s *UserService) DeleteUser(id string) error {
-	// Verificar se o usuário tem empréstimos ativos
-	activeLoans, err := s.loanRepo.GetLoansByUser(id)
-	if err != nil {
-		return err
-	}
+// DeleteUser remove logicamente um usuário com a conta zerada: ele deixa de
+// aparecer e de entrar no sistema, com as sessões revogadas, mas o histórico
+// de empréstimos e a conta continuam apontando para ele até o expurgo
+func (s *UserService) DeleteUser(ctx context.Context, id string) error {
+	return inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		user, err := repos.Users.GetByID(id)
+		if err != nil {
+			return err
+		}
+
+		// Verificar se o usuário tem empréstimos ativos
+		activeLoans, err := repos.Loans.GetLoansByUser(id)
+		if err != nil {
+			return err
+		}
+
+		for _, loan := range activeLoans {
+			if !loan.IsReturned {
+				return domain.NewConflict("delete_user_has_loans", "não é possível deletar um usuário com empréstimos ativos")
+			}
+		}
+
+		// Uma reserva ativa de um usuário removido bloquearia a fila do livro
+		reservations, err := repos.Reservations.GetByUser(id)
+		if err != nil {
+			return err
+		}
+
+		for _, reservation := range reservations {
+			if reservation.IsActive() {
+				return domain.NewConflict("delete_user_has_holds", "não é possível deletar um usuário com reservas ativas")
+			}
+		}
+
+		// O saldo, devedor ou credor, precisa ser acertado antes da remoção
+		balance, err := repos.Accounts.GetBalance(id)
+		if err != nil {
+			return err
+		}
+		if balance != 0 {
+			return domain.NewConflict("delete_user_has_balance", "não é possível deletar um usuário com saldo na conta").
+				WithParams(map[string]interface{}{"balance": balance})
+		}
+
+		now := time.Now()
+		user.DeletedAt = &now
+		user.UpdatedAt = now
+		if err := repos.Users.Update(user); err != nil {
+			return err
+		}
+		return repos.Sessions.RevokeByUser(id, "", now)
+	})
+}
 
-	for _, loan := range activeLoans {
-		if !loan.IsReturned {
-			return errors.New("não é possível deletar um usuário com empréstimos ativos")
+// RestoreUser desfaz a remoção de um usuário ainda não expurgado, se nenhum
+// usuário ativo tiver passado a usar o email dele. As sessões revogadas na
+// remoção continuam revogadas.
+func (s *UserService) RestoreUser(ctx context.Context, id string) (*domain.User, error) {
+	var user *domain.User
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		var err error
+		user, err = repos.Users.GetByIDWithDeleted(id)
+		if err != nil {
+			return err
+		}
+		if user.DeletedAt == nil {
+			return domain.ErrUserNotDeleted
+		}
+		if other, err := repos.Users.GetByEmail(user.Email); err == nil {
+			return domain.ErrRestoreEmailTaken.WithParams(map[string]interface{}{"user_id": other.ID})
+		} else if !errors.Is(err, domain.ErrUserNotFound) {
+			return err
 		}
+
+		user.DeletedAt = nil
+		user.UpdatedAt = time.Now()
+		return repos.Users.Update(user)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return user, nil
+}
+
+// PurgeUsers apaga de vez os usuários removidos antes de deletedBefore, com
+// tudo o que pertence a eles, e retorna quantos foram apagados. Os usuários
+// com saldo na conta, devedor ou credor, são mantidos.
+func (s *UserService) PurgeUsers(ctx context.Context, deletedBefore time.Time) (int, error) {
+	var ids []string
+	err := inTx(ctx, s.uow, func(repos domain.Repositories) error {
+		var err error
+		ids, err = repos.Users.Purge(deletedBefore)
+		return err
+	})
+	return len(ids), err
+}
 
-	return s.userRepo.Delete(id)
+// validateEmail confere o formato do email
+func validateEmail(email string) error {
+	if !emailRegex.MatchString(email) {
+		return domain.NewFieldError("email", "invalid_email", "formato de email inválido")
+	}
+	return nil
 }
@@ -0,0 +1,78 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"library-management/internal/domain"
+	"testing"
+	"time"
+)
+
+// TestDeletedUserEmail confere que o email de um usuário removido pode ser
+// usado por outro usuário, e que então o removido não pode ser restaurado
+func TestDeletedUserEmail(t *testing.T) {
+	ctx := context.Background()
+	service := newTestUserService(newTestDB(t))
+
+	deleted, err := service.CreateUser(ctx, "Capitu", "capitu@example.com", "", "", "")
+	if err != nil {
+		t.Fatalf("erro ao criar o usuário: %v", err)
+	}
+	if err := service.DeleteUser(ctx, deleted.ID.String()); err != nil {
+		t.Fatalf("erro ao remover o usuário: %v", err)
+	}
+
+	active, err := service.CreateUser(ctx, "Capitolina", "capitu@example.com", "", "", "")
+	if err != nil {
+		t.Fatalf("erro ao criar outro usuário com o email do removido: %v", err)
+	}
+	if _, err := service.CreateUser(ctx, "Capitu", "capitu@example.com", "", "", ""); !errors.Is(err, domain.ErrEmailTaken) {
+		t.Errorf("email de um usuário ativo: erro = %v, esperado %v", err, domain.ErrEmailTaken)
+	}
+
+	if _, err := service.RestoreUser(ctx, deleted.ID.String()); !errors.Is(err, domain.ErrRestoreEmailTaken) {
+		t.Fatalf("restauração: erro = %v, esperado %v", err, domain.ErrRestoreEmailTaken)
+	}
+
+	if err := service.DeleteUser(ctx, active.ID.String()); err != nil {
+		t.Fatalf("erro ao remover o outro usuário: %v", err)
+	}
+	if _, err := service.RestoreUser(ctx, deleted.ID.String()); err != nil {
+		t.Errorf("restauração com o email livre: erro inesperado %v", err)
+	}
+}
+
+// TestUserBalance confere que um usuário com saldo na conta não é removido
+// nem expurgado
+func TestUserBalance(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	service := newTestUserService(db)
+
+	user, err := service.CreateUser(ctx, "Escobar", "escobar@example.com", "", "", "")
+	if err != nil {
+		t.Fatalf("erro ao criar o usuário: %v", err)
+	}
+	id := user.ID.String()
+
+	addAccountEntry(t, db, id, 500)
+	var domainErr *domain.Error
+	if err := service.DeleteUser(ctx, id); !errors.As(err, &domainErr) || domainErr.Code != "delete_user_has_balance" {
+		t.Fatalf("remoção com saldo devedor: erro = %v, esperado delete_user_has_balance", err)
+	}
+	addAccountEntry(t, db, id, -500)
+	if err := service.DeleteUser(ctx, id); err != nil {
+		t.Fatalf("remoção com a conta zerada: erro inesperado %v", err)
+	}
+
+	// Um lançamento depois da remoção impede o expurgo até ser acertado
+	cutoff := time.Now().Add(time.Hour)
+	addAccountEntry(t, db, id, -100)
+	if purged, err := service.PurgeUsers(ctx, cutoff); err != nil || purged != 0 {
+		t.Errorf("expurgo com saldo credor = %d, %v, esperado 0", purged, err)
+	}
+	addAccountEntry(t, db, id, 100)
+	if purged, err := service.PurgeUsers(ctx, cutoff); err != nil || purged != 1 {
+		t.Errorf("expurgo com a conta zerada = %d, %v, esperado 1", purged, err)
+	}
+}
@@ -1,8 +1,30 @@
-import React from 'react';
+import React, { useEffect, useState } from 'react';
 import { Layout } from './components/Layout';
+import LoginForm from './components/auth/LoginForm';
+import { User } from './types';
+import { authApi, setSessionExpiredHandler, tokenStorage } from './services/api';
 
 const App: React.FC = () => {
-  return <Layout />;
+  const [user, setUser] = useState<User | null>(() => tokenStorage.get()?.user ?? null);
+
+  useEffect(() => {
+    setSessionExpiredHandler(() => setUser(null));
+  }, []);
+
+  const handleLogout = async () => {
+    try {
+      await authApi.logout();
+    } finally {
+      tokenStorage.clear();
+      setUser(null);
+    }
+  };
+
+  if (!user) {
+    return <LoginForm onLogin={(tokens) => setUser(tokens.user)} />;
+  }
+
+  return <Layout user={user} onLogout={handleLogout} />;
 };
 
 export default App;
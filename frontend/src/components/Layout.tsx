@@ -4,8 +4,14 @@ import BooksTab from './tabs/BooksTab';
 import UsersTab from './tabs/UsersTab';
 import LoansTab from './tabs/LoansTab';
 import ReportsTab from './tabs/ReportsTab';
+import { User } from '../types';
 
-export const Layout: React.FC = () => {
+interface LayoutProps {
+  user: User;
+  onLogout: () => void;
+}
+
+export const Layout: React.FC<LayoutProps> = ({ user, onLogout }) => {
   const [activeTab, setActiveTab] = useState('books');
 
   const tabs = [
@@ -75,8 +81,11 @@ export const Layout: React.FC = () => {
                 Sistema de Biblioteca
               </h1>
             </div>
-            <div className="text-sm text-gray-500">
-              Gerenciamento de Empréstimos
+            <div className="flex items-center space-x-4 text-sm text-gray-500">
+              <span>{user.name}</span>
+              <button onClick={onLogout} className="btn-secondary">
+                Sair
+              </button>
             </div>
           </div>
         </div>
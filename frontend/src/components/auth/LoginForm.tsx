@@ -0,0 +1,68 @@
+import React, { useState } from 'react';
+import { AuthTokens } from '../../types';
+import { authApi, tokenStorage } from '../../services/api';
+
+interface LoginFormProps {
+  onLogin: (tokens: AuthTokens) => void;
+}
+
+const LoginForm: React.FC<LoginFormProps> = ({ onLogin }) => {
+  const [email, setEmail] = useState('');
+  const [password, setPassword] = useState('');
+  const [error, setError] = useState<string | null>(null);
+  const [loading, setLoading] = useState(false);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await authApi.login({ email, password });
+      tokenStorage.set(response.data);
+      onLogin(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Erro ao entrar');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="min-h-screen bg-gray-50 flex items-center justify-center">
+      <form onSubmit={handleSubmit} className="bg-white shadow-sm rounded-lg p-8 w-full max-w-sm space-y-4">
+        <h1 className="text-2xl font-bold text-gray-900 text-center">Sistema de Biblioteca</h1>
+
+        <div className="form-group">
+          <label className="form-label">Email</label>
+          <input
+            type="email"
+            value={email}
+            onChange={(e) => setEmail(e.target.value)}
+            className="input-field"
+            placeholder="exemplo@email.com"
+            required
+          />
+        </div>
+
+        <div className="form-group">
+          <label className="form-label">Senha</label>
+          <input
+            type="password"
+            value={password}
+            onChange={(e) => setPassword(e.target.value)}
+            className="input-field"
+            required
+          />
+        </div>
+
+        {error && <p className="text-red-500 text-sm">{error}</p>}
+
+        <button type="submit" className="btn-primary w-full" disabled={loading}>
+          {loading ? 'Entrando...' : 'Entrar'}
+        </button>
+      </form>
+    </div>
+  );
+};
+
+export default LoginForm;
@@ -1,5 +1,6 @@
 import React, { useState, useEffect } from 'react';
 import { Book, CreateBookRequest } from '../../types';
+import { booksApi } from '../../services/api';
 
 interface BookFormProps {
   book?: Book;
@@ -13,8 +14,11 @@ const BookForm: React.FC<BookFormProps> = ({ book, onSubmit, onCancel }) => {
     author: '',
     year_published: new Date().getFullYear(),
     isbn: '',
+    publisher: '',
+    cover_url: '',
   });
   const [errors, setErrors] = useState<Record<string, string>>({});
+  const [lookingUp, setLookingUp] = useState(false);
 
   useEffect(() => {
     if (book) {
@@ -22,7 +26,9 @@ const BookForm: React.FC<BookFormProps> = ({ book, onSubmit, onCancel }) => {
         title: book.title,
         author: book.author,
         year_published: book.year_published,
-        isbn: book.isbn || '',
+        isbn: book.isbn_display || book.isbn || '',
+        publisher: book.publisher || '',
+        cover_url: book.cover_url || '',
       });
     }
   }, [book]);
@@ -60,6 +66,28 @@ const BookForm: React.FC<BookFormProps> = ({ book, onSubmit, onCancel }) => {
     }
   };
 
+  // Preenche o formulário com os dados do ISBN obtidos pelo servidor
+  const handleLookup = async () => {
+    if (!formData.isbn?.trim()) return;
+    try {
+      setLookingUp(true);
+      const { data } = await booksApi.getMetadata(formData.isbn);
+      setFormData(prev => ({
+        ...prev,
+        title: data.title || prev.title,
+        author: data.authors?.join(', ') || prev.author,
+        year_published: data.year_published || prev.year_published,
+        publisher: data.publisher || prev.publisher,
+        cover_url: data.cover_url || prev.cover_url,
+      }));
+      setErrors({});
+    } catch (err: any) {
+      setErrors(prev => ({ ...prev, isbn: err.response?.data?.error || 'Erro ao buscar dados do ISBN' }));
+    } finally {
+      setLookingUp(false);
+    }
+  };
+
   return (
     <form onSubmit={handleSubmit} className="space-y-4">
       <div className="form-group">
@@ -102,12 +130,43 @@ const BookForm: React.FC<BookFormProps> = ({ book, onSubmit, onCancel }) => {
 
       <div className="form-group">
         <label className="form-label">ISBN (opcional)</label>
+        <div className="flex space-x-2">
+          <input
+            type="text"
+            value={formData.isbn}
+            onChange={(e) => handleChange('isbn', e.target.value)}
+            className={`input-field flex-1 ${errors.isbn ? 'border-red-500' : ''}`}
+            placeholder="Ex: 978-3-16-148410-0"
+          />
+          <button
+            type="button"
+            onClick={handleLookup}
+            disabled={lookingUp || !formData.isbn?.trim()}
+            className="btn-secondary"
+          >
+            {lookingUp ? 'Buscando...' : 'Buscar dados'}
+          </button>
+        </div>
+        {errors.isbn && <p className="text-red-500 text-sm mt-1">{errors.isbn}</p>}
+      </div>
+
+      <div className="form-group">
+        <label className="form-label">Editora (opcional)</label>
         <input
           type="text"
-          value={formData.isbn}
-          onChange={(e) => handleChange('isbn', e.target.value)}
+          value={formData.publisher}
+          onChange={(e) => handleChange('publisher', e.target.value)}
+          className="input-field"
+        />
+      </div>
+
+      <div className="form-group">
+        <label className="form-label">URL da capa (opcional)</label>
+        <input
+          type="url"
+          value={formData.cover_url}
+          onChange={(e) => handleChange('cover_url', e.target.value)}
           className="input-field"
-          placeholder="Ex: 978-3-16-148410-0"
         />
       </div>
 
@@ -6,7 +6,8 @@ import { Card, StatusBadge } from '../common/UI';
 import BookForm from './BookForm';
 
 const BooksTab: React.FC = () => {
-  const { books, loading, error, createBook, updateBook, deleteBook } = useBooks();
+  const { books, total, loading, error, searchBooks, createBook, updateBook, deleteBook } = useBooks();
+  const [search, setSearch] = useState('');
   const [showCreateModal, setShowCreateModal] = useState(false);
   const [editingBook, setEditingBook] = useState<Book | null>(null);
   const [deleteConfirm, setDeleteConfirm] = useState<{ show: boolean; book: Book | null }>({
@@ -47,6 +48,11 @@ const BooksTab: React.FC = () => {
     }
   };
 
+  const handleSearch = (e: React.FormEvent) => {
+    e.preventDefault();
+    searchBooks(search);
+  };
+
   const openDeleteConfirm = (book: Book) => {
     setDeleteConfirm({ show: true, book });
   };
@@ -63,7 +69,10 @@ const BooksTab: React.FC = () => {
 
       <Card>
         <div className="flex justify-between items-center mb-4">
-          <h2 className="text-xl font-semibold text-gray-900">Gerenciar Livros</h2>
+          <div>
+            <h2 className="text-xl font-semibold text-gray-900">Gerenciar Livros</h2>
+            <p className="text-sm text-gray-500">{total} livro(s)</p>
+          </div>
           <button
             onClick={() => setShowCreateModal(true)}
             className="btn-primary"
@@ -75,6 +84,19 @@ const BooksTab: React.FC = () => {
           </button>
         </div>
 
+        <form onSubmit={handleSearch} className="flex space-x-2 mb-4">
+          <input
+            type="search"
+            value={search}
+            onChange={(e) => setSearch(e.target.value)}
+            placeholder="Buscar por título, autor ou ISBN"
+            className="input-field flex-1"
+          />
+          <button type="submit" className="btn-secondary">
+            Buscar
+          </button>
+        </form>
+
         {loading ? (
           <LoadingSpinner />
         ) : error ? (
@@ -98,7 +120,7 @@ const BooksTab: React.FC = () => {
                     <td className="table-cell font-medium">{book.title}</td>
                     <td className="table-cell">{book.author}</td>
                     <td className="table-cell">{book.year_published || '-'}</td>
-                    <td className="table-cell">{book.isbn || '-'}</td>
+                    <td className="table-cell">{book.isbn_display || book.isbn || '-'}</td>
                     <td className="table-cell">
                       <StatusBadge status={book.is_available ? 'available' : 'unavailable'} />
                     </td>
@@ -125,7 +147,9 @@ const BooksTab: React.FC = () => {
             
             {books.length === 0 && (
               <div className="text-center py-8 text-gray-500">
-                Nenhum livro cadastrado. Clique em "Novo Livro" para começar.
+                {search.trim()
+                  ? 'Nenhum livro encontrado.'
+                  : 'Nenhum livro cadastrado. Clique em "Novo Livro" para começar.'}
               </div>
             )}
           </div>
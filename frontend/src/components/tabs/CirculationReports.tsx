@@ -0,0 +1,202 @@
+import React, { useState } from 'react';
+import { reportsApi } from '../../services/api';
+import { Alert, LoadingSpinner } from '../common/Modal';
+import { Card } from '../common/UI';
+import { Report, ReportInterval, ReportName, ReportValue } from '../../types';
+
+const reports: { name: ReportName; label: string; periodic?: boolean; ranking?: boolean }[] = [
+  { name: 'loans-per-period', label: 'Empréstimos por período', periodic: true },
+  { name: 'top-titles', label: 'Títulos mais emprestados', ranking: true },
+  { name: 'top-authors', label: 'Autores mais emprestados', ranking: true },
+  { name: 'active-patrons', label: 'Leitores mais ativos', ranking: true },
+  { name: 'overdue-rate', label: 'Taxa de atraso', periodic: true },
+  { name: 'loan-duration', label: 'Duração dos empréstimos', periodic: true },
+  { name: 'never-borrowed', label: 'Livros sem empréstimos (descarte)' },
+];
+
+const intervals: { value: ReportInterval; label: string }[] = [
+  { value: 'day', label: 'Dia' },
+  { value: 'week', label: 'Semana' },
+  { value: 'month', label: 'Mês' },
+  { value: 'year', label: 'Ano' },
+];
+
+const columnLabels: Record<string, string> = {
+  period: 'Período',
+  loans: 'Empréstimos',
+  patrons: 'Leitores',
+  returned: 'Devolvidos',
+  title: 'Título',
+  author: 'Autor',
+  titles: 'Títulos',
+  name: 'Nome',
+  email: 'Email',
+  category: 'Categoria',
+  last_loan: 'Último empréstimo',
+  overdue: 'Atrasados',
+  overdue_rate: 'Taxa de atraso (%)',
+  average_days: 'Média (dias)',
+  min_days: 'Mínimo (dias)',
+  max_days: 'Máximo (dias)',
+  year_published: 'Ano',
+  material_type: 'Tipo',
+  copies: 'Exemplares',
+  created_at: 'Cadastrado em',
+};
+
+// Os identificadores ficam só no CSV
+const hiddenColumns = ['book_id', 'user_id'];
+
+const formatValue = (column: string, value: ReportValue) => {
+  if (value === null || value === '') return '-';
+  if (column === 'last_loan' || column === 'created_at') {
+    return new Date(value as string).toLocaleDateString('pt-BR');
+  }
+  return typeof value === 'number' ? value.toLocaleString('pt-BR') : value;
+};
+
+// Estatísticas de circulação calculadas pela API, com exportação em CSV
+const CirculationReports: React.FC = () => {
+  const [name, setName] = useState<ReportName>('loans-per-period');
+  const [from, setFrom] = useState('');
+  const [to, setTo] = useState('');
+  const [interval, setReportInterval] = useState<ReportInterval>('month');
+  const [limit, setLimit] = useState(10);
+  const [report, setReport] = useState<Report | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  const selected = reports.find((r) => r.name === name)!;
+
+  const params = () => ({
+    ...(from ? { from } : {}),
+    ...(to ? { to } : {}),
+    ...(selected.periodic ? { interval } : {}),
+    ...(selected.ranking ? { limit } : {}),
+  });
+
+  const generate = async () => {
+    setLoading(true);
+    setError(null);
+    try {
+      const response = await reportsApi.get(name, params());
+      setReport(response.data);
+    } catch (err: any) {
+      setReport(null);
+      setError(err.response?.data?.error || 'Erro ao gerar relatório');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const exportCsv = async () => {
+    try {
+      const response = await reportsApi.exportCsv(name, params());
+      const url = URL.createObjectURL(response.data);
+      const link = document.createElement('a');
+      link.href = url;
+      link.download = `relatorio-${name}.csv`;
+      document.body.appendChild(link);
+      link.click();
+      document.body.removeChild(link);
+      URL.revokeObjectURL(url);
+    } catch {
+      setError('Erro ao exportar relatório');
+    }
+  };
+
+  const columns = report?.columns.filter((c) => !hiddenColumns.includes(c)) || [];
+
+  return (
+    <Card>
+      <h2 className="text-xl font-semibold text-gray-900 mb-6">Estatísticas de Circulação</h2>
+
+      <div className="grid grid-cols-1 md:grid-cols-5 gap-4 items-end">
+        <div className="md:col-span-2">
+          <label className="block text-sm font-medium text-gray-700 mb-1">Relatório</label>
+          <select value={name} onChange={(e) => setName(e.target.value as ReportName)} className="input-field">
+            {reports.map((r) => (
+              <option key={r.name} value={r.name}>{r.label}</option>
+            ))}
+          </select>
+        </div>
+        <div>
+          <label className="block text-sm font-medium text-gray-700 mb-1">De</label>
+          <input type="date" value={from} onChange={(e) => setFrom(e.target.value)} className="input-field" />
+        </div>
+        <div>
+          <label className="block text-sm font-medium text-gray-700 mb-1">Até (exclusive)</label>
+          <input type="date" value={to} onChange={(e) => setTo(e.target.value)} className="input-field" />
+        </div>
+        {selected.periodic && (
+          <div>
+            <label className="block text-sm font-medium text-gray-700 mb-1">Agrupar por</label>
+            <select value={interval} onChange={(e) => setReportInterval(e.target.value as ReportInterval)} className="input-field">
+              {intervals.map((i) => (
+                <option key={i.value} value={i.value}>{i.label}</option>
+              ))}
+            </select>
+          </div>
+        )}
+        {selected.ranking && (
+          <div>
+            <label className="block text-sm font-medium text-gray-700 mb-1">Quantidade</label>
+            <input
+              type="number"
+              min={1}
+              value={limit}
+              onChange={(e) => setLimit(Number(e.target.value))}
+              className="input-field"
+            />
+          </div>
+        )}
+      </div>
+
+      <div className="flex gap-3 mt-4">
+        <button onClick={generate} className="btn-primary" disabled={loading}>
+          Gerar Relatório
+        </button>
+        {report && report.rows.length > 0 && (
+          <button onClick={exportCsv} className="btn-success">
+            Exportar CSV
+          </button>
+        )}
+      </div>
+
+      {error && <div className="mt-4"><Alert type="error" message={error} /></div>}
+
+      {loading ? (
+        <LoadingSpinner />
+      ) : report && (
+        report.rows.length === 0 ? (
+          <div className="text-center py-8 text-gray-500">
+            Nenhum dado encontrado para o período selecionado.
+          </div>
+        ) : (
+          <div className="table-container mt-4">
+            <table className="table">
+              <thead className="table-header">
+                <tr>
+                  {columns.map((column) => (
+                    <th key={column}>{columnLabels[column] || column}</th>
+                  ))}
+                </tr>
+              </thead>
+              <tbody>
+                {report.rows.map((row, i) => (
+                  <tr key={i} className="table-row">
+                    {columns.map((column) => (
+                      <td key={column} className="table-cell">{formatValue(column, row[column])}</td>
+                    ))}
+                  </tr>
+                ))}
+              </tbody>
+            </table>
+          </div>
+        )
+      )}
+    </Card>
+  );
+};
+
+export default CirculationReports;
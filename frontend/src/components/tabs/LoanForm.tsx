@@ -12,7 +12,6 @@ const LoanForm: React.FC<LoanFormProps> = ({ availableBooks, users, onSubmit, on
   const [formData, setFormData] = useState<CreateLoanRequest>({
     book_id: '',
     user_id: '',
-    days_to_return: 14, // 14 dias padrão
   });
   const [errors, setErrors] = useState<Record<string, string>>({});
 
@@ -27,10 +26,6 @@ const LoanForm: React.FC<LoanFormProps> = ({ availableBooks, users, onSubmit, on
       newErrors.user_id = 'Selecione um usuário';
     }
 
-    if (formData.days_to_return < 1 || formData.days_to_return > 365) {
-      newErrors.days_to_return = 'Dias para devolução deve estar entre 1 e 365';
-    }
-
     setErrors(newErrors);
     return Object.keys(newErrors).length === 0;
   };
@@ -49,13 +44,6 @@ const LoanForm: React.FC<LoanFormProps> = ({ availableBooks, users, onSubmit, on
     }
   };
 
-  const calculateReturnDate = () => {
-    const today = new Date();
-    const returnDate = new Date(today);
-    returnDate.setDate(today.getDate() + formData.days_to_return);
-    return returnDate.toLocaleDateString('pt-BR');
-  };
-
   return (
     <form onSubmit={handleSubmit} className="space-y-4">
       <div className="form-group">
@@ -98,23 +86,6 @@ const LoanForm: React.FC<LoanFormProps> = ({ availableBooks, users, onSubmit, on
         )}
       </div>
 
-      <div className="form-group">
-        <label className="form-label">Dias para Devolução</label>
-        <input
-          type="number"
-          value={formData.days_to_return}
-          onChange={(e) => handleChange('days_to_return', parseInt(e.target.value) || 0)}
-          className={`input-field ${errors.days_to_return ? 'border-red-500' : ''}`}
-          placeholder="14"
-          min="1"
-          max="365"
-        />
-        {errors.days_to_return && <p className="text-red-500 text-sm mt-1">{errors.days_to_return}</p>}
-        <p className="text-sm text-gray-500 mt-1">
-          Data de devolução prevista: {calculateReturnDate()}
-        </p>
-      </div>
-
       <div className="bg-gray-50 rounded-lg p-4">
         <h4 className="text-sm font-medium text-gray-700 mb-2">Resumo do Empréstimo</h4>
         <div className="space-y-1 text-sm text-gray-600">
@@ -134,7 +105,7 @@ const LoanForm: React.FC<LoanFormProps> = ({ availableBooks, users, onSubmit, on
             <strong>Data de Empréstimo:</strong> {new Date().toLocaleDateString('pt-BR')}
           </div>
           <div>
-            <strong>Data de Devolução:</strong> {calculateReturnDate()}
+            <strong>Data de Devolução:</strong> definida pela política da categoria do usuário
           </div>
         </div>
       </div>
@@ -8,7 +8,7 @@ import { Card, StatusBadge } from '../common/UI';
 import LoanForm from './LoanForm';
 
 const LoansTab: React.FC = () => {
-  const { loans, loading, error, fetchActiveLoans, fetchOverdueLoans, fetchLoans, createLoan, returnLoan } = useLoans();
+  const { loans, total, loading, error, fetchActiveLoans, fetchOverdueLoans, fetchLoans, createLoan, returnLoan } = useLoans();
   const { books } = useBooks();
   const { users } = useUsers();
   
@@ -110,7 +110,10 @@ const LoansTab: React.FC = () => {
 
       <Card>
         <div className="flex justify-between items-center mb-4">
-          <h2 className="text-xl font-semibold text-gray-900">Gerenciar Empréstimos</h2>
+          <div>
+            <h2 className="text-xl font-semibold text-gray-900">Gerenciar Empréstimos</h2>
+            <p className="text-sm text-gray-500">{total} empréstimo(s)</p>
+          </div>
           <div className="flex space-x-3">
             <select
               value={filter}
@@ -5,6 +5,7 @@ import { useUsers } from '../../hooks/useUsers';
 import { Alert, LoadingSpinner } from '../common/Modal';
 import { Card, StatusBadge } from '../common/UI';
 import { Loan } from '../../types';
+import CirculationReports from './CirculationReports';
 
 const ReportsTab: React.FC = () => {
   const { 
@@ -245,6 +246,8 @@ const ReportsTab: React.FC = () => {
         </div>
       </Card>
 
+      <CirculationReports />
+
       {/* Resultados do Relatório */}
       {(activeReport && !loading) && (
         <Card>
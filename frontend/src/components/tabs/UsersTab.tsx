@@ -6,7 +6,7 @@ import { Card } from '../common/UI';
 import UserForm from './UserForm';
 
 const UsersTab: React.FC = () => {
-  const { users, loading, error, createUser, updateUser, deleteUser } = useUsers();
+  const { users, total, loading, error, createUser, updateUser, deleteUser } = useUsers();
   const [showCreateModal, setShowCreateModal] = useState(false);
   const [editingUser, setEditingUser] = useState<User | null>(null);
   const [deleteConfirm, setDeleteConfirm] = useState<{ show: boolean; user: User | null }>({
@@ -67,7 +67,10 @@ const UsersTab: React.FC = () => {
 
       <Card>
         <div className="flex justify-between items-center mb-4">
-          <h2 className="text-xl font-semibold text-gray-900">Gerenciar Usuários</h2>
+          <div>
+            <h2 className="text-xl font-semibold text-gray-900">Gerenciar Usuários</h2>
+            <p className="text-sm text-gray-500">{total} usuário(s)</p>
+          </div>
           <button
             onClick={() => setShowCreateModal(true)}
             className="btn-primary"
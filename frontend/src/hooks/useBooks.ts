@@ -1,9 +1,11 @@
 import { useState, useEffect } from 'react';
 import { Book, CreateBookRequest } from '../types';
-import { booksApi } from '../services/api';
+import { booksApi, fetchAllPages } from '../services/api';
 
 export const useBooks = () => {
   const [books, setBooks] = useState<Book[]>([]);
+  // Total de registros da última listagem
+  const [total, setTotal] = useState(0);
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState<string | null>(null);
 
@@ -11,8 +13,9 @@ export const useBooks = () => {
     try {
       setLoading(true);
       setError(null);
-      const response = await booksApi.getAll();
-      setBooks(response.data || []);
+      const response = await fetchAllPages(booksApi.getAll);
+      setBooks(response.data);
+      setTotal(response.total);
     } catch (err: any) {
       setError(err.response?.data?.error || 'Erro ao buscar livros');
     } finally {
@@ -24,8 +27,9 @@ export const useBooks = () => {
     try {
       setLoading(true);
       setError(null);
-      const response = await booksApi.getAvailable();
-      setBooks(response.data || []);
+      const response = await fetchAllPages(booksApi.getAvailable);
+      setBooks(response.data);
+      setTotal(response.total);
     } catch (err: any) {
       setError(err.response?.data?.error || 'Erro ao buscar livros disponíveis');
     } finally {
@@ -33,11 +37,30 @@ export const useBooks = () => {
     }
   };
 
+  // Busca no acervo pelo servidor; sem texto, volta à listagem completa
+  const searchBooks = async (q: string) => {
+    if (!q.trim()) {
+      return fetchBooks();
+    }
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await fetchAllPages((params) => booksApi.search(q, params));
+      setBooks(response.data);
+      setTotal(response.total);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Erro ao buscar livros');
+    } finally {
+      setLoading(false);
+    }
+  };
+
   const createBook = async (data: CreateBookRequest) => {
     try {
       setError(null);
       const response = await booksApi.create(data);
       setBooks(prev => [...prev, response.data]);
+      setTotal(prev => prev + 1);
       return response.data;
     } catch (err: any) {
       const errorMessage = err.response?.data?.error || 'Erro ao criar livro';
@@ -64,6 +87,7 @@ export const useBooks = () => {
       setError(null);
       await booksApi.delete(id);
       setBooks(prev => prev.filter(book => book.id !== id));
+      setTotal(prev => prev - 1);
     } catch (err: any) {
       const errorMessage = err.response?.data?.error || 'Erro ao deletar livro';
       setError(errorMessage);
@@ -77,10 +101,12 @@ export const useBooks = () => {
 
   return {
     books,
+    total,
     loading,
     error,
     fetchBooks,
     fetchAvailableBooks,
+    searchBooks,
     createBook,
     updateBook,
     deleteBook,
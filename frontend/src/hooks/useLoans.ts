@@ -1,9 +1,11 @@
 import { useState, useEffect } from 'react';
-import { Loan, CreateLoanRequest } from '../types';
-import { loansApi } from '../services/api';
+import { Loan, CreateLoanRequest, StandingReason } from '../types';
+import { loansApi, fetchAllPages } from '../services/api';
 
 export const useLoans = () => {
   const [loans, setLoans] = useState<Loan[]>([]);
+  // Total de registros da última listagem
+  const [total, setTotal] = useState(0);
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState<string | null>(null);
 
@@ -11,8 +13,9 @@ export const useLoans = () => {
     try {
       setLoading(true);
       setError(null);
-      const response = await loansApi.getAll();
-      setLoans(response.data || []); 
+      const response = await fetchAllPages(loansApi.getAll);
+      setLoans(response.data);
+      setTotal(response.total);
     } catch (err: any) {
       setError(err.response?.data?.error || 'Erro ao buscar empréstimos');
     } finally {
@@ -24,8 +27,9 @@ export const useLoans = () => {
     try {
       setLoading(true);
       setError(null);
-      const response = await loansApi.getActive();
-      setLoans(response.data || []); 
+      const response = await fetchAllPages(loansApi.getActive);
+      setLoans(response.data);
+      setTotal(response.total);
     } catch (err: any) {
       setError(err.response?.data?.error || 'Erro ao buscar empréstimos ativos');
     } finally {
@@ -37,8 +41,9 @@ export const useLoans = () => {
     try {
       setLoading(true);
       setError(null);
-      const response = await loansApi.getOverdue();
-      setLoans(response.data || []); 
+      const response = await fetchAllPages(loansApi.getOverdue);
+      setLoans(response.data);
+      setTotal(response.total);
     } catch (err: any) {
       setError(err.response?.data?.error || 'Erro ao buscar empréstimos em atraso');
     } finally {
@@ -50,8 +55,9 @@ export const useLoans = () => {
     try {
       setLoading(true);
       setError(null);
-      const response = await loansApi.getByUser(userId);
-      setLoans(response.data || []); 
+      const response = await fetchAllPages((params) => loansApi.getByUser(userId, params));
+      setLoans(response.data);
+      setTotal(response.total);
     } catch (err: any) {
       setError(err.response?.data?.error || 'Erro ao buscar empréstimos do usuário');
     } finally {
@@ -63,8 +69,9 @@ export const useLoans = () => {
     try {
       setLoading(true);
       setError(null);
-      const response = await loansApi.getByBook(bookId);
-      setLoans(response.data || []); 
+      const response = await fetchAllPages((params) => loansApi.getByBook(bookId, params));
+      setLoans(response.data);
+      setTotal(response.total);
     } catch (err: any) {
       setError(err.response?.data?.error || 'Erro ao buscar empréstimos do livro');
     } finally {
@@ -77,9 +84,13 @@ export const useLoans = () => {
       setError(null);
       const response = await loansApi.create(data);
       setLoans(prev => [...prev, response.data]);
+      setTotal(prev => prev + 1);
       return response.data;
     } catch (err: any) {
-      const errorMessage = err.response?.data?.error || 'Erro ao criar empréstimo';
+      const reasons: StandingReason[] | undefined = err.response?.data?.reasons;
+      const errorMessage = reasons?.length
+        ? `Usuário impedido de pegar livros emprestados: ${reasons.map(r => r.message).join('; ')}`
+        : err.response?.data?.error || 'Erro ao criar empréstimo';
       setError(errorMessage);
       throw new Error(errorMessage);
     }
@@ -104,6 +115,7 @@ export const useLoans = () => {
 
   return {
     loans,
+    total,
     loading,
     error,
     fetchLoans,
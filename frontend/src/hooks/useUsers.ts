@@ -1,9 +1,11 @@
 import { useState, useEffect } from 'react';
 import { User, CreateUserRequest } from '../types';
-import { usersApi } from '../services/api';
+import { usersApi, fetchAllPages } from '../services/api';
 
 export const useUsers = () => {
   const [users, setUsers] = useState<User[]>([]);
+  // Total de registros da última listagem
+  const [total, setTotal] = useState(0);
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState<string | null>(null);
 
@@ -11,8 +13,9 @@ export const useUsers = () => {
     try {
       setLoading(true);
       setError(null);
-      const response = await usersApi.getAll();
-      setUsers(response.data || []);
+      const response = await fetchAllPages(usersApi.getAll);
+      setUsers(response.data);
+      setTotal(response.total);
     } catch (err: any) {
       setError(err.response?.data?.error || 'Erro ao buscar usuários');
     } finally {
@@ -25,6 +28,7 @@ export const useUsers = () => {
       setError(null);
       const response = await usersApi.create(data);
       setUsers(prev => [...prev, response.data]);
+      setTotal(prev => prev + 1);
       return response.data;
     } catch (err: any) {
       const errorMessage = err.response?.data?.error || 'Erro ao criar usuário';
@@ -51,6 +55,7 @@ export const useUsers = () => {
       setError(null);
       await usersApi.delete(id);
       setUsers(prev => prev.filter(user => user.id !== id));
+      setTotal(prev => prev - 1);
     } catch (err: any) {
       const errorMessage = err.response?.data?.error || 'Erro ao deletar usuário';
       setError(errorMessage);
@@ -64,6 +69,7 @@ export const useUsers = () => {
 
   return {
     users,
+    total,
     loading,
     error,
     fetchUsers,
@@ -1,5 +1,5 @@
-import axios from 'axios';
-import { Book, User, Loan, CreateBookRequest, CreateUserRequest, CreateLoanRequest } from '../types';
+import axios, { AxiosResponse } from 'axios';
+import { Book, User, Loan, PatronStanding, AuthTokens, LoginRequest, CreateBookRequest, CreateUserRequest, CreateLoanRequest, BookSearchResult, BookMetadata, ImportBookRequest, BookListParams, UserListParams, LoanListParams, ListParams, ImportParams, ImportReport, TransferFormat, Report, ReportName, ReportParams, JobInfo, JobRun, NotificationPreferences, UpdateNotificationPreferencesRequest, AuditEntry, AuditListParams, ListResult, MAX_PAGE_LIMIT } from '../types';
 
 const API_BASE_URL = import.meta.env.VITE_API_URL || 'http://localhost:8080/api';
 
@@ -7,34 +7,173 @@ const api = axios.create({
   baseURL: API_BASE_URL,
   headers: {
     'Content-Type': 'application/json',
+    // A interface é em português; as mensagens de erro da API seguem o idioma pedido
+    'Accept-Language': 'pt-BR',
   },
 });
 
+const TOKENS_KEY = 'library.auth';
+
+export const tokenStorage = {
+  get: (): AuthTokens | null => {
+    const raw = localStorage.getItem(TOKENS_KEY);
+    return raw ? (JSON.parse(raw) as AuthTokens) : null;
+  },
+  set: (tokens: AuthTokens) => localStorage.setItem(TOKENS_KEY, JSON.stringify(tokens)),
+  clear: () => localStorage.removeItem(TOKENS_KEY),
+};
+
+// Chamado quando a sessão expira e não pode ser renovada
+let onSessionExpired: () => void = () => {};
+export const setSessionExpiredHandler = (handler: () => void) => {
+  onSessionExpired = handler;
+};
+
+api.interceptors.request.use((config) => {
+  const tokens = tokenStorage.get();
+  if (tokens) {
+    config.headers.Authorization = `Bearer ${tokens.access_token}`;
+  }
+  return config;
+});
+
+// Renovações simultâneas compartilham a mesma requisição de refresh
+let refreshing: Promise<AuthTokens> | null = null;
+
+api.interceptors.response.use(
+  (response) => response,
+  async (error) => {
+    const original = error.config;
+    const tokens = tokenStorage.get();
+    const isAuthRoute = original?.url?.startsWith('/auth/');
+    if (error.response?.status !== 401 || !tokens || original._retry || isAuthRoute) {
+      if (error.response?.status === 401 && !isAuthRoute) {
+        tokenStorage.clear();
+        onSessionExpired();
+      }
+      return Promise.reject(error);
+    }
+
+    original._retry = true;
+    try {
+      refreshing = refreshing || authApi.refresh(tokens.refresh_token).then((r) => r.data);
+      const renewed = await refreshing;
+      tokenStorage.set(renewed);
+      original.headers.Authorization = `Bearer ${renewed.access_token}`;
+      return api(original);
+    } catch (refreshError) {
+      tokenStorage.clear();
+      onSessionExpired();
+      return Promise.reject(refreshError);
+    } finally {
+      refreshing = null;
+    }
+  },
+);
+
+// Busca todas as páginas de uma listagem, de MAX_PAGE_LIMIT em MAX_PAGE_LIMIT,
+// até reunir o total informado em X-Total-Count (ou a API não trazer mais nada)
+export const fetchAllPages = async <T>(
+  get: (page: ListParams) => Promise<AxiosResponse<T[]>>,
+): Promise<ListResult<T>> => {
+  const data: T[] = [];
+  let total = 0;
+  do {
+    const response = await get({ limit: MAX_PAGE_LIMIT, offset: data.length });
+    const page = response.data || [];
+    const header = Number(response.headers['x-total-count']);
+    total = Number.isFinite(header) ? header : data.length + page.length;
+    if (page.length === 0) break;
+    data.push(...page);
+  } while (data.length < total);
+  return { data, total: Math.max(total, data.length) };
+};
+
+const formatsByExtension: Record<string, TransferFormat> = { json: 'json', mrc: 'marc', xml: 'marcxml' };
+
+const contentTypes: Record<TransferFormat, string> = {
+  csv: 'text/csv',
+  json: 'application/json',
+  marc: 'application/marc',
+  marcxml: 'application/marcxml+xml',
+};
+
+// Envia o arquivo como corpo da requisição, no formato indicado ou deduzido da extensão
+const importFile = (path: string, file: File, { map, ...params }: ImportParams = {}) => {
+  const format = params.format || formatsByExtension[file.name.split('.').pop()?.toLowerCase() || ''] || 'csv';
+  const mapping = map && Object.entries(map).map(([field, column]) => `${field}:${column}`).join(',');
+  return api.post<ImportReport>(path, file, {
+    params: { ...params, format, ...(mapping ? { map: mapping } : {}) },
+    headers: { 'Content-Type': contentTypes[format] },
+  });
+};
+
+const exportFile = (path: string, format: TransferFormat = 'csv') =>
+  api.get<Blob>(path, { params: { format }, responseType: 'blob' });
+
+export const authApi = {
+  login: (data: LoginRequest) => api.post<AuthTokens>('/auth/login', data),
+  refresh: (refreshToken: string) => api.post<AuthTokens>('/auth/refresh', { refresh_token: refreshToken }),
+  logout: () => api.post('/auth/logout'),
+  me: () => api.get<User>('/auth/me'),
+};
+
 export const booksApi = {
-  getAll: () => api.get<Book[]>('/books'),
-  getById: (id: string) => api.get<Book>(`/books/${id}`),
-  getAvailable: () => api.get<Book[]>('/books/available'),
+  getAll: (params?: BookListParams) => api.get<Book[]>('/books', { params }),
+  getById: (id: string, params?: { include_deleted?: boolean }) => api.get<Book>(`/books/${id}`, { params }),
+  getByISBN: (isbn: string) => api.get<Book>(`/books/isbn/${encodeURIComponent(isbn)}`),
+  getAvailable: (params?: BookListParams) => api.get<Book[]>('/books/available', { params }),
+  search: (q: string, params?: ListParams) => api.get<BookSearchResult[]>('/books/search', { params: { ...params, q } }),
   create: (data: CreateBookRequest) => api.post<Book>('/books', data),
+  importByISBN: (data: ImportBookRequest) => api.post<Book>('/books/import-by-isbn', data),
+  getMetadata: (isbn: string) => api.get<BookMetadata>(`/books/metadata/${encodeURIComponent(isbn)}`),
   update: (id: string, data: Partial<CreateBookRequest>) => api.put<Book>(`/books/${id}`, data),
   delete: (id: string) => api.delete(`/books/${id}`),
+  restore: (id: string) => api.post<Book>(`/books/${id}/restore`),
+  importFile: (file: File, params?: ImportParams) => importFile('/books/import', file, params),
+  exportFile: (format?: TransferFormat) => exportFile('/books/export', format),
 };
 
 export const usersApi = {
-  getAll: () => api.get<User[]>('/users'),
-  getById: (id: string) => api.get<User>(`/users/${id}`),
+  getAll: (params?: UserListParams) => api.get<User[]>('/users', { params }),
+  getById: (id: string, params?: { include_deleted?: boolean }) => api.get<User>(`/users/${id}`, { params }),
   create: (data: CreateUserRequest) => api.post<User>('/users', data),
   update: (id: string, data: Partial<CreateUserRequest>) => api.put<User>(`/users/${id}`, data),
   delete: (id: string) => api.delete(`/users/${id}`),
+  restore: (id: string) => api.post<User>(`/users/${id}/restore`),
+  getStanding: (id: string) => api.get<PatronStanding>(`/users/${id}/standing`),
+  importFile: (file: File, params?: ImportParams) => importFile('/users/import', file, params),
+  exportFile: (format?: TransferFormat) => exportFile('/users/export', format),
+  getNotificationPreferences: (id: string) =>
+    api.get<NotificationPreferences>(`/users/${id}/notification-preferences`),
+  updateNotificationPreferences: (id: string, data: UpdateNotificationPreferencesRequest) =>
+    api.put<NotificationPreferences>(`/users/${id}/notification-preferences`, data),
 };
 
 export const loansApi = {
-  getAll: () => api.get<Loan[]>('/loans'),
-  getActive: () => api.get<Loan[]>('/loans/active'),
-  getOverdue: () => api.get<Loan[]>('/loans/overdue'),
-  getByUser: (userId: string) => api.get<Loan[]>(`/loans/user/${userId}`),
-  getByBook: (bookId: string) => api.get<Loan[]>(`/loans/book/${bookId}`),
+  getAll: (params?: LoanListParams) => api.get<Loan[]>('/loans', { params }),
+  getActive: (params?: LoanListParams) => api.get<Loan[]>('/loans/active', { params }),
+  getOverdue: (params?: LoanListParams) => api.get<Loan[]>('/loans/overdue', { params }),
+  getByUser: (userId: string, params?: ListParams) => api.get<Loan[]>(`/loans/user/${userId}`, { params }),
+  getByBook: (bookId: string, params?: ListParams) => api.get<Loan[]>(`/loans/book/${bookId}`, { params }),
   create: (data: CreateLoanRequest) => api.post<Loan>('/loans', data),
   returnLoan: (id: string) => api.put<Loan>(`/loans/${id}/return`),
 };
 
+export const reportsApi = {
+  get: (name: ReportName, params?: ReportParams) => api.get<Report>(`/reports/${name}`, { params }),
+  exportCsv: (name: ReportName, params?: ReportParams) =>
+    api.get<Blob>(`/reports/${name}`, { params: { ...params, format: 'csv' }, responseType: 'blob' }),
+};
+
+export const jobsApi = {
+  getAll: () => api.get<JobInfo[]>('/jobs'),
+  getRuns: (name: string, params?: ListParams) => api.get<JobRun[]>(`/jobs/${name}/runs`, { params }),
+  run: (name: string) => api.post<JobRun>(`/jobs/${name}/run`),
+};
+
+export const auditApi = {
+  getAll: (params?: AuditListParams) => api.get<AuditEntry[]>('/audit', { params }),
+};
+
 export default api;
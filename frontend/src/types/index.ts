@@ -3,25 +3,58 @@ export interface Book {
   title: string;
   author: string;
   year_published: number;
+  // ISBN-13 normalizado (só dígitos) e o ISBN como foi digitado
   isbn?: string;
+  isbn_display?: string;
+  publisher?: string;
+  cover_url?: string;
+  subjects?: string[];
+  material_type: string;
   is_available: boolean;
+  total_copies: number;
+  available_copies: number;
   created_at: string;
   updated_at: string;
+  // Preenchido quando o livro foi removido (só aparece com include_deleted)
+  deleted_at?: string;
 }
 
+export type ItemStatus = 'available' | 'on_loan' | 'on_hold' | 'lost' | 'damaged' | 'withdrawn';
+
+export interface Item {
+  id: string;
+  book_id: string;
+  book?: Book;
+  barcode: string;
+  shelf_location?: string;
+  status: ItemStatus;
+  created_at: string;
+  updated_at: string;
+}
+
+export type PatronCategory = 'student' | 'staff' | 'visitor';
+
+export type Role = 'admin' | 'librarian' | 'patron';
+
 export interface User {
   id: string;
   name: string;
   email: string;
   phone?: string;
+  category: PatronCategory;
+  role: Role;
   created_at: string;
   updated_at: string;
+  // Preenchido quando o usuário foi removido (só aparece com include_deleted)
+  deleted_at?: string;
 }
 
 export interface Loan {
   id: string;
+  item_id: string;
   book_id: string;
   user_id: string;
+  item?: Item;
   book?: Book;
   user?: User;
   loan_date: string;
@@ -33,28 +66,316 @@ export interface Loan {
   updated_at: string;
 }
 
+export type ReservationStatus = 'waiting' | 'ready' | 'fulfilled' | 'cancelled' | 'expired';
+
+export interface Reservation {
+  id: string;
+  book_id: string;
+  user_id: string;
+  item_id?: string;
+  book?: Book;
+  user?: User;
+  status: ReservationStatus;
+  position?: number;
+  ready_at?: string;
+  expires_at?: string;
+  created_at: string;
+  updated_at: string;
+}
+
 export interface CreateBookRequest {
   title: string;
   author: string;
   year_published: number;
   isbn?: string;
+  publisher?: string;
+  cover_url?: string;
+  subjects?: string[];
+  material_type?: string;
+  copies?: number;
+}
+
+// Dados bibliográficos de um ISBN obtidos das fontes externas (Open Library, Google Books...)
+export interface BookMetadata {
+  isbn: string;
+  title: string;
+  authors: string[];
+  year_published?: number;
+  publisher?: string;
+  cover_url?: string;
+  source: string;
+}
+
+export interface ImportBookRequest {
+  isbn: string;
+  material_type?: string;
+  copies?: number;
+}
+
+// Importação e exportação em lote; os formatos MARC só valem para livros
+export type TransferFormat = 'csv' | 'json' | 'marc' | 'marcxml';
+
+export interface ImportParams {
+  format?: TransferFormat;
+  dry_run?: boolean;
+  // Coluna do arquivo de cada campo, quando os nomes diferem (ex.: { title: 'Título' })
+  map?: Record<string, string>;
+}
+
+export interface ImportRowError {
+  row: number;
+  field?: string;
+  code: string;
+  message: string;
+}
+
+export interface ImportReport {
+  total: number;
+  created: number;
+  updated: number;
+  failed: number;
+  dry_run: boolean;
+  errors: ImportRowError[];
+  // Colunas (ou etiquetas MARC) ignoradas e em quantos registros apareceram
+  unmapped: Record<string, number>;
+}
+
+// Relatórios de circulação calculados pela API
+export type ReportName =
+  | 'loans-per-period'
+  | 'top-titles'
+  | 'top-authors'
+  | 'active-patrons'
+  | 'overdue-rate'
+  | 'loan-duration'
+  | 'never-borrowed';
+
+export type ReportInterval = 'day' | 'week' | 'month' | 'year';
+
+export interface ReportParams {
+  // Datas AAAA-MM-DD; o intervalo inclui from e exclui to
+  from?: string;
+  to?: string;
+  interval?: ReportInterval;
+  limit?: number;
+}
+
+export type ReportValue = string | number | null;
+
+export interface Report {
+  report: ReportName;
+  from?: string;
+  to?: string;
+  interval?: ReportInterval;
+  columns: string[];
+  rows: Record<string, ReportValue>[];
+}
+
+// Tarefas agendadas do backend
+export type JobStatus = 'running' | 'succeeded' | 'failed';
+
+export interface JobRun {
+  id: string;
+  job: string;
+  trigger: 'schedule' | 'manual';
+  scheduled_for?: string;
+  status: JobStatus;
+  started_at: string;
+  finished_at?: string;
+  result?: string;
+  error?: string;
+}
+
+export interface JobInfo {
+  name: string;
+  description: string;
+  schedule: string;
+  next_run?: string;
+  last_run?: JobRun;
+}
+
+// Preferências de aviso dos leitores
+export type NotificationEvent = 'due_soon' | 'overdue' | 'hold_ready' | 'loan_receipt';
+export type NotificationChannel = 'email' | 'sms' | 'webhook';
+
+export interface QuietHours {
+  start: string; // HH:MM
+  end: string;
+}
+
+export interface NotificationPreferences {
+  user_id: string;
+  events: NotificationEvent[];
+  channels: NotificationChannel[];
+  webhook_url?: string;
+  quiet_hours?: QuietHours;
+  digest: boolean;
+  updated_at?: string;
+}
+
+export type UpdateNotificationPreferencesRequest = Partial<Omit<NotificationPreferences, 'user_id' | 'updated_at'>>;
+
+// Log de auditoria
+export type AuditAction = 'create' | 'update' | 'delete' | 'restore' | 'purge';
+export type AuditEntity =
+  | 'book' | 'item' | 'user' | 'credential' | 'loan' | 'reservation'
+  | 'account_entry' | 'block' | 'policy' | 'notification_preferences';
+
+export interface AuditChange {
+  before: unknown;
+  after: unknown;
+}
+
+export interface AuditEntry {
+  id: string;
+  occurred_at: string;
+  actor_id?: string; // ausente nas alterações feitas pelo sistema
+  actor_name: string;
+  action: AuditAction;
+  entity: AuditEntity;
+  entity_id: string;
+  changes: Record<string, AuditChange>;
+  request_id?: string;
 }
 
 export interface CreateUserRequest {
   name: string;
   email: string;
   phone?: string;
+  category?: PatronCategory;
+  role?: Role;
 }
 
 export interface CreateLoanRequest {
   book_id: string;
+  item_id?: string;
+  user_id: string;
+}
+
+export interface CirculationPolicy {
+  id: string;
+  patron_category: PatronCategory;
+  material_type?: string;
+  loan_period_days: number;
+  max_loans: number;
+  max_renewals: number;
+  renewal_grace_days: number;
+  hold_pickup_days: number;
+  fine_daily_rate: string;
+  fine_max_per_item: string;
+  max_debt: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export type StandingReasonCode = 'overdue_loans' | 'debt_limit' | 'manual_block';
+
+export interface StandingReason {
+  code: StandingReasonCode;
+  message: string;
+  block_id?: string;
+}
+
+export interface PatronBlock {
+  id: string;
   user_id: string;
-  days_to_return: number;
+  reason: string;
+  expires_at?: string;
+  lifted_at?: string;
+  created_at: string;
+}
+
+export interface PatronStanding {
+  user_id: string;
+  can_borrow: boolean;
+  reasons: StandingReason[];
+  overdue_loans: number;
+  balance: string;
+  max_debt: string;
+  blocks: PatronBlock[];
+}
+
+export interface LoginRequest {
+  email: string;
+  password: string;
+}
+
+export interface AuthTokens {
+  access_token: string;
+  token_type: string;
+  expires_at: string;
+  refresh_token: string;
+  refresh_expires_at: string;
+  user: User;
 }
 
 export interface ApiResponse<T> {
   data?: T;
   error?: string;
+  reasons?: StandingReason[];
 }
 
 export type LoanStatus = 'active' | 'overdue' | 'returned';
+
+// Parâmetros comuns às listagens paginadas da API
+export interface ListParams {
+  limit?: number;
+  offset?: number;
+  // Campo de ordenação; prefixo "-" para ordem decrescente
+  sort?: string;
+}
+
+export interface BookListParams extends ListParams {
+  author?: string;
+  year_from?: number;
+  year_to?: number;
+  available?: boolean;
+  material_type?: string;
+  include_deleted?: boolean;
+}
+
+// Livro encontrado pela busca textual; os destaques são HTML escapado com os
+// termos entre <mark>
+export interface BookSearchResult extends Book {
+  score: number;
+  highlights: {
+    title: string;
+    author: string;
+    snippet: string;
+  };
+}
+
+export interface UserListParams extends ListParams {
+  name?: string;
+  email?: string;
+  category?: PatronCategory;
+  role?: Role;
+  include_deleted?: boolean;
+}
+
+export interface LoanListParams extends ListParams {
+  status?: LoanStatus;
+  user_id?: string;
+  book_id?: string;
+  loaned_from?: string;
+  loaned_to?: string;
+  due_from?: string;
+  due_to?: string;
+}
+
+export interface AuditListParams extends Omit<ListParams, 'sort'> {
+  entity?: AuditEntity;
+  id?: string;
+  actor?: string;
+  from?: string;
+  to?: string;
+}
+
+// Maior página aceita pela API
+export const MAX_PAGE_LIMIT = 200;
+
+// Todos os registros de uma listagem e o total informado pela API (X-Total-Count)
+export interface ListResult<T> {
+  data: T[];
+  total: number;
+}